@@ -61,6 +61,10 @@ func main() {
 	users := db.NewUserStore(database)
 	tokens := db.NewTokenStore(database)
 	configs := db.NewConfigStore(database)
+	ledgers := db.NewLedgerStore(database)
+	runs := db.NewSyncRunStore(database)
+	audit := db.NewAuditStore(database)
+	apiTokens := db.NewAPITokenStore(database)
 
 	resolver := perm.New(
 		os.Getenv("POWER_USER_EMAIL_LIST"),
@@ -75,18 +79,22 @@ func main() {
 		schedTickerMin = v
 	}
 
-	sched := scheduler.New(configs, tokens, oauthCfg, schedTickerMin)
+	sched := scheduler.New(configs, ledgers, runs, tokens, oauthCfg, schedTickerMin)
 	go sched.Start(ctx)
 
 	authH := handler.NewAuthHandler(users, tokens, secret, httpsOnly, oauthCfg, basePath)
 	dashH := handler.NewDashboardHandler(configs, users, tokens, oauthCfg, basePath)
-	cfgH := handler.NewConfigHandler(configs, tokens, oauthCfg, basePath)
+	cfgH := handler.NewConfigHandler(configs, ledgers, runs, audit, tokens, oauthCfg, basePath)
 	schemaH := handler.NewSchemaHandler(basePath)
+	apiTokenH := handler.NewAPITokenHandler(apiTokens, basePath)
 
 	loginPath := basePath + "/login"
 	requireAuth := func(h http.Handler) http.Handler {
 		return handler.RequireAuth(users, secret, resolver, loginPath, h)
 	}
+	requireAPIAuth := func(h http.HandlerFunc) http.Handler {
+		return handler.RequireAPIAuth(users, apiTokens, secret, resolver, h)
+	}
 
 	mux := http.NewServeMux()
 
@@ -116,6 +124,44 @@ func main() {
 	mux.Handle("POST "+basePath+"/configs/{id}/wipe", requireAuth(http.HandlerFunc(cfgH.HandleWipe)))
 	mux.Handle("POST "+basePath+"/configs/{id}/auto-sync", requireAuth(http.HandlerFunc(cfgH.HandleUpdateAutoSync)))
 	mux.Handle("GET "+basePath+"/configs/{id}/blockers", requireAuth(http.HandlerFunc(cfgH.HandleListBlockers)))
+	mux.Handle("GET "+basePath+"/configs/{id}/preview", requireAuth(http.HandlerFunc(cfgH.HandlePreview)))
+	mux.Handle("GET "+basePath+"/configs/{id}/drift", requireAuth(http.HandlerFunc(cfgH.HandleDrift)))
+	mux.Handle("GET "+basePath+"/configs/{id}/legacy", requireAuth(http.HandlerFunc(cfgH.HandleLegacyBlockers)))
+	mux.Handle("POST "+basePath+"/configs/{id}/legacy/{eventID}", requireAuth(http.HandlerFunc(cfgH.HandleResolveLegacyBlocker)))
+	mux.Handle("GET "+basePath+"/configs/{id}/runs", requireAuth(http.HandlerFunc(cfgH.HandleSyncRuns)))
+	mux.Handle("GET "+basePath+"/configs/{id}/runs/{runID}", requireAuth(http.HandlerFunc(cfgH.HandleSyncRun)))
+	mux.Handle("GET "+basePath+"/configs/{id}/audit", requireAuth(http.HandlerFunc(cfgH.HandleAudit)))
+	mux.Handle("GET "+basePath+"/configs/{id}/audit/{entryID}", requireAuth(http.HandlerFunc(cfgH.HandleAuditEntry)))
+	mux.Handle("POST "+basePath+"/configs/{id}/audit/{entryID}/restore", requireAuth(http.HandlerFunc(cfgH.HandleAuditRestore)))
+	mux.Handle("GET "+basePath+"/configs/{id}/revisions", requireAuth(http.HandlerFunc(cfgH.HandleRevisions)))
+	mux.Handle("GET "+basePath+"/configs/{id}/revisions/compare", requireAuth(http.HandlerFunc(cfgH.HandleRevisionCompare)))
+	mux.Handle("GET "+basePath+"/configs/{id}/revisions/{rev}", requireAuth(http.HandlerFunc(cfgH.HandleRevision)))
+	mux.Handle("POST "+basePath+"/configs/{id}/revisions/{rev}/rollback", requireAuth(http.HandlerFunc(cfgH.HandleRevisionRollback)))
+	mux.Handle("GET "+basePath+"/configs/{id}/holidays", requireAuth(http.HandlerFunc(cfgH.HandleHolidays)))
+
+	mux.Handle("GET "+basePath+"/tokens", requireAuth(http.HandlerFunc(apiTokenH.HandleList)))
+	mux.Handle("POST "+basePath+"/tokens", requireAuth(http.HandlerFunc(apiTokenH.HandleCreate)))
+	mux.Handle("POST "+basePath+"/tokens/{id}/delete", requireAuth(http.HandlerFunc(apiTokenH.HandleDelete)))
+
+	// JSON API, authenticated by API token or session cookie.
+	api := basePath + "/api/v1"
+	mux.HandleFunc("GET "+api+"/openapi.yaml", schemaH.HandleOpenAPI)
+	mux.Handle("GET "+api+"/configs", requireAPIAuth(cfgH.APIListConfigs))
+	mux.Handle("POST "+api+"/configs", requireAPIAuth(cfgH.APICreateConfig))
+	mux.Handle("GET "+api+"/configs/{id}", requireAPIAuth(cfgH.APIGetConfig))
+	mux.Handle("PATCH "+api+"/configs/{id}", requireAPIAuth(cfgH.APIUpdateConfig))
+	mux.Handle("DELETE "+api+"/configs/{id}", requireAPIAuth(cfgH.APIDeleteConfig))
+	mux.Handle("POST "+api+"/configs/{id}/validate", requireAPIAuth(cfgH.APIValidateConfig))
+	mux.Handle("POST "+api+"/configs/{id}/sync", requireAPIAuth(cfgH.APISyncConfig))
+	mux.Handle("POST "+api+"/configs/{id}/wipe", requireAPIAuth(cfgH.APIWipeConfig))
+	mux.Handle("GET "+api+"/configs/{id}/blockers", requireAPIAuth(cfgH.APIListBlockers))
+	mux.Handle("GET "+api+"/configs/{id}/preview", requireAPIAuth(cfgH.APIPreviewConfig))
+	// The freeze query is a deploy gate; FREEZE_API_PUBLIC=true serves it without authentication.
+	if os.Getenv("FREEZE_API_PUBLIC") == "true" {
+		mux.HandleFunc("GET "+api+"/configs/{id}/freeze", cfgH.APIFreeze)
+	} else {
+		mux.Handle("GET "+api+"/configs/{id}/freeze", requireAPIAuth(cfgH.APIFreeze))
+	}
 
 	// Schema reference (public — no auth needed, no secrets exposed)
 	mux.HandleFunc("GET "+basePath+"/schema/{version}", schemaH.HandleSchemaRef)
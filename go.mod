@@ -5,6 +5,7 @@ go 1.25.0
 require (
 	github.com/sirupsen/logrus v1.9.3
 	golang.org/x/oauth2 v0.30.0
+	golang.org/x/sync v0.20.0
 	google.golang.org/api v0.240.0
 	gopkg.in/yaml.v3 v3.0.1
 	modernc.org/sqlite v1.37.0
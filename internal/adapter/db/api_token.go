@@ -0,0 +1,106 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// apiTokenPrefix marks API tokens so they are recognisable in logs and secret scanners.
+const apiTokenPrefix = "tgifd_"
+
+// APIToken is a personal token that authenticates its user on the JSON API. Only a hash of
+// the token is stored; the token itself is shown once, when it is created.
+type APIToken struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+type APITokenStore struct{ db *sql.DB }
+
+func NewAPITokenStore(db *sql.DB) *APITokenStore { return &APITokenStore{db: db} }
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create issues a new token for the user and returns it together with its record.
+func (s *APITokenStore) Create(userID int64, name string) (string, *APIToken, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("generate api token: %w", err)
+	}
+	token := apiTokenPrefix + strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	t := &APIToken{UserID: userID, Name: name, CreatedAt: time.Now().UTC()}
+	res, err := s.db.Exec(`INSERT INTO api_tokens (user_id, name, token_hash, created_at) VALUES (?, ?, ?, ?)`,
+		userID, name, hashAPIToken(token), t.CreatedAt)
+	if err != nil {
+		return "", nil, fmt.Errorf("create api token: %w", err)
+	}
+	if t.ID, err = res.LastInsertId(); err != nil {
+		return "", nil, err
+	}
+	return token, t, nil
+}
+
+// Authenticate returns the ID of the user the token belongs to and records its use.
+// It returns false for unknown and revoked tokens.
+func (s *APITokenStore) Authenticate(token string) (int64, bool, error) {
+	if !strings.HasPrefix(token, apiTokenPrefix) {
+		return 0, false, nil
+	}
+	var id, userID int64
+	err := s.db.QueryRow(`SELECT id, user_id FROM api_tokens WHERE token_hash = ?`, hashAPIToken(token)).Scan(&id, &userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("look up api token: %w", err)
+	}
+	if _, err := s.db.Exec(`UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, time.Now().UTC(), id); err != nil {
+		return 0, false, fmt.Errorf("record api token use: %w", err)
+	}
+	return userID, true, nil
+}
+
+// ListByUser returns the user's tokens, newest first.
+func (s *APITokenStore) ListByUser(userID int64) ([]*APIToken, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, name, created_at, last_used_at
+		FROM api_tokens WHERE user_id = ? ORDER BY id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var out []*APIToken
+	for rows.Next() {
+		t := &APIToken{}
+		var lastUsedAt sql.NullTime
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.CreatedAt, &lastUsedAt); err != nil {
+			return nil, err
+		}
+		if lastUsedAt.Valid {
+			t.LastUsedAt = &lastUsedAt.Time
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// Delete revokes one of the user's tokens.
+func (s *APITokenStore) Delete(id, userID int64) error {
+	_, err := s.db.Exec(`DELETE FROM api_tokens WHERE id = ? AND user_id = ?`, id, userID)
+	return err
+}
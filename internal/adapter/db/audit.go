@@ -0,0 +1,110 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditConfigCreate   = "config.create"
+	AuditConfigUpdate   = "config.update"
+	AuditConfigDelete   = "config.delete"
+	AuditConfigRestore  = "config.restore"
+	AuditConfigRollback = "config.rollback"
+	AuditAutoSync       = "config.autoSync"
+	AuditValidate       = "config.validate"
+	AuditSync           = "config.sync"
+	AuditWipe           = "config.wipe"
+	AuditLegacyBlocker  = "config.legacyBlocker"
+)
+
+// AuditEntry records one action on a config. Config and user are copied in by name and
+// email, so the entry still reads correctly after either is deleted or renamed.
+type AuditEntry struct {
+	ID         int64
+	ConfigID   int64
+	ConfigName string
+	UserID     *int64 // Nil for actions the app took on its own.
+	UserEmail  string
+	Action     string // AuditConfigCreate, ...
+	Detail     string
+	// OldYAML and NewYAML are the config before and after the action, for actions that
+	// change it; empty otherwise.
+	OldYAML   string
+	NewYAML   string
+	CreatedAt time.Time
+}
+
+// AuditStore appends to and reads the audit log. There is deliberately no way to change
+// or remove an entry.
+type AuditStore struct{ db *sql.DB }
+
+func NewAuditStore(db *sql.DB) *AuditStore { return &AuditStore{db: db} }
+
+// Append writes the entry and sets its ID and CreatedAt.
+func (s *AuditStore) Append(e *AuditEntry) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	res, err := s.db.Exec(`
+		INSERT INTO audit_log (config_id, config_name, user_id, user_email, action, detail, old_yaml, new_yaml, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, e.ConfigID, e.ConfigName, e.UserID, e.UserEmail, e.Action, e.Detail, e.OldYAML, e.NewYAML, e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("append audit entry: %w", err)
+	}
+	e.ID, err = res.LastInsertId()
+	return err
+}
+
+const auditSelect = `
+	SELECT id, config_id, config_name, user_id, user_email, action, detail, old_yaml, new_yaml, created_at
+	FROM audit_log`
+
+func scanAuditEntry(row interface{ Scan(dest ...any) error }) (*AuditEntry, error) {
+	e := &AuditEntry{}
+	var userID sql.NullInt64
+	if err := row.Scan(&e.ID, &e.ConfigID, &e.ConfigName, &userID, &e.UserEmail, &e.Action, &e.Detail,
+		&e.OldYAML, &e.NewYAML, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		e.UserID = &userID.Int64
+	}
+	return e, nil
+}
+
+// ListForConfig returns a page of the config's entries, newest first, and the total number of entries.
+func (s *AuditStore) ListForConfig(configID int64, limit, offset int) ([]*AuditEntry, int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM audit_log WHERE config_id = ?`, configID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count audit entries: %w", err)
+	}
+	rows, err := s.db.Query(auditSelect+` WHERE config_id = ? ORDER BY id DESC LIMIT ? OFFSET ?`, configID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var out []*AuditEntry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, e)
+	}
+	return out, total, rows.Err()
+}
+
+// Get returns one of the config's entries, or nil if not found.
+func (s *AuditStore) Get(configID, entryID int64) (*AuditEntry, error) {
+	e, err := scanAuditEntry(s.db.QueryRow(auditSelect+` WHERE id = ? AND config_id = ?`, entryID, configID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
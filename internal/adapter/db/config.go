@@ -23,11 +23,13 @@ const (
 )
 
 type Config struct {
-	ID                 int64
-	UserID             int64
-	Name               string
-	SchemaVersion      string
-	ConfigYAML         string
+	ID            int64
+	UserID        int64
+	Name          string
+	SchemaVersion string
+	ConfigYAML    string
+	// Revision is the number of the config_revisions entry holding ConfigYAML.
+	Revision           int
 	Status             ConfigStatus
 	StatusMessage      string
 	CreatedAt          time.Time
@@ -36,6 +38,16 @@ type Config struct {
 	NextSyncAt         *time.Time
 	LastAutoSyncedAt   *time.Time
 	LastAutoSyncResult *string
+	// LastAutoSyncStatus is the last auto-sync's domain.SyncStatus; nil before the first
+	// run, and for runs from before statuses were recorded.
+	LastAutoSyncStatus *string
+	// LegacyBlockersAdoptedAt is when the config's blockers from before extended
+	// properties were tagged; nil until that one-time migration has run.
+	LegacyBlockersAdoptedAt *time.Time
+	// LegacyBlockersUnmatched is how many blockers from before extended properties the last
+	// migration attempt could not tell were the config's. The migration is not done while
+	// any are left; people adopt or delete them on the config's page.
+	LegacyBlockersUnmatched int
 }
 
 // ConfigWithAuthor enriches Config with the owning user's display info.
@@ -51,17 +63,21 @@ func NewConfigStore(db *sql.DB) *ConfigStore { return &ConfigStore{db: db} }
 
 const configSelectCols = `id, user_id, name, schema_version, config_yaml,
 	status, status_message, created_at, updated_at,
-	sync_schedule, next_sync_at, last_auto_synced_at, last_auto_sync_result`
+	sync_schedule, next_sync_at, last_auto_synced_at, last_auto_sync_result,
+	legacy_blockers_adopted_at, last_auto_sync_status, revision, legacy_blockers_unmatched`
 
 func scanConfig(row interface{ Scan(dest ...any) error }) (*Config, error) {
 	c := &Config{}
 	var nextSyncAt sql.NullTime
 	var lastAutoSyncedAt sql.NullTime
 	var lastAutoSyncResult sql.NullString
+	var legacyBlockersAdoptedAt sql.NullTime
+	var lastAutoSyncStatus sql.NullString
 	err := row.Scan(
 		&c.ID, &c.UserID, &c.Name, &c.SchemaVersion, &c.ConfigYAML,
 		&c.Status, &c.StatusMessage, &c.CreatedAt, &c.UpdatedAt,
 		&c.SyncSchedule, &nextSyncAt, &lastAutoSyncedAt, &lastAutoSyncResult,
+		&legacyBlockersAdoptedAt, &lastAutoSyncStatus, &c.Revision, &c.LegacyBlockersUnmatched,
 	)
 	if err != nil {
 		return nil, err
@@ -75,6 +91,12 @@ func scanConfig(row interface{ Scan(dest ...any) error }) (*Config, error) {
 	if lastAutoSyncResult.Valid {
 		c.LastAutoSyncResult = &lastAutoSyncResult.String
 	}
+	if legacyBlockersAdoptedAt.Valid {
+		c.LegacyBlockersAdoptedAt = &legacyBlockersAdoptedAt.Time
+	}
+	if lastAutoSyncStatus.Valid {
+		c.LastAutoSyncStatus = &lastAutoSyncStatus.String
+	}
 	return c, nil
 }
 
@@ -85,6 +107,7 @@ func (s *ConfigStore) ListAllWithAuthor(filterUserID *int64) ([]*ConfigWithAutho
 		SELECT c.id, c.user_id, c.name, c.schema_version, c.config_yaml,
 		       c.status, c.status_message, c.created_at, c.updated_at,
 		       c.sync_schedule, c.next_sync_at, c.last_auto_synced_at, c.last_auto_sync_result,
+		       c.legacy_blockers_adopted_at, c.last_auto_sync_status, c.revision, c.legacy_blockers_unmatched,
 		       u.email, u.display_name
 		FROM configs c
 		JOIN users u ON c.user_id = u.id`
@@ -107,10 +130,13 @@ func (s *ConfigStore) ListAllWithAuthor(filterUserID *int64) ([]*ConfigWithAutho
 		var nextSyncAt sql.NullTime
 		var lastAutoSyncedAt sql.NullTime
 		var lastAutoSyncResult sql.NullString
+		var legacyBlockersAdoptedAt sql.NullTime
+		var lastAutoSyncStatus sql.NullString
 		if err := rows.Scan(
 			&r.ID, &r.UserID, &r.Name, &r.SchemaVersion, &r.ConfigYAML,
 			&r.Status, &r.StatusMessage, &r.CreatedAt, &r.UpdatedAt,
 			&r.SyncSchedule, &nextSyncAt, &lastAutoSyncedAt, &lastAutoSyncResult,
+			&legacyBlockersAdoptedAt, &lastAutoSyncStatus, &r.Revision, &r.LegacyBlockersUnmatched,
 			&r.AuthorEmail, &r.AuthorDisplayName,
 		); err != nil {
 			return nil, err
@@ -124,20 +150,39 @@ func (s *ConfigStore) ListAllWithAuthor(filterUserID *int64) ([]*ConfigWithAutho
 		if lastAutoSyncResult.Valid {
 			r.LastAutoSyncResult = &lastAutoSyncResult.String
 		}
+		if legacyBlockersAdoptedAt.Valid {
+			r.LegacyBlockersAdoptedAt = &legacyBlockersAdoptedAt.Time
+		}
+		if lastAutoSyncStatus.Valid {
+			r.LastAutoSyncStatus = &lastAutoSyncStatus.String
+		}
 		out = append(out, r)
 	}
 	return out, rows.Err()
 }
 
+// Create stores a new config as revision 1.
 func (s *ConfigStore) Create(userID int64, name, schemaVersion, configYAML, syncSchedule string, nextSyncAt *time.Time) (*Config, error) {
-	res, err := s.db.Exec(`
-		INSERT INTO configs (user_id, name, schema_version, config_yaml, status, sync_schedule, next_sync_at)
-		VALUES (?, ?, ?, ?, 'pending', ?, ?)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("create config: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.Exec(`
+		INSERT INTO configs (user_id, name, schema_version, config_yaml, status, sync_schedule, next_sync_at, revision)
+		VALUES (?, ?, ?, ?, 'pending', ?, ?, 1)
 	`, userID, name, schemaVersion, configYAML, syncSchedule, nextSyncAt)
 	if err != nil {
 		return nil, fmt.Errorf("create config: %w", err)
 	}
 	id, _ := res.LastInsertId()
+	if err := insertRevision(tx, id, 1, schemaVersion, configYAML, userID, ""); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("create config: %w", err)
+	}
 	return s.Get(id, userID)
 }
 
@@ -193,14 +238,40 @@ func (s *ConfigStore) ListByUser(userID int64) ([]*Config, error) {
 	return configs, rows.Err()
 }
 
-func (s *ConfigStore) Update(id, userID int64, name, configYAML, syncSchedule string, nextSyncAt *time.Time) error {
-	_, err := s.db.Exec(`
+// Update saves the config owned by userID. If the YAML changed, it is stored as the next
+// revision, credited to editorID with the given note.
+func (s *ConfigStore) Update(id, userID, editorID int64, name, configYAML, syncSchedule string, nextSyncAt *time.Time, note string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("update config: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var schemaVersion, oldYAML string
+	var revision int
+	err = tx.QueryRow(`SELECT schema_version, config_yaml, revision FROM configs WHERE id = ? AND user_id = ?`, id, userID).
+		Scan(&schemaVersion, &oldYAML, &revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("update config: %w", err)
+	}
+	if configYAML != oldYAML {
+		revision++
+		if err := insertRevision(tx, id, revision, schemaVersion, configYAML, editorID, note); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`
 		UPDATE configs
-		SET name = ?, config_yaml = ?, status = 'pending', status_message = '',
+		SET name = ?, config_yaml = ?, revision = ?, status = 'pending', status_message = '',
 		    sync_schedule = ?, next_sync_at = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ? AND user_id = ?
-	`, name, configYAML, syncSchedule, nextSyncAt, id, userID)
-	return err
+	`, name, configYAML, revision, syncSchedule, nextSyncAt, id, userID); err != nil {
+		return fmt.Errorf("update config: %w", err)
+	}
+	return tx.Commit()
 }
 
 // UpdateSyncSchedule updates only the sync schedule fields without touching status or config YAML.
@@ -249,12 +320,25 @@ func (s *ConfigStore) ListDueForAutoSync(now time.Time) ([]*Config, error) {
 	return configs, rows.Err()
 }
 
-// RecordAutoSync stores the result of an auto-sync run and advances next_sync_at.
-func (s *ConfigStore) RecordAutoSync(id int64, syncedAt time.Time, result string, nextSyncAt time.Time) error {
+// RecordAutoSync stores the status and result message of an auto-sync run and advances next_sync_at.
+func (s *ConfigStore) RecordAutoSync(id int64, syncedAt time.Time, status, result string, nextSyncAt time.Time) error {
 	_, err := s.db.Exec(`
 		UPDATE configs
-		SET last_auto_synced_at = ?, last_auto_sync_result = ?, next_sync_at = ?
+		SET last_auto_synced_at = ?, last_auto_sync_status = ?, last_auto_sync_result = ?, next_sync_at = ?
 		WHERE id = ?
-	`, syncedAt, result, nextSyncAt, id)
+	`, syncedAt, status, result, nextSyncAt, id)
+	return err
+}
+
+// RecordLegacyBlockersAdopted marks the one-time adoption of the config's legacy blockers as done.
+func (s *ConfigStore) RecordLegacyBlockersAdopted(id int64, adoptedAt time.Time) error {
+	_, err := s.db.Exec(`UPDATE configs SET legacy_blockers_adopted_at = ?, legacy_blockers_unmatched = 0 WHERE id = ?`, adoptedAt, id)
+	return err
+}
+
+// RecordLegacyBlockersUnmatched records how many legacy blockers an adoption attempt left
+// because it could not tell they were the config's.
+func (s *ConfigStore) RecordLegacyBlockersUnmatched(id int64, unmatched int) error {
+	_, err := s.db.Exec(`UPDATE configs SET legacy_blockers_unmatched = ? WHERE id = ?`, unmatched, id)
 	return err
 }
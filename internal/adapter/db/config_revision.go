@@ -0,0 +1,95 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConfigRevision is one saved version of a config's YAML. Revisions are numbered from 1
+// per config and never change once written.
+type ConfigRevision struct {
+	ConfigID      int64
+	Revision      int
+	SchemaVersion string
+	// ConfigYAML is filled in by GetRevision only.
+	ConfigYAML string
+	// UserID is who saved the revision; nil if that user has been deleted.
+	UserID    *int64
+	UserEmail string
+	Note      string
+	CreatedAt time.Time
+}
+
+func insertRevision(tx *sql.Tx, configID int64, revision int, schemaVersion, configYAML string, userID int64, note string) error {
+	_, err := tx.Exec(`
+		INSERT INTO config_revisions (config_id, revision, schema_version, config_yaml, user_id, note)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, configID, revision, schemaVersion, configYAML, userID, note)
+	if err != nil {
+		return fmt.Errorf("insert config revision: %w", err)
+	}
+	return nil
+}
+
+func scanRevision(row interface{ Scan(dest ...any) error }, withYAML bool) (*ConfigRevision, error) {
+	rev := &ConfigRevision{}
+	var userID sql.NullInt64
+	dest := []any{&rev.ConfigID, &rev.Revision, &rev.SchemaVersion, &userID, &rev.UserEmail, &rev.Note, &rev.CreatedAt}
+	if withYAML {
+		dest = append(dest, &rev.ConfigYAML)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		rev.UserID = &userID.Int64
+	}
+	return rev, nil
+}
+
+const revisionSelectCols = `r.config_id, r.revision, r.schema_version, r.user_id, COALESCE(u.email, ''), r.note, r.created_at`
+
+// ListRevisions returns the config's revisions, newest first, without their YAML.
+func (s *ConfigStore) ListRevisions(configID int64) ([]*ConfigRevision, error) {
+	rows, err := s.db.Query(`
+		SELECT `+revisionSelectCols+`
+		FROM config_revisions r
+		LEFT JOIN users u ON r.user_id = u.id
+		WHERE r.config_id = ?
+		ORDER BY r.revision DESC
+	`, configID)
+	if err != nil {
+		return nil, fmt.Errorf("list config revisions: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var out []*ConfigRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rev)
+	}
+	return out, rows.Err()
+}
+
+// GetRevision returns one of the config's revisions with its YAML, or nil if not found.
+func (s *ConfigStore) GetRevision(configID int64, revision int) (*ConfigRevision, error) {
+	row := s.db.QueryRow(`
+		SELECT `+revisionSelectCols+`, r.config_yaml
+		FROM config_revisions r
+		LEFT JOIN users u ON r.user_id = u.id
+		WHERE r.config_id = ? AND r.revision = ?
+	`, configID, revision)
+	rev, err := scanRevision(row, true)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get config revision: %w", err)
+	}
+	return rev, nil
+}
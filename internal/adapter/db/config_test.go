@@ -1,9 +1,12 @@
 package db_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/domain"
 )
 
 func TestConfigStore_GetByID_CrossUser(t *testing.T) {
@@ -75,3 +78,382 @@ func TestConfigStore_GetByID_NotFound(t *testing.T) {
 		t.Fatal("expected nil for missing config")
 	}
 }
+
+func TestConfigStore_RecordLegacyBlockersAdopted(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	users := db.NewUserStore(database)
+	configs := db.NewConfigStore(database)
+
+	user, err := users.Upsert("google-1", "user1@example.com", "User One")
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	cfg, err := configs.Create(user.ID, "Test Config", "v1", "shared:\n  lookbackDays: 7\n", "none", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	if cfg.LegacyBlockersAdoptedAt != nil {
+		t.Fatalf("new config LegacyBlockersAdoptedAt = %v, want nil", cfg.LegacyBlockersAdoptedAt)
+	}
+
+	if err := configs.RecordLegacyBlockersUnmatched(cfg.ID, 2); err != nil {
+		t.Fatalf("RecordLegacyBlockersUnmatched error: %v", err)
+	}
+	if got, err := configs.GetByID(cfg.ID); err != nil || got.LegacyBlockersUnmatched != 2 || got.LegacyBlockersAdoptedAt != nil {
+		t.Fatalf("after an attempt with unmatched blockers = %+v, %v; want 2 unmatched and not adopted", got, err)
+	}
+
+	adoptedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	if err := configs.RecordLegacyBlockersAdopted(cfg.ID, adoptedAt); err != nil {
+		t.Fatalf("RecordLegacyBlockersAdopted error: %v", err)
+	}
+	got, err := configs.GetByID(cfg.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetByID = %v, %v", got, err)
+	}
+	if got.LegacyBlockersAdoptedAt == nil || !got.LegacyBlockersAdoptedAt.Equal(adoptedAt) || got.LegacyBlockersUnmatched != 0 {
+		t.Errorf("LegacyBlockersAdoptedAt = %v with %d unmatched, want %v with none", got.LegacyBlockersAdoptedAt, got.LegacyBlockersUnmatched, adoptedAt)
+	}
+}
+
+func TestConfigStore_RecordAutoSync(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	users := db.NewUserStore(database)
+	configs := db.NewConfigStore(database)
+
+	user, err := users.Upsert("google-1", "user1@example.com", "User One")
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	cfg, err := configs.Create(user.ID, "Test Config", "v1", "shared:\n  lookbackDays: 7\n", "weekly", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+
+	syncedAt := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	if err := configs.RecordAutoSync(cfg.ID, syncedAt, "partial", "⚠️ Sync incomplete", syncedAt.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("RecordAutoSync error: %v", err)
+	}
+	got, err := configs.GetByID(cfg.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetByID = %v, %v", got, err)
+	}
+	if got.LastAutoSyncStatus == nil || *got.LastAutoSyncStatus != "partial" {
+		t.Errorf("LastAutoSyncStatus = %v, want partial", got.LastAutoSyncStatus)
+	}
+	if got.LastAutoSyncResult == nil || *got.LastAutoSyncResult != "⚠️ Sync incomplete" {
+		t.Errorf("LastAutoSyncResult = %v, want the recorded message", got.LastAutoSyncResult)
+	}
+}
+
+func TestLedgerStore_Replace(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	users := db.NewUserStore(database)
+	configs := db.NewConfigStore(database)
+	ledgers := db.NewLedgerStore(database)
+
+	user, err := users.Upsert("google-1", "user1@example.com", "User One")
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	cfg, err := configs.Create(user.ID, "Test Config", "v1", "shared:\n  lookbackDays: 7\n", "none", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+
+	day := time.Date(2026, 8, 29, 0, 0, 0, 0, time.UTC)
+	ledger := ledgers.ForConfig(cfg.ID)
+	if err := ledger.RecordWrittenBlockers([]*domain.Blocker{
+		{EventID: "merged", Date: day, EndDate: day.AddDate(0, 0, 1), Summary: "Weekend", AllDay: true},
+		{EventID: "timed", Date: day.AddDate(0, 0, 3), Summary: "Freeze", StartTime: "08:00", EndTime: "20:00"},
+	}); err != nil {
+		t.Fatalf("RecordWrittenBlockers error: %v", err)
+	}
+	if err := ledger.RecordWrittenBlockers([]*domain.Blocker{
+		{EventID: "merged", Date: day, EndDate: day.AddDate(0, 0, 1), Summary: "Weekend", AllDay: true},
+	}); err != nil {
+		t.Fatalf("RecordWrittenBlockers error: %v", err)
+	}
+
+	got, err := ledger.WrittenBlockers()
+	if err != nil {
+		t.Fatalf("WrittenBlockers error: %v", err)
+	}
+	if len(got) != 1 || got[0].EventID != "merged" || !got[0].EndDate.Equal(day.AddDate(0, 0, 1)) || !got[0].AllDay {
+		t.Errorf("WrittenBlockers = %+v, want only the merged blocker", got)
+	}
+
+	// Deleting the config drops its ledger.
+	if err := configs.Delete(cfg.ID, user.ID); err != nil {
+		t.Fatalf("delete config: %v", err)
+	}
+	if got, _ := ledger.WrittenBlockers(); len(got) != 0 {
+		t.Errorf("ledger after config delete = %+v, want empty", got)
+	}
+}
+
+func TestSyncRunStore_RecordListGet(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	users := db.NewUserStore(database)
+	configs := db.NewConfigStore(database)
+	runs := db.NewSyncRunStore(database)
+
+	user, err := users.Upsert("google-1", "user1@example.com", "User One")
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	cfg, err := configs.Create(user.ID, "Test Config", "v1", "shared:\n  lookbackDays: 7\n", "none", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+
+	day := time.Date(2026, 8, 29, 0, 0, 0, 0, time.UTC)
+	outcome := &domain.SyncOutcome{
+		Status:     domain.SyncStatusPartial,
+		Message:    "Sync incomplete",
+		Result:     &domain.SyncResult{Created: 1, Failed: []domain.SyncFailure{{}}},
+		RangeStart: day,
+		RangeEnd:   day.AddDate(0, 0, 7),
+		Changes: []domain.ChangeOutcome{
+			{PlanChange: domain.PlanChange{Date: "2026-08-29", Action: domain.PlanActionAdd, Blocker: &domain.Blocker{Date: day, EndDate: day.AddDate(0, 0, 1), Summary: "Weekend", EventID: "new"}}, Result: domain.ChangeApplied},
+			{PlanChange: domain.PlanChange{Date: "2026-09-01", Action: domain.PlanActionAdd, Blocker: &domain.Blocker{Date: day.AddDate(0, 0, 3), Summary: "Freeze"}}, Result: domain.ChangeFailed, Err: errors.New("quota")},
+		},
+	}
+	first := db.NewSyncRun(cfg, db.SyncTriggerAuto, nil, day, day.Add(time.Second), domain.FailedSync("no token"))
+	second := db.NewSyncRun(cfg, db.SyncTriggerManual, &user.ID, day.Add(time.Hour), day.Add(time.Hour+time.Second), outcome)
+	for _, run := range []*db.SyncRun{first, second} {
+		if err := runs.Record(run); err != nil {
+			t.Fatalf("Record error: %v", err)
+		}
+	}
+
+	page, total, err := runs.List(cfg.ID, 1, 0)
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if total != 2 || len(page) != 1 || page[0].ID != second.ID || page[0].UserEmail != "user1@example.com" {
+		t.Fatalf("List = %+v (total %d), want the newest run of 2 by user1", page, total)
+	}
+	if page[0].Created != 1 || page[0].Failed != 1 || page[0].RangeStart != "2026-08-29" {
+		t.Errorf("run = %+v, want counts and range recorded", page[0])
+	}
+
+	got, err := runs.Get(cfg.ID, second.ID)
+	if err != nil || got == nil {
+		t.Fatalf("Get = %v, %v", got, err)
+	}
+	if len(got.Changes) != 2 || got.Changes[0].EndDate != "2026-08-30" || got.Changes[0].EventID != "new" || got.Changes[1].Error != "quota" {
+		t.Errorf("Changes = %+v, want the merged add and the failed add", got.Changes)
+	}
+	if other, err := runs.Get(cfg.ID+1, second.ID); err != nil || other != nil {
+		t.Errorf("Get for another config = %v, %v; want nil", other, err)
+	}
+	auto, err := runs.Get(cfg.ID, first.ID)
+	if err != nil || auto == nil || auto.UserID != nil || auto.RangeStart != "" || auto.Status != domain.SyncStatusFailed {
+		t.Errorf("auto run = %+v, %v; want a failed run without user or range", auto, err)
+	}
+}
+
+func TestAuditStore_AppendOnly(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	audit := db.NewAuditStore(database)
+	userID := int64(1)
+	for _, e := range []*db.AuditEntry{
+		{ConfigID: 5, ConfigName: "Team", UserID: &userID, UserEmail: "a@example.com", Action: db.AuditConfigCreate, NewYAML: "v: 1\n"},
+		{ConfigID: 5, ConfigName: "Team", UserID: &userID, UserEmail: "a@example.com", Action: db.AuditConfigUpdate, OldYAML: "v: 1\n", NewYAML: "v: 2\n"},
+		{ConfigID: 6, ConfigName: "Other", Action: db.AuditSync},
+	} {
+		if err := audit.Append(e); err != nil {
+			t.Fatalf("Append error: %v", err)
+		}
+	}
+
+	entries, total, err := audit.ListForConfig(5, 10, 0)
+	if err != nil {
+		t.Fatalf("ListForConfig error: %v", err)
+	}
+	if total != 2 || len(entries) != 2 || entries[0].Action != db.AuditConfigUpdate || entries[0].OldYAML != "v: 1\n" {
+		t.Fatalf("ListForConfig = %+v (total %d), want the update then the create", entries, total)
+	}
+	got, err := audit.Get(5, entries[1].ID)
+	if err != nil || got == nil || got.NewYAML != "v: 1\n" || got.UserID == nil || *got.UserID != userID {
+		t.Errorf("Get = %+v, %v; want the create entry", got, err)
+	}
+	if other, err := audit.Get(6, entries[1].ID); err != nil || other != nil {
+		t.Errorf("Get for another config = %v, %v; want nil", other, err)
+	}
+
+	if _, err := database.Exec(`UPDATE audit_log SET detail = 'tampered'`); err == nil {
+		t.Error("updating the audit log succeeded, want it rejected")
+	}
+	if _, err := database.Exec(`DELETE FROM audit_log`); err == nil {
+		t.Error("deleting from the audit log succeeded, want it rejected")
+	}
+}
+
+func TestConfigStore_Revisions(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	users := db.NewUserStore(database)
+	configs := db.NewConfigStore(database)
+	owner, err := users.Upsert("google-1", "owner@example.com", "Owner")
+	if err != nil {
+		t.Fatalf("upsert owner: %v", err)
+	}
+	editor, err := users.Upsert("google-2", "editor@example.com", "Editor")
+	if err != nil {
+		t.Fatalf("upsert editor: %v", err)
+	}
+
+	cfg, err := configs.Create(owner.ID, "Test Config", "v1", "v: 1\n", "none", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	if cfg.Revision != 1 {
+		t.Fatalf("Revision after Create = %d, want 1", cfg.Revision)
+	}
+
+	// Saving without a YAML change keeps the revision; a change adds one.
+	steps := []struct {
+		yaml string
+		want int
+	}{{"v: 1\n", 1}, {"v: 2\n", 2}, {"v: 1\n", 3}}
+	for _, step := range steps {
+		if err := configs.Update(cfg.ID, owner.ID, editor.ID, "Renamed", step.yaml, "none", nil, "note"); err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+		got, _ := configs.Get(cfg.ID, owner.ID)
+		if got.Revision != step.want || got.ConfigYAML != step.yaml {
+			t.Errorf("after saving %q: revision %d with %q, want revision %d", step.yaml, got.Revision, got.ConfigYAML, step.want)
+		}
+	}
+
+	revs, err := configs.ListRevisions(cfg.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions error: %v", err)
+	}
+	if len(revs) != 3 || revs[0].Revision != 3 || revs[2].Revision != 1 {
+		t.Fatalf("ListRevisions = %+v, want revisions 3, 2, 1", revs)
+	}
+	if revs[2].UserEmail != "owner@example.com" || revs[0].UserEmail != "editor@example.com" || revs[0].Note != "note" {
+		t.Errorf("revisions credited to %q and %q (note %q), want owner then editor", revs[2].UserEmail, revs[0].UserEmail, revs[0].Note)
+	}
+	rev, err := configs.GetRevision(cfg.ID, 2)
+	if err != nil || rev == nil || rev.ConfigYAML != "v: 2\n" {
+		t.Errorf("GetRevision(2) = %+v, %v; want the v: 2 YAML", rev, err)
+	}
+	if missing, err := configs.GetRevision(cfg.ID, 9); err != nil || missing != nil {
+		t.Errorf("GetRevision(9) = %v, %v; want nil", missing, err)
+	}
+	if _, err := database.Exec(`UPDATE config_revisions SET config_yaml = 'tampered'`); err == nil {
+		t.Error("updating a revision succeeded, want it rejected")
+	}
+
+	cfg, _ = configs.Get(cfg.ID, owner.ID)
+	runs := db.NewSyncRunStore(database)
+	now := time.Now().UTC()
+	run := db.NewSyncRun(cfg, db.SyncTriggerAuto, nil, now, now, domain.FailedSync("no token"))
+	if err := runs.Record(run); err != nil {
+		t.Fatalf("Record error: %v", err)
+	}
+	if got, _ := runs.Get(cfg.ID, run.ID); got == nil || got.ConfigRevision != 3 {
+		t.Errorf("recorded run = %+v, want config revision 3", got)
+	}
+}
+
+func TestOpen_BackfillsFirstRevision(t *testing.T) {
+	path := t.TempDir() + "/test.db"
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	// A config saved before revisions existed.
+	if _, err := database.Exec(`INSERT INTO users (id, google_id, email) VALUES (1, 'google-1', 'a@example.com')`); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	if _, err := database.Exec(`INSERT INTO configs (id, user_id, name, config_yaml, revision) VALUES (1, 1, 'Old', 'v: 1\n', 0)`); err != nil {
+		t.Fatalf("insert config: %v", err)
+	}
+	database.Close() //nolint:errcheck,gosec
+
+	database, err = db.Open(path)
+	if err != nil {
+		t.Fatalf("reopen db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	configs := db.NewConfigStore(database)
+	cfg, err := configs.Get(1, 1)
+	if err != nil || cfg == nil || cfg.Revision != 1 {
+		t.Fatalf("Get = %+v, %v; want revision 1", cfg, err)
+	}
+	rev, err := configs.GetRevision(1, 1)
+	if err != nil || rev == nil || rev.ConfigYAML != cfg.ConfigYAML {
+		t.Errorf("GetRevision(1) = %+v, %v; want the config's YAML", rev, err)
+	}
+}
+
+func TestAPITokenStore(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+
+	user, err := db.NewUserStore(database).Upsert("google-1", "a@example.com", "A")
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	tokens := db.NewAPITokenStore(database)
+	token, created, err := tokens.Create(user.ID, "pipeline")
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	if userID, ok, err := tokens.Authenticate(token); err != nil || !ok || userID != user.ID {
+		t.Errorf("Authenticate(token) = %d, %v, %v; want the user", userID, ok, err)
+	}
+	if _, ok, _ := tokens.Authenticate(token + "x"); ok {
+		t.Error("Authenticate accepted a wrong token")
+	}
+	list, err := tokens.ListByUser(user.ID)
+	if err != nil || len(list) != 1 || list[0].Name != "pipeline" || list[0].LastUsedAt == nil {
+		t.Fatalf("ListByUser = %+v, %v; want the used pipeline token", list, err)
+	}
+
+	if err := tokens.Delete(created.ID, user.ID); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if _, ok, _ := tokens.Authenticate(token); ok {
+		t.Error("Authenticate accepted a revoked token")
+	}
+}
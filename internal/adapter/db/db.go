@@ -70,9 +70,98 @@ func migrate(db *sql.DB) error {
 			sync_schedule         TEXT    NOT NULL DEFAULT 'none',
 			next_sync_at          DATETIME,
 			last_auto_synced_at   DATETIME,
-			last_auto_sync_result TEXT
+			last_auto_sync_result TEXT,
+			legacy_blockers_adopted_at DATETIME,
+			last_auto_sync_status TEXT,
+			revision              INTEGER NOT NULL DEFAULT 0,
+			legacy_blockers_unmatched INTEGER NOT NULL DEFAULT 0
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_configs_user_id ON configs(user_id)`,
+		`CREATE TABLE IF NOT EXISTS blocker_ledger (
+			config_id  INTEGER NOT NULL REFERENCES configs(id) ON DELETE CASCADE,
+			event_id   TEXT    NOT NULL,
+			date       TEXT    NOT NULL,
+			end_date   TEXT    NOT NULL DEFAULT '',
+			summary    TEXT    NOT NULL DEFAULT '',
+			start_time TEXT    NOT NULL DEFAULT '',
+			end_time   TEXT    NOT NULL DEFAULT '',
+			all_day    INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (config_id, event_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS sync_runs (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			config_id   INTEGER NOT NULL REFERENCES configs(id) ON DELETE CASCADE,
+			trigger     TEXT    NOT NULL,
+			user_id     INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			started_at  DATETIME NOT NULL,
+			finished_at DATETIME NOT NULL,
+			range_start TEXT    NOT NULL DEFAULT '',
+			range_end   TEXT    NOT NULL DEFAULT '',
+			status      TEXT    NOT NULL,
+			message     TEXT    NOT NULL DEFAULT '',
+			created     INTEGER NOT NULL DEFAULT 0,
+			updated     INTEGER NOT NULL DEFAULT 0,
+			deleted     INTEGER NOT NULL DEFAULT 0,
+			unchanged   INTEGER NOT NULL DEFAULT 0,
+			failed      INTEGER NOT NULL DEFAULT 0,
+			held_back   INTEGER NOT NULL DEFAULT 0,
+			config_revision INTEGER
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_runs_config_id ON sync_runs(config_id, started_at)`,
+		// audit_log is append-only: no foreign keys, so entries outlive the config and user
+		// they name, and triggers reject updates and deletes.
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			config_id   INTEGER NOT NULL,
+			config_name TEXT    NOT NULL DEFAULT '',
+			user_id     INTEGER,
+			user_email  TEXT    NOT NULL DEFAULT '',
+			action      TEXT    NOT NULL,
+			detail      TEXT    NOT NULL DEFAULT '',
+			old_yaml    TEXT    NOT NULL DEFAULT '',
+			new_yaml    TEXT    NOT NULL DEFAULT '',
+			created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_config_id ON audit_log(config_id, id)`,
+		`CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+		BEGIN SELECT RAISE(ABORT, 'audit_log is append-only'); END`,
+		`CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+		BEGIN SELECT RAISE(ABORT, 'audit_log is append-only'); END`,
+		`CREATE TABLE IF NOT EXISTS sync_run_changes (
+			run_id   INTEGER NOT NULL REFERENCES sync_runs(id) ON DELETE CASCADE,
+			seq      INTEGER NOT NULL,
+			date     TEXT    NOT NULL,
+			end_date TEXT    NOT NULL DEFAULT '',
+			action   TEXT    NOT NULL,
+			summary  TEXT    NOT NULL DEFAULT '',
+			event_id TEXT    NOT NULL DEFAULT '',
+			result   TEXT    NOT NULL,
+			error    TEXT    NOT NULL DEFAULT '',
+			PRIMARY KEY (run_id, seq)
+		)`,
+		// config_revisions keeps every saved version of a config's YAML; triggers make
+		// revisions immutable once written.
+		`CREATE TABLE IF NOT EXISTS config_revisions (
+			config_id      INTEGER NOT NULL REFERENCES configs(id) ON DELETE CASCADE,
+			revision       INTEGER NOT NULL,
+			schema_version TEXT    NOT NULL,
+			config_yaml    TEXT    NOT NULL,
+			user_id        INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			note           TEXT    NOT NULL DEFAULT '',
+			created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (config_id, revision)
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS config_revisions_no_update BEFORE UPDATE ON config_revisions
+		BEGIN SELECT RAISE(ABORT, 'config revisions are immutable'); END`,
+		// api_tokens holds personal tokens for the JSON API, stored as SHA-256 hashes.
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id      INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name         TEXT    NOT NULL,
+			token_hash   TEXT    UNIQUE NOT NULL,
+			created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_used_at DATETIME
+		)`,
 	}
 
 	for _, stmt := range stmts {
@@ -87,6 +176,11 @@ func migrate(db *sql.DB) error {
 		`ALTER TABLE configs ADD COLUMN next_sync_at DATETIME`,
 		`ALTER TABLE configs ADD COLUMN last_auto_synced_at DATETIME`,
 		`ALTER TABLE configs ADD COLUMN last_auto_sync_result TEXT`,
+		`ALTER TABLE configs ADD COLUMN legacy_blockers_adopted_at DATETIME`,
+		`ALTER TABLE configs ADD COLUMN last_auto_sync_status TEXT`,
+		`ALTER TABLE configs ADD COLUMN revision INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE sync_runs ADD COLUMN config_revision INTEGER`,
+		`ALTER TABLE configs ADD COLUMN legacy_blockers_unmatched INTEGER NOT NULL DEFAULT 0`,
 	}
 	for _, stmt := range alterStmts {
 		if _, err := tx.Exec(stmt); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
@@ -94,5 +188,17 @@ func migrate(db *sql.DB) error {
 		}
 	}
 
+	// Configs saved before revisions existed start at revision 1 with their current YAML.
+	backfillStmts := []string{
+		`INSERT INTO config_revisions (config_id, revision, schema_version, config_yaml, user_id, created_at)
+		SELECT id, 1, schema_version, config_yaml, user_id, updated_at FROM configs WHERE revision = 0`,
+		`UPDATE configs SET revision = 1 WHERE revision = 0`,
+	}
+	for _, stmt := range backfillStmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("migration statement failed: %w", err)
+		}
+	}
+
 	return tx.Commit()
 }
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+// LedgerStore keeps, per config, the blocker events the last sync left on the calendar as
+// they were written: summary and span. Syncs compare the calendar with it to find blockers
+// people changed by hand.
+type LedgerStore struct{ db *sql.DB }
+
+func NewLedgerStore(db *sql.DB) *LedgerStore { return &LedgerStore{db: db} }
+
+// ForConfig returns the ledger of one config.
+func (s *LedgerStore) ForConfig(configID int64) domain.BlockerLedger {
+	return &configLedger{store: s, configID: configID}
+}
+
+// List returns the config's ledger entries ordered by date.
+func (s *LedgerStore) List(configID int64) ([]*domain.Blocker, error) {
+	rows, err := s.db.Query(`
+		SELECT event_id, date, end_date, summary, start_time, end_time, all_day
+		FROM blocker_ledger WHERE config_id = ? ORDER BY date, event_id
+	`, configID)
+	if err != nil {
+		return nil, fmt.Errorf("list blocker ledger: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var out []*domain.Blocker
+	for rows.Next() {
+		b := &domain.Blocker{}
+		var date, endDate string
+		if err := rows.Scan(&b.EventID, &date, &endDate, &b.Summary, &b.StartTime, &b.EndTime, &b.AllDay); err != nil {
+			return nil, err
+		}
+		if b.Date, err = time.Parse(time.DateOnly, date); err != nil {
+			return nil, fmt.Errorf("blocker ledger entry %s: %w", b.EventID, err)
+		}
+		if endDate != "" {
+			if b.EndDate, err = time.Parse(time.DateOnly, endDate); err != nil {
+				return nil, fmt.Errorf("blocker ledger entry %s: %w", b.EventID, err)
+			}
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// Replace swaps the config's ledger for the given blockers.
+func (s *LedgerStore) Replace(configID int64, blockers []*domain.Blocker) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin ledger update: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.Exec(`DELETE FROM blocker_ledger WHERE config_id = ?`, configID); err != nil {
+		return fmt.Errorf("clear blocker ledger: %w", err)
+	}
+	for _, b := range blockers {
+		var endDate string
+		if !b.EndDate.IsZero() {
+			endDate = b.EndDate.Format(time.DateOnly)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO blocker_ledger (config_id, event_id, date, end_date, summary, start_time, end_time, all_day)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, configID, b.EventID, b.Date.Format(time.DateOnly), endDate, b.Summary, b.StartTime, b.EndTime, b.AllDay); err != nil {
+			return fmt.Errorf("record blocker %s: %w", b.EventID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+type configLedger struct {
+	store    *LedgerStore
+	configID int64
+}
+
+func (l *configLedger) WrittenBlockers() ([]*domain.Blocker, error) {
+	return l.store.List(l.configID)
+}
+
+func (l *configLedger) RecordWrittenBlockers(blockers []*domain.Blocker) error {
+	return l.store.Replace(l.configID, blockers)
+}
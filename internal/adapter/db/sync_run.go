@@ -0,0 +1,218 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+// What started a sync run.
+const (
+	SyncTriggerManual = "manual"
+	SyncTriggerAuto   = "auto"
+	SyncTriggerAPI    = "api"
+)
+
+// SyncRun is the record of one sync of a config.
+type SyncRun struct {
+	ID       int64
+	ConfigID int64
+	// ConfigRevision is the config revision the run synced; 0 for runs from before
+	// revisions were recorded.
+	ConfigRevision int
+	Trigger        string // SyncTriggerManual, SyncTriggerAuto or SyncTriggerAPI.
+	// UserID is who started the run; nil for auto-sync.
+	UserID     *int64
+	UserEmail  string // Filled in by List and Get.
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// RangeStart and RangeEnd are the synced dates, YYYY-MM-DD; empty when the run failed
+	// before planning.
+	RangeStart string
+	RangeEnd   string
+	Status     domain.SyncStatus
+	Message    string
+	Created    int
+	Updated    int
+	Deleted    int
+	Unchanged  int
+	Failed     int
+	HeldBack   int
+	// Changes is the per-date outcome, filled in by Get and NewSyncRun.
+	Changes []*SyncRunChange
+}
+
+// SyncRunChange is what a run did to one blocker.
+type SyncRunChange struct {
+	Date    string
+	EndDate string // Last day of a merged blocker.
+	Action  string // domain.PlanActionAdd, ...
+	Summary string
+	EventID string
+	Result  string // domain.ChangeApplied, ...
+	Error   string
+}
+
+// NewSyncRun builds the record of a sync run of cfg, as loaded when the run started, from its outcome.
+func NewSyncRun(cfg *Config, trigger string, userID *int64, startedAt, finishedAt time.Time, outcome *domain.SyncOutcome) *SyncRun {
+	run := &SyncRun{
+		ConfigID:       cfg.ID,
+		ConfigRevision: cfg.Revision,
+		Trigger:        trigger,
+		UserID:         userID,
+		StartedAt:      startedAt,
+		FinishedAt:     finishedAt,
+		Status:         outcome.Status,
+		Message:        outcome.Message,
+	}
+	if !outcome.RangeStart.IsZero() {
+		run.RangeStart = outcome.RangeStart.Format(time.DateOnly)
+		run.RangeEnd = outcome.RangeEnd.Format(time.DateOnly)
+	}
+	if r := outcome.Result; r != nil {
+		run.Created, run.Updated, run.Deleted, run.Unchanged = r.Created, r.Updated, r.Deleted, r.Unchanged
+		run.Failed, run.HeldBack = len(r.Failed), r.HeldBack
+	}
+	for _, c := range outcome.Changes {
+		change := &SyncRunChange{
+			Date:    string(c.Date),
+			Action:  c.Action,
+			Summary: c.Blocker.Summary,
+			EventID: c.EventID,
+			Result:  c.Result,
+		}
+		if c.EventID == "" {
+			change.EventID = c.Blocker.EventID
+		}
+		if last := domain.NewDateKey(c.Blocker.LastDate()); last != c.Date {
+			change.EndDate = string(last)
+		}
+		if c.Err != nil {
+			change.Error = c.Err.Error()
+		}
+		run.Changes = append(run.Changes, change)
+	}
+	return run
+}
+
+type SyncRunStore struct{ db *sql.DB }
+
+func NewSyncRunStore(db *sql.DB) *SyncRunStore { return &SyncRunStore{db: db} }
+
+// Record stores the run and its changes, and sets run.ID.
+func (s *SyncRunStore) Record(run *SyncRun) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin sync run: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.Exec(`
+		INSERT INTO sync_runs (config_id, config_revision, trigger, user_id, started_at, finished_at, range_start, range_end,
+		                       status, message, created, updated, deleted, unchanged, failed, held_back)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, run.ConfigID, run.ConfigRevision, run.Trigger, run.UserID, run.StartedAt.UTC(), run.FinishedAt.UTC(), run.RangeStart, run.RangeEnd,
+		run.Status, run.Message, run.Created, run.Updated, run.Deleted, run.Unchanged, run.Failed, run.HeldBack)
+	if err != nil {
+		return fmt.Errorf("insert sync run: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	for i, c := range run.Changes {
+		if _, err := tx.Exec(`
+			INSERT INTO sync_run_changes (run_id, seq, date, end_date, action, summary, event_id, result, error)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, id, i, c.Date, c.EndDate, c.Action, c.Summary, c.EventID, c.Result, c.Error); err != nil {
+			return fmt.Errorf("insert sync run change: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	run.ID = id
+	return nil
+}
+
+const syncRunSelect = `
+	SELECT r.id, r.config_id, COALESCE(r.config_revision, 0), r.trigger, r.user_id, COALESCE(u.email, ''), r.started_at, r.finished_at,
+	       r.range_start, r.range_end, r.status, r.message,
+	       r.created, r.updated, r.deleted, r.unchanged, r.failed, r.held_back
+	FROM sync_runs r
+	LEFT JOIN users u ON r.user_id = u.id`
+
+func scanSyncRun(row interface{ Scan(dest ...any) error }) (*SyncRun, error) {
+	r := &SyncRun{}
+	var userID sql.NullInt64
+	err := row.Scan(
+		&r.ID, &r.ConfigID, &r.ConfigRevision, &r.Trigger, &userID, &r.UserEmail, &r.StartedAt, &r.FinishedAt,
+		&r.RangeStart, &r.RangeEnd, &r.Status, &r.Message,
+		&r.Created, &r.Updated, &r.Deleted, &r.Unchanged, &r.Failed, &r.HeldBack,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		r.UserID = &userID.Int64
+	}
+	return r, nil
+}
+
+// List returns a page of the config's runs, newest first, and the total number of runs.
+func (s *SyncRunStore) List(configID int64, limit, offset int) ([]*SyncRun, int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM sync_runs WHERE config_id = ?`, configID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count sync runs: %w", err)
+	}
+	rows, err := s.db.Query(syncRunSelect+`
+		WHERE r.config_id = ?
+		ORDER BY r.started_at DESC, r.id DESC
+		LIMIT ? OFFSET ?
+	`, configID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list sync runs: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var runs []*SyncRun
+	for rows.Next() {
+		r, err := scanSyncRun(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, total, rows.Err()
+}
+
+// Get returns one of the config's runs with its changes, or nil if not found.
+func (s *SyncRunStore) Get(configID, runID int64) (*SyncRun, error) {
+	row := s.db.QueryRow(syncRunSelect+` WHERE r.id = ? AND r.config_id = ?`, runID, configID)
+	run, err := scanSyncRun(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT date, end_date, action, summary, event_id, result, error
+		FROM sync_run_changes WHERE run_id = ? ORDER BY seq
+	`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("list sync run changes: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	for rows.Next() {
+		c := &SyncRunChange{}
+		if err := rows.Scan(&c.Date, &c.EndDate, &c.Action, &c.Summary, &c.EventID, &c.Result, &c.Error); err != nil {
+			return nil, err
+		}
+		run.Changes = append(run.Changes, c)
+	}
+	return run, rows.Err()
+}
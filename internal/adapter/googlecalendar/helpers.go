@@ -2,46 +2,64 @@ package googlecalendar
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/nvat/tgifreezeday/internal/consts"
+	"github.com/nvat/tgifreezeday/internal/domain"
 	"google.golang.org/api/calendar/v3"
 )
 
-const (
-	// Holiday types in English (Google Calendar API returns these)
-	enPublicHoliday = "Public holiday"
-	enObservance    = "Observance"
-)
-
-var countryToCalendarID = map[string]string{
-	"jpn": "ja.japanese#holiday@group.v.calendar.google.com",
-	"vnm": "vi.vietnamese#holiday@group.v.calendar.google.com",
-}
-
+// GetHolidayCalendarID returns the Google public holiday calendar ID for a country in the registry.
 func GetHolidayCalendarID(country string) (string, error) {
-	v, ok := countryToCalendarID[country]
+	c, ok := consts.LookupCountry(country)
 	if !ok {
 		return "", fmt.Errorf("country %s is not supported. Supported countries: %v", country, consts.SupportedCountries)
 	}
-	return v, nil
+	return c.CalendarID, nil
 }
 
-// isPublicHoliday determines if an event represents an actual public holiday vs observance
-func (r *Repository) isPublicHoliday(event *calendar.Event) bool {
-	// Check event description for holiday type indicators
-	if event.Description != "" {
-		desc := event.Description
-
-		// "Public holiday" = actual non-working days
-		if desc == enPublicHoliday {
-			return true
-		}
-
-		// "Observance" = cultural/festival days (still working days)
-		if desc == enObservance {
-			return false
-		}
+// Holiday type phrases found in the description of Google holiday calendar events, per locale.
+// The description's first line is lower-cased and matched by prefix. Regional phrases are
+// checked first because several of them start with the public holiday phrase.
+var (
+	regionalHolidayPhrases = []string{
+		"common local holiday", "local holiday", "regional holiday", "public holiday in ",
+		"regionaler feiertag", "jour férié local", "festivo local", "festivo regional",
+		"地方の祝日", "ngày lễ địa phương",
 	}
+	observancePhrases = []string{
+		"observance", "season", "optional holiday", "restricted holiday",
+		"gedenktag", "journée", "observancia", "ricorrenza",
+		"記念日", "行事", "ngày kỷ niệm", "기념일", "纪念日", "紀念日",
+	}
+	publicHolidayPhrases = []string{
+		"public holiday", "national holiday", "bank holiday", "federal holiday", "gazetted holiday",
+		"gesetzlicher feiertag", "feiertag", "jour férié", "día festivo", "festivo", "giorno festivo",
+		"feestdag", "feriado",
+		"祝日", "国民の祝日", "休日", "ngày lễ", "ngày nghỉ lễ", "공휴일", "法定假日", "公众假期", "公眾假期",
+		"hari libur nasional", "cuti umum", "วันหยุดราชการ",
+	}
+)
 
-	return false
+// holidayKind classifies a holiday calendar event from its description, in any supported locale.
+func holidayKind(event *calendar.Event) domain.HolidayKind {
+	desc, _, _ := strings.Cut(event.Description, "\n")
+	desc = strings.ToLower(strings.TrimSpace(desc))
+	if desc == "" {
+		return domain.HolidayKindUnknown
+	}
+	hasPrefix := func(phrases []string) bool {
+		return slices.ContainsFunc(phrases, func(p string) bool { return strings.HasPrefix(desc, p) })
+	}
+	switch {
+	case hasPrefix(regionalHolidayPhrases):
+		return domain.HolidayKindRegional
+	case hasPrefix(observancePhrases):
+		return domain.HolidayKindObservance
+	case hasPrefix(publicHolidayPhrases):
+		return domain.HolidayKindPublic
+	default:
+		return domain.HolidayKindUnknown
+	}
 }
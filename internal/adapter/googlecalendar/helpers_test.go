@@ -0,0 +1,218 @@
+package googlecalendar
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+func TestHolidayKind(t *testing.T) {
+	tests := []struct {
+		description string
+		want        domain.HolidayKind
+	}{
+		{"Public holiday", domain.HolidayKindPublic},
+		{"Observance\nTo hide observances, go to Google Calendar Settings > Holidays in United States", domain.HolidayKindObservance},
+		{"Public holiday in Bavaria, Baden-Württemberg", domain.HolidayKindRegional},
+		{"Common local holiday", domain.HolidayKindRegional},
+		{"祝日", domain.HolidayKindPublic},
+		{"Ngày lễ", domain.HolidayKindPublic},
+		{"Gesetzlicher Feiertag", domain.HolidayKindPublic},
+		{"Season", domain.HolidayKindObservance},
+		{"", domain.HolidayKindUnknown},
+		{"Something else", domain.HolidayKindUnknown},
+	}
+	for _, tt := range tests {
+		if got := holidayKind(&calendar.Event{Description: tt.description}); got != tt.want {
+			t.Errorf("holidayKind(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestBlockerToEvent_Presentation(t *testing.T) {
+	r := &Repository{calendarTZ: time.UTC}
+	b := &domain.Blocker{Date: time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), Summary: "Freeze", AllDay: true, ColorID: "11", Visibility: "private"}
+
+	event, err := r.blockerToEvent(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ColorId != "11" || event.Visibility != "private" {
+		t.Errorf("event colour/visibility = %q/%q, want 11/private", event.ColorId, event.Visibility)
+	}
+	if got := r.eventToBlocker(event); !got.SameContent(b) {
+		t.Errorf("round trip = %+v, want %+v", got, b)
+	}
+
+	// Without a colour or visibility, a patch resets whatever an earlier template set.
+	b.ColorID, b.Visibility = "", ""
+	event, err = r.blockerToEvent(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Visibility != visibilityDefault || len(event.NullFields) != 1 || event.NullFields[0] != "ColorId" {
+		t.Errorf("event = visibility %q, NullFields %v; want default visibility and a null ColorId", event.Visibility, event.NullFields)
+	}
+	if got := r.eventToBlocker(event); !got.SameContent(b) {
+		t.Errorf("round trip = %+v, want %+v", got, b)
+	}
+}
+
+func TestBlockerToEvent_MultiDay(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	r := &Repository{calendarTZ: tokyo}
+	first := time.Date(2026, 4, 29, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2026, 5, 6, 0, 0, 0, 0, time.UTC)
+
+	allDay := &domain.Blocker{Date: first, EndDate: last, Summary: "Golden Week", AllDay: true}
+	event, err := r.blockerToEvent(allDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Start.Date != "2026-04-29" || event.End.Date != "2026-05-07" {
+		t.Errorf("all-day event = %s..%s, want 2026-04-29..2026-05-07 (end exclusive)", event.Start.Date, event.End.Date)
+	}
+	if got := r.eventToBlocker(event); !got.SameContent(allDay) {
+		t.Errorf("round trip = %+v, want %+v", got, allDay)
+	}
+
+	timed := &domain.Blocker{Date: first, EndDate: last, Summary: "Golden Week", StartTime: "08:00", EndTime: "20:00"}
+	event, err = r.blockerToEvent(timed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Start.DateTime != "2026-04-29T08:00:00+09:00" || event.End.DateTime != "2026-05-06T20:00:00+09:00" {
+		t.Errorf("timed event = %s..%s", event.Start.DateTime, event.End.DateTime)
+	}
+	if got := r.eventToBlocker(event); !got.SameContent(timed) {
+		t.Errorf("round trip = %+v, want %+v", got, timed)
+	}
+
+	// A single-day blocker reads back without an end date.
+	single := &domain.Blocker{Date: first, Summary: "Freeze", AllDay: true}
+	event, _ = r.blockerToEvent(single)
+	if got := r.eventToBlocker(event); !got.EndDate.IsZero() {
+		t.Errorf("single-day EndDate = %v, want zero", got.EndDate)
+	}
+}
+
+func TestBlockerToEvent_FreeBusyRemindersGuests(t *testing.T) {
+	r := &Repository{calendarTZ: time.UTC}
+	b := &domain.Blocker{
+		Date: time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), Summary: "Freeze", AllDay: true,
+		Free: true, HideOtherGuests: true,
+		Reminders: []domain.Reminder{{Method: "email", Minutes: 1440}, {Method: "popup", Minutes: 10}},
+	}
+	event, err := r.blockerToEvent(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Transparency != transparencyTransparent || event.GuestsCanSeeOtherGuests == nil || *event.GuestsCanSeeOtherGuests {
+		t.Errorf("event transparency %q, guestsCanSeeOtherGuests %v; want transparent and false", event.Transparency, event.GuestsCanSeeOtherGuests)
+	}
+	if event.Reminders.UseDefault || len(event.Reminders.Overrides) != 2 {
+		t.Errorf("event reminders = %+v, want two overrides", event.Reminders)
+	}
+	if got := r.eventToBlocker(event); !got.SameContent(b) {
+		t.Errorf("round trip = %+v, want %+v", got, b)
+	}
+
+	// No reminders at all is not the same as the calendar's default reminders.
+	b.Reminders = []domain.Reminder{}
+	event, _ = r.blockerToEvent(b)
+	got := r.eventToBlocker(event)
+	if !got.SameContent(b) {
+		t.Errorf("round trip without reminders = %+v, want %+v", got, b)
+	}
+	b.Reminders = nil
+	if got.SameContent(b) {
+		t.Error("a blocker without reminders should differ from one with the calendar's defaults")
+	}
+	event, _ = r.blockerToEvent(b)
+	if !event.Reminders.UseDefault {
+		t.Error("nil reminders should use the calendar's defaults")
+	}
+}
+
+func TestBlockerToEvent_Attendees(t *testing.T) {
+	r := &Repository{calendarTZ: time.UTC}
+	b := &domain.Blocker{
+		Date: time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), Summary: "Freeze", AllDay: true,
+		Attendees: []string{"oncall@example.com", "release@example.com"},
+	}
+	event, err := r.blockerToEvent(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(event.Attendees) != 2 || event.Attendees[0].Email != "oncall@example.com" {
+		t.Errorf("event attendees = %+v, want both guests", event.Attendees)
+	}
+
+	// Guests come back in whatever case and order the calendar stores them.
+	event.Attendees[0], event.Attendees[1] = event.Attendees[1], event.Attendees[0]
+	event.Attendees[0].Email = "Release@Example.com"
+	if got := r.eventToBlocker(event); !got.SameContent(b) {
+		t.Errorf("round trip = %+v, want %+v", got, b)
+	}
+
+	if got := sendUpdates(b); got != domain.SendUpdatesNone {
+		t.Errorf("sendUpdates() = %q, want %q by default", got, domain.SendUpdatesNone)
+	}
+}
+
+func TestBlockerToEvent_ExtendedProperties(t *testing.T) {
+	r := &Repository{calendarTZ: time.UTC, configID: 42}
+	b := &domain.Blocker{
+		Date: time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), Summary: "Freeze", AllDay: true,
+		Description: "Edited by hand", Rule: domain.DayRuleLabel(1),
+	}
+	event, err := r.blockerToEvent(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		propApp:    appID,
+		propConfig: "42",
+		propRule:   "todayIsFreezeDayIf #2",
+		propDate:   "2026-08-31",
+	}
+	if event.ExtendedProperties == nil || !reflect.DeepEqual(event.ExtendedProperties.Private, want) {
+		t.Errorf("private properties = %+v, want %v", event.ExtendedProperties, want)
+	}
+	// The description no longer decides whether an event is a blocker.
+	if !isBlockerEvent(event) || isLegacyBlockerEvent(event) {
+		t.Error("a tagged event without the signature should be a blocker, not a legacy one")
+	}
+	if got := r.eventToBlocker(event); got.Rule != b.Rule {
+		t.Errorf("round trip Rule = %q, want %q", got.Rule, b.Rule)
+	}
+
+	legacy := &calendar.Event{Description: "No deploys. " + domain.BlockerSignature}
+	if isBlockerEvent(legacy) || !isLegacyBlockerEvent(legacy) {
+		t.Error("an untagged event with the signature should be a legacy blocker")
+	}
+	if isLegacyBlockerEvent(&calendar.Event{Description: "Team offsite"}) {
+		t.Error("an untagged event without the signature is not a blocker")
+	}
+}
+
+func TestRepository_OwnsEvent(t *testing.T) {
+	day := &domain.Blocker{Date: time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), Summary: "Freeze", AllDay: true}
+	mine, _ := (&Repository{calendarTZ: time.UTC, configID: 1}).blockerToEvent(day)
+	theirs, _ := (&Repository{calendarTZ: time.UTC, configID: 2}).blockerToEvent(day)
+
+	r := &Repository{calendarTZ: time.UTC, configID: 1}
+	if !r.ownsEvent(mine) || r.ownsEvent(theirs) {
+		t.Error("config 1 should own its own blocker and not config 2's on the same calendar")
+	}
+	if got := r.ownerFilters(); !reflect.DeepEqual(got, []string{"tgifreezedayApp=tgifreezeday", "tgifreezedayConfig=1"}) {
+		t.Errorf("ownerFilters() = %v", got)
+	}
+	if r.ownsEvent(&calendar.Event{Description: domain.BlockerSignature}) {
+		t.Error("an untagged event should not be owned")
+	}
+}
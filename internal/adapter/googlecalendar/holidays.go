@@ -0,0 +1,113 @@
+package googlecalendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+// HolidayCalendar is a domain.HolidaySource backed by one of Google's public holiday calendars.
+type HolidayCalendar struct {
+	service     *calendar.Service
+	countryCode string
+	calendarID  string
+}
+
+// HolidayCalendar returns the public holiday source for a country, reusing the repository's
+// authorized calendar service.
+func (r *Repository) HolidayCalendar(countryCode string) (*HolidayCalendar, error) {
+	calendarID, err := GetHolidayCalendarID(countryCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday calendar ID: %w", err)
+	}
+	return &HolidayCalendar{
+		service:     r.service,
+		countryCode: countryCode,
+		calendarID:  calendarID,
+	}, nil
+}
+
+func (c *HolidayCalendar) Name() string {
+	return HolidayCalendarName(c.countryCode)
+}
+
+// HolidayCalendarName is the source name of the country's public holiday calendar.
+func HolidayCalendarName(countryCode string) string {
+	return "Google public holidays (" + countryCode + ")"
+}
+
+// HolidaysInRange returns the holiday calendar events in [rangeStart, rangeEnd), each with
+// its kind (public, regional, observance) so the config's policy can decide which count.
+func (c *HolidayCalendar) HolidaysInRange(rangeStart, rangeEnd time.Time) ([]domain.Holiday, error) {
+	events, err := c.fetchEvents(rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+
+	var holidays []domain.Holiday
+	for _, event := range events {
+		eventDate := extractEventDate(event)
+		if eventDate.IsZero() {
+			continue
+		}
+		desc, _, _ := strings.Cut(event.Description, "\n")
+		holidays = append(holidays, domain.Holiday{
+			Date:        eventDate,
+			Name:        event.Summary,
+			Country:     c.countryCode,
+			Kind:        holidayKind(event),
+			Description: strings.TrimSpace(desc),
+		})
+	}
+	return holidays, nil
+}
+
+// fetchEvents retrieves events from Google Calendar within the specified time range
+func (c *HolidayCalendar) fetchEvents(timeMin, timeMax time.Time) ([]*calendar.Event, error) {
+	call := c.service.Events.List(c.calendarID).
+		TimeMin(timeMin.Format(time.RFC3339)).
+		TimeMax(timeMax.Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime")
+
+	var all []*calendar.Event
+	for {
+		events, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve events from Google Calendar: %w", err)
+		}
+		all = append(all, events.Items...)
+		if events.NextPageToken == "" {
+			break
+		}
+		call = call.PageToken(events.NextPageToken)
+	}
+	return all, nil
+}
+
+// extractEventDate extracts the date from a Google Calendar event
+func extractEventDate(event *calendar.Event) time.Time {
+	if event.Start == nil {
+		return time.Time{}
+	}
+
+	// Try DateTime first (for timed events)
+	if event.Start.DateTime != "" {
+		if t, err := time.Parse(time.RFC3339, event.Start.DateTime); err == nil {
+			return t
+		}
+	}
+
+	// Try Date (for all-day events)
+	if event.Start.Date != "" {
+		if t, err := time.Parse("2006-01-02", event.Start.Date); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -2,35 +2,39 @@ package googlecalendar
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"golang.org/x/oauth2"
 	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 
 	"github.com/nvat/tgifreezeday/internal/domain"
-	"github.com/nvat/tgifreezeday/internal/helpers"
 )
 
-// Repository implements the CalendarRepository interface for Google Calendar
+// Repository implements the TGIFCalendarRepository interface for Google Calendar
 type Repository struct {
 	service         *calendar.Service
-	readCalendarID  string
 	writeCalendarID string
 	calendarTZ      *time.Location
+	configID        int64 // Config whose blockers are written; recorded on every event.
 }
 
 // NewRepositoryWithToken creates a Google Calendar repository using a stored OAuth token.
+// configID is recorded on the blockers it writes; pass 0 when no blockers are written.
 func NewRepositoryWithToken(
 	ctx context.Context,
 	oauthCfg *oauth2.Config,
 	token *oauth2.Token,
 	userID int64,
 	store TokenStore,
-	countryCode,
 	writeCalendarID string,
+	configID int64,
 ) (*Repository, error) {
 	httpClient := NewHTTPClientWithPersistence(ctx, oauthCfg, token, userID, store)
 
@@ -39,11 +43,6 @@ func NewRepositoryWithToken(
 		return nil, fmt.Errorf("failed to create calendar service: %w", err)
 	}
 
-	readCalendarID, err := GetHolidayCalendarID(countryCode)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get holiday calendar ID: %w", err)
-	}
-
 	cal, err := service.Calendars.Get(writeCalendarID).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get calendar info: %w", err)
@@ -56,57 +55,43 @@ func NewRepositoryWithToken(
 
 	return &Repository{
 		service:         service,
-		readCalendarID:  readCalendarID,
 		writeCalendarID: writeCalendarID,
 		calendarTZ:      calendarTZ,
+		configID:        configID,
 	}, nil
 }
 
-// GetFreezeDaysInRange fetches events for a range [start, end), and maps them to TGIFMapping domain model
-// rangeStart is inclusive, rangeEnd is exclusive
-func (r *Repository) GetFreezeDaysInRange(rangeStart, rangeEnd time.Time) (*domain.TGIFMapping, error) {
-	// Fetch events from Google Calendar
-	events, err := r.fetchEvents(rangeStart, rangeEnd)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch events: %w", err)
-	}
+// Private extended properties written on every blocker event. propApp marks the event as
+// a blocker; blockers are looked up by it rather than by the description, which people
+// can edit. The others record which config, rule and freeze day produced the event.
+const (
+	propApp    = "tgifreezedayApp"
+	propConfig = "tgifreezedayConfig"
+	propRule   = "tgifreezedayRule"
+	propDate   = "tgifreezedayDate"
 
-	// Create holiday map for quick lookup (only for actual public holidays)
-	holidayMap := make(map[string]bool)
-	for _, event := range events {
-		eventDate := r.extractEventDate(event)
-		if !eventDate.IsZero() {
-			isHoliday := r.isPublicHoliday(event)
-			dateKey := helpers.DateKey(eventDate)
+	appID = "tgifreezeday"
+)
 
-			if isHoliday {
-				holidayMap[dateKey] = true
-			}
-		}
+// blockerProperties returns the private extended properties for a blocker of the repository's config.
+func (r *Repository) blockerProperties(b *domain.Blocker) *calendar.EventExtendedProperties {
+	props := map[string]string{
+		propApp:  appID,
+		propDate: string(b.Key()),
 	}
-
-	tgifMapping := make(domain.TGIFMapping)
-	currDate := rangeStart
-	for currDate.Before(rangeEnd) {
-		dateKey := domain.NewDateKey(currDate)
-		isHoliday := holidayMap[string(dateKey)]
-
-		tgifDay := domain.NewTGIFDay(currDate, &tgifMapping, isHoliday)
-		tgifMapping[dateKey] = tgifDay
-
-		currDate = currDate.AddDate(0, 0, 1)
+	if r.configID != 0 {
+		props[propConfig] = strconv.FormatInt(r.configID, 10)
 	}
-	// CRITICAL: Fill month info for first/last business day calculations
-	// This is required for freeze day rules to work properly
-	tgifMapping.FillMonthInfo()
-
-	return &tgifMapping, nil
+	if b.Rule != "" {
+		props[propRule] = b.Rule
+	}
+	return &calendar.EventExtendedProperties{Private: props}
 }
 
-const (
-	defaultBlockerSignature   = "Managed by tgifreezeday, do not modify."
-	defaultBlockerDescription = "" + defaultBlockerSignature
-)
+// isBlockerEvent reports whether the event carries the blocker marker property.
+func isBlockerEvent(event *calendar.Event) bool {
+	return event.ExtendedProperties != nil && event.ExtendedProperties.Private[propApp] == appID
+}
 
 // WipeAllBlockersInMonth wipes all blockers in the month of the dateAnchor
 // Calls WipeAllBlockersInRange with the start and end of the month
@@ -118,7 +103,7 @@ func (r *Repository) WipeAllBlockersInMonth(dateAnchor time.Time) error {
 	return r.WipeAllBlockersInRange(startDate, endDate)
 }
 
-// get all events from writeCalendarId that has description containing defaultBlockerSignature
+// get all blocker events from writeCalendarId, found by their extended properties,
 // then delete them
 func (r *Repository) WipeAllBlockersInRange(startDate, endDate time.Time) error {
 	blockerEvents, err := r.fetchBlockerEvents(startDate, endDate)
@@ -126,33 +111,83 @@ func (r *Repository) WipeAllBlockersInRange(startDate, endDate time.Time) error
 		return fmt.Errorf("failed to retrieve blocker events: %w", err)
 	}
 
-	// Delete events sequentially - Google Calendar Go client doesn't support batch requests
-	for _, event := range blockerEvents {
-		deleteCall := r.service.Events.Delete(r.writeCalendarID, event.Id)
-		if err := deleteCall.Do(); err != nil {
-			return fmt.Errorf("failed to delete blocker event %s: %w", event.Id, err)
+	// The Google Calendar Go client doesn't support batch requests, so deletes run
+	// concurrently instead. A failed delete doesn't stop the others.
+	errs := domain.ForEachConcurrently(len(blockerEvents), domain.WriteConcurrency, func(i int) error {
+		return r.DeleteBlocker(blockerEvents[i].Id)
+	})
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
 		}
 	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d blocker event(s) not deleted: %w", len(failed), len(blockerEvents), errors.Join(failed...))
+	}
+	return nil
+}
 
+// DeleteBlocker deletes a single blocker event by its event ID. An event that is already
+// gone counts as deleted, which also covers a retry after a delete that went through.
+func (r *Repository) DeleteBlocker(eventID string) error {
+	err := withRetry(func(int) error {
+		return r.service.Events.Delete(r.writeCalendarID, eventID).Do()
+	})
+	if err != nil && !hasStatus(err, http.StatusNotFound, http.StatusGone) {
+		return fmt.Errorf("failed to delete blocker event %s: %w", eventID, err)
+	}
 	return nil
 }
 
-// fetchBlockerEvents retrieves all blocker events from the write calendar within the specified date range
+// fetchBlockerEvents retrieves the repository config's blocker events from the write calendar
+// within the specified date range. Blockers of other configs sharing the calendar are left out.
 func (r *Repository) fetchBlockerEvents(startDate, endDate time.Time) ([]*calendar.Event, error) {
-	call := r.service.Events.List(r.writeCalendarID).
+	call := r.listEvents(startDate, endDate).PrivateExtendedProperty(r.ownerFilters()...)
+	return collectEvents(call, r.ownsEvent)
+}
+
+// ownerFilters are the privateExtendedProperty constraints matching the config's blockers.
+func (r *Repository) ownerFilters() []string {
+	filters := []string{propApp + "=" + appID}
+	if r.configID != 0 {
+		filters = append(filters, propConfig+"="+strconv.FormatInt(r.configID, 10))
+	}
+	return filters
+}
+
+// ownsEvent reports whether the event is a blocker of the repository's config.
+func (r *Repository) ownsEvent(event *calendar.Event) bool {
+	if !isBlockerEvent(event) {
+		return false
+	}
+	return r.configID == 0 || event.ExtendedProperties.Private[propConfig] == strconv.FormatInt(r.configID, 10)
+}
+
+// listEvents lists the write calendar's events in the range, recurring events expanded.
+func (r *Repository) listEvents(startDate, endDate time.Time) *calendar.EventsListCall {
+	return r.service.Events.List(r.writeCalendarID).
 		TimeMin(startDate.Format(time.RFC3339)).
 		TimeMax(endDate.Format(time.RFC3339)).
 		SingleEvents(true).
 		OrderBy("startTime")
+}
 
+// collectEvents runs the list call through all pages and keeps the events keep accepts.
+func collectEvents(call *calendar.EventsListCall, keep func(*calendar.Event) bool) ([]*calendar.Event, error) {
 	var all []*calendar.Event
 	for {
-		events, err := call.Do()
+		var events *calendar.Events
+		err := withRetry(func(int) error {
+			var err error
+			events, err = call.Do()
+			return err
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve events from write calendar: %w", err)
 		}
 		for _, event := range events.Items {
-			if event.Description != "" && strings.Contains(event.Description, defaultBlockerDescription) {
+			if keep(event) {
 				all = append(all, event)
 			}
 		}
@@ -164,165 +199,265 @@ func (r *Repository) fetchBlockerEvents(startDate, endDate time.Time) ([]*calend
 	return all, nil
 }
 
-// BlockerEvent represents a blocker event for display purposes
-type BlockerEvent struct {
-	ID          string
-	Summary     string
-	Description string
-	Start       time.Time
-	End         time.Time
+// isLegacyBlockerEvent reports whether the event is a blocker written before blockers were
+// tagged with extended properties, which were recognised by the signature in their description.
+func isLegacyBlockerEvent(event *calendar.Event) bool {
+	return !isBlockerEvent(event) && strings.Contains(event.Description, domain.BlockerSignature)
 }
 
-// ListAllBlockersInRange lists all blocker events in the specified date range
-func (r *Repository) ListAllBlockersInRange(startDate, endDate time.Time) ([]*BlockerEvent, error) {
+// ListLegacyBlockersInRange lists the untagged, signature-marked blockers in the range,
+// written before blockers were tagged with extended properties. It writes nothing.
+func (r *Repository) ListLegacyBlockersInRange(startDate, endDate time.Time) ([]*domain.Blocker, error) {
+	legacy, err := collectEvents(r.listEvents(startDate, endDate), isLegacyBlockerEvent)
+	if err != nil {
+		return nil, err
+	}
+	blockers := make([]*domain.Blocker, 0, len(legacy))
+	for _, event := range legacy {
+		if b := r.eventToBlocker(event); b != nil {
+			blockers = append(blockers, b)
+		}
+	}
+	return blockers, nil
+}
+
+// AdoptLegacyBlockers tags the given legacy blockers, from ListLegacyBlockersInRange, as
+// blockers of the repository's config, so that they are found, updated and wiped like new
+// ones. Their rule is unknown and left unset. Returns the number of events adopted.
+func (r *Repository) AdoptLegacyBlockers(legacy []*domain.Blocker) (int, error) {
+	adopted := 0
+	for _, b := range legacy {
+		patch := &calendar.Event{ExtendedProperties: r.blockerProperties(b)}
+		err := withRetry(func(int) error {
+			_, err := r.service.Events.Patch(r.writeCalendarID, b.EventID, patch).SendUpdates(domain.SendUpdatesNone).Do()
+			return err
+		})
+		if err != nil {
+			return adopted, fmt.Errorf("failed to adopt legacy blocker event %s: %w", b.EventID, err)
+		}
+		adopted++
+	}
+	return adopted, nil
+}
+
+// ListBlockersInRange lists all blocker events in the specified date range.
+// Event dates and times are read in the write calendar's timezone.
+func (r *Repository) ListBlockersInRange(startDate, endDate time.Time) ([]*domain.Blocker, error) {
 	blockerEvents, err := r.fetchBlockerEvents(startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve blocker events: %w", err)
 	}
 
-	var blockers []*BlockerEvent
+	blockers := make([]*domain.Blocker, 0, len(blockerEvents))
 	for _, event := range blockerEvents {
-		blocker := &BlockerEvent{
-			ID:          event.Id,
-			Summary:     event.Summary,
-			Description: event.Description,
+		if b := r.eventToBlocker(event); b != nil {
+			blockers = append(blockers, b)
 		}
+	}
+	return blockers, nil
+}
 
-		// Parse start time
-		if event.Start != nil {
-			if event.Start.DateTime != "" {
-				if startTime, err := time.Parse(time.RFC3339, event.Start.DateTime); err == nil {
-					blocker.Start = startTime
-				}
-			} else if event.Start.Date != "" {
-				if startTime, err := time.Parse("2006-01-02", event.Start.Date); err == nil {
-					blocker.Start = startTime
-				}
-			}
-		}
+// eventToBlocker converts a calendar event into a Blocker. Returns nil when the event
+// has no parseable start.
+func (r *Repository) eventToBlocker(event *calendar.Event) *domain.Blocker {
+	if event.Start == nil {
+		return nil
+	}
+	b := &domain.Blocker{
+		EventID:     event.Id,
+		Summary:     event.Summary,
+		Description: event.Description,
+	}
+	if event.ExtendedProperties != nil {
+		b.Rule = event.ExtendedProperties.Private[propRule]
+	}
+	presentationFromEvent(event, b)
 
-		// Parse end time
+	if event.Start.Date != "" {
+		startDate, err := time.Parse("2006-01-02", event.Start.Date)
+		if err != nil {
+			return nil
+		}
+		b.Date = startDate
+		b.AllDay = true
+		// The end date is exclusive: a one-day event ends the day after it starts.
 		if event.End != nil {
-			if event.End.DateTime != "" {
-				if endTime, err := time.Parse(time.RFC3339, event.End.DateTime); err == nil {
-					blocker.End = endTime
-				}
-			} else if event.End.Date != "" {
-				if endTime, err := time.Parse("2006-01-02", event.End.Date); err == nil {
-					blocker.End = endTime
-				}
+			if endDate, err := time.Parse("2006-01-02", event.End.Date); err == nil && endDate.AddDate(0, 0, -1).After(startDate) {
+				b.EndDate = endDate.AddDate(0, 0, -1)
 			}
 		}
-
-		blockers = append(blockers, blocker)
+		return b
 	}
 
-	return blockers, nil
+	startTime, err := time.Parse(time.RFC3339, event.Start.DateTime)
+	if err != nil {
+		return nil
+	}
+	startTime = startTime.In(r.calendarTZ)
+	b.Date = time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, time.UTC)
+	b.StartTime = startTime.Format("15:04")
+	if event.End != nil {
+		if endTime, err := time.Parse(time.RFC3339, event.End.DateTime); err == nil {
+			endTime = endTime.In(r.calendarTZ)
+			b.EndTime = endTime.Format("15:04")
+			if endDate := time.Date(endTime.Year(), endTime.Month(), endTime.Day(), 0, 0, 0, 0, time.UTC); endDate.After(b.Date) {
+				b.EndDate = endDate
+			}
+		}
+	}
+	return b
 }
 
-func (r *Repository) WriteBlockerOnDate(date time.Time, summary, description, startTime, endTime string, allDay bool) error {
-	// Combine custom description with signature for identification.
-	var finalDescription string
-	if description == "" || description == defaultBlockerSignature {
-		finalDescription = defaultBlockerSignature
-	} else {
-		finalDescription = description + defaultBlockerSignature
-	}
-
-	if allDay {
-		calendarDate := date.In(r.calendarTZ)
-		dateStr := calendarDate.Format("2006-01-02")
-		nextDateStr := calendarDate.AddDate(0, 0, 1).Format("2006-01-02")
-		call := r.service.Events.Insert(r.writeCalendarID, &calendar.Event{
-			Summary:     summary,
-			Start:       &calendar.EventDateTime{Date: dateStr},
-			End:         &calendar.EventDateTime{Date: nextDateStr},
-			Description: finalDescription,
-		})
-		if _, err := call.Do(); err != nil {
-			return fmt.Errorf("failed to write all-day blocker on date: %w", err)
+// WriteBlockerOnDate inserts a new blocker event and sets b.EventID to its ID.
+func (r *Repository) WriteBlockerOnDate(b *domain.Blocker) error {
+	event, err := r.blockerToEvent(b)
+	if err != nil {
+		return err
+	}
+	event.Id = newEventID()
+	err = withRetry(func(attempt int) error {
+		_, err := r.service.Events.Insert(r.writeCalendarID, event).SendUpdates(sendUpdates(b)).Do()
+		if attempt > 1 && hasStatus(err, http.StatusConflict) {
+			return nil // An earlier attempt created the event.
 		}
-		return nil
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write blocker on date: %w", err)
 	}
+	b.EventID = event.Id
+	return nil
+}
 
-	// Convert to calendar timezone for proper display.
-	calendarDate := date.In(r.calendarTZ)
-
-	parsedStart, err := time.Parse("15:04", startTime)
+// PatchBlocker overwrites the content of an existing blocker event, keeping its event ID.
+func (r *Repository) PatchBlocker(eventID string, b *domain.Blocker) error {
+	event, err := r.blockerToEvent(b)
 	if err != nil {
-		return fmt.Errorf("invalid startTime %q: %w", startTime, err)
+		return err
 	}
-	parsedEnd, err := time.Parse("15:04", endTime)
+	err = withRetry(func(int) error {
+		_, err := r.service.Events.Patch(r.writeCalendarID, eventID, event).SendUpdates(sendUpdates(b)).Do()
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("invalid endTime %q: %w", endTime, err)
+		return fmt.Errorf("failed to patch blocker event %s: %w", eventID, err)
 	}
+	return nil
+}
 
-	startDateTime := time.Date(
-		calendarDate.Year(), calendarDate.Month(), calendarDate.Day(),
-		parsedStart.Hour(), parsedStart.Minute(), 0, 0,
-		r.calendarTZ)
-
-	endDateTime := time.Date(
-		calendarDate.Year(), calendarDate.Month(), calendarDate.Day(),
-		parsedEnd.Hour(), parsedEnd.Minute(), 0, 0,
-		r.calendarTZ)
+// sendUpdates returns the blocker's notification policy; nobody is notified unless asked.
+func sendUpdates(b *domain.Blocker) string {
+	if b.SendUpdates == "" {
+		return domain.SendUpdatesNone
+	}
+	return b.SendUpdates
+}
 
-	call := r.service.Events.Insert(r.writeCalendarID, &calendar.Event{
-		Summary:     summary,
-		Start:       &calendar.EventDateTime{DateTime: startDateTime.Format(time.RFC3339)},
-		End:         &calendar.EventDateTime{DateTime: endDateTime.Format(time.RFC3339)},
-		Description: finalDescription,
-	})
+// blockerToEvent builds the calendar event for a blocker. The blocker is placed on its
+// freeze days' calendar dates in the write calendar's timezone; a merged timed blocker runs
+// from the start time on its first day to the end time on its last day.
+func (r *Repository) blockerToEvent(b *domain.Blocker) (*calendar.Event, error) {
+	year, month, day := b.Date.Date()
+	lastYear, lastMonth, lastDay := b.LastDate().Date()
+
+	if b.AllDay {
+		calendarDate := time.Date(year, month, day, 0, 0, 0, 0, r.calendarTZ)
+		lastCalendarDate := time.Date(lastYear, lastMonth, lastDay, 0, 0, 0, 0, r.calendarTZ)
+		return withPresentation(&calendar.Event{
+			Summary:            b.Summary,
+			Start:              &calendar.EventDateTime{Date: calendarDate.Format("2006-01-02")},
+			End:                &calendar.EventDateTime{Date: lastCalendarDate.AddDate(0, 0, 1).Format("2006-01-02")},
+			Description:        b.Description,
+			ExtendedProperties: r.blockerProperties(b),
+		}, b), nil
+	}
 
-	if _, err := call.Do(); err != nil {
-		return fmt.Errorf("failed to write default blocker on date: %w", err)
+	parsedStart, err := time.Parse("15:04", b.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startTime %q: %w", b.StartTime, err)
+	}
+	parsedEnd, err := time.Parse("15:04", b.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endTime %q: %w", b.EndTime, err)
 	}
 
-	return nil
+	startDateTime := time.Date(year, month, day, parsedStart.Hour(), parsedStart.Minute(), 0, 0, r.calendarTZ)
+	endDateTime := time.Date(lastYear, lastMonth, lastDay, parsedEnd.Hour(), parsedEnd.Minute(), 0, 0, r.calendarTZ)
+
+	return withPresentation(&calendar.Event{
+		Summary:            b.Summary,
+		Start:              &calendar.EventDateTime{DateTime: startDateTime.Format(time.RFC3339)},
+		End:                &calendar.EventDateTime{DateTime: endDateTime.Format(time.RFC3339)},
+		Description:        b.Description,
+		ExtendedProperties: r.blockerProperties(b),
+	}, b), nil
 }
 
-// fetchEvents retrieves events from Google Calendar within the specified time range
-func (r *Repository) fetchEvents(timeMin, timeMax time.Time) ([]*calendar.Event, error) {
-	call := r.service.Events.List(r.readCalendarID).
-		TimeMin(timeMin.Format(time.RFC3339)).
-		TimeMax(timeMax.Format(time.RFC3339)).
-		SingleEvents(true).
-		OrderBy("startTime")
+// visibilityDefault is the calendar's own visibility value; blockers leave it empty.
+const visibilityDefault = "default"
 
-	var all []*calendar.Event
-	for {
-		events, err := call.Do()
-		if err != nil {
-			return nil, fmt.Errorf("failed to retrieve events from Google Calendar: %w", err)
-		}
-		all = append(all, events.Items...)
-		if events.NextPageToken == "" {
-			break
+// Event transparency values: opaque blocks free/busy, transparent does not.
+const (
+	transparencyOpaque      = "opaque"
+	transparencyTransparent = "transparent"
+)
+
+// withPresentation sets the blocker's colour, visibility, transparency, reminders, guest
+// settings and attendees on the event. Unset values are sent explicitly so that a patch resets whatever
+// an earlier template set.
+func withPresentation(event *calendar.Event, b *domain.Blocker) *calendar.Event {
+	event.Visibility = visibilityDefault
+	if b.Visibility != "" {
+		event.Visibility = b.Visibility
+	}
+	if b.ColorID != "" {
+		event.ColorId = b.ColorID
+	} else {
+		event.NullFields = append(event.NullFields, "ColorId")
+	}
+	event.Transparency = transparencyOpaque
+	if b.Free {
+		event.Transparency = transparencyTransparent
+	}
+	event.GuestsCanSeeOtherGuests = googleapi.Bool(!b.HideOtherGuests)
+	event.Reminders = &calendar.EventReminders{UseDefault: b.Reminders == nil}
+	if b.Reminders != nil {
+		event.Reminders.Overrides = make([]*calendar.EventReminder, 0, len(b.Reminders))
+		for _, r := range b.Reminders {
+			event.Reminders.Overrides = append(event.Reminders.Overrides, &calendar.EventReminder{Method: r.Method, Minutes: int64(r.Minutes)})
 		}
-		call = call.PageToken(events.NextPageToken)
+		// useDefault false and an empty override list are zero values; send them anyway.
+		event.Reminders.ForceSendFields = []string{"UseDefault", "Overrides"}
 	}
-	return all, nil
+	event.Attendees = make([]*calendar.EventAttendee, 0, len(b.Attendees))
+	for _, email := range b.Attendees {
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: email})
+	}
+	// An empty list removes attendees a patch would otherwise keep.
+	event.ForceSendFields = append(event.ForceSendFields, "Attendees")
+	return event
 }
 
-// extractEventDate extracts the date from a Google Calendar event
-func (r *Repository) extractEventDate(event *calendar.Event) time.Time {
-	if event.Start == nil {
-		return time.Time{}
+// presentationFromEvent reads back the settings withPresentation writes.
+func presentationFromEvent(event *calendar.Event, b *domain.Blocker) {
+	b.ColorID = event.ColorId
+	if event.Visibility != visibilityDefault {
+		b.Visibility = event.Visibility
 	}
-
-	// Try DateTime first (for timed events)
-	if event.Start.DateTime != "" {
-		if t, err := time.Parse(time.RFC3339, event.Start.DateTime); err == nil {
-			return t
+	b.Free = event.Transparency == transparencyTransparent
+	b.HideOtherGuests = event.GuestsCanSeeOtherGuests != nil && !*event.GuestsCanSeeOtherGuests
+	if event.Reminders != nil && !event.Reminders.UseDefault {
+		b.Reminders = make([]domain.Reminder, 0, len(event.Reminders.Overrides))
+		for _, r := range event.Reminders.Overrides {
+			b.Reminders = append(b.Reminders, domain.Reminder{Method: r.Method, Minutes: int(r.Minutes)})
 		}
+		domain.SortReminders(b.Reminders)
 	}
-
-	// Try Date (for all-day events)
-	if event.Start.Date != "" {
-		if t, err := time.Parse("2006-01-02", event.Start.Date); err == nil {
-			return t
+	if len(event.Attendees) > 0 {
+		emails := make([]string, 0, len(event.Attendees))
+		for _, a := range event.Attendees {
+			emails = append(emails, a.Email)
 		}
+		b.Attendees = domain.NormalizeAttendees(emails)
 	}
-
-	return time.Time{}
 }
@@ -0,0 +1,88 @@
+package googlecalendar
+
+import (
+	crand "crypto/rand"
+	"encoding/base32"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Retry policy for Google Calendar calls. Attempts back off exponentially from
+// retryBaseDelay, with full jitter, so concurrent writers hitting the same quota spread out.
+const (
+	retryAttempts  = 5
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 16 * time.Second
+)
+
+// sleep waits between attempts; tests replace it.
+var sleep = time.Sleep
+
+// withRetry runs call until it succeeds, fails with an error that is not worth retrying,
+// or has been tried retryAttempts times. call gets the attempt number, starting at 1.
+func withRetry(call func(attempt int) error) error {
+	var err error
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		if err = call(attempt); err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt < retryAttempts {
+			sleep(backoff(attempt))
+		}
+	}
+	return err
+}
+
+// backoff returns a random delay up to retryBaseDelay·2^(attempt-1), capped at retryMaxDelay.
+func backoff(attempt int) time.Duration {
+	ceiling := min(retryBaseDelay<<(attempt-1), retryMaxDelay)
+	return rand.N(ceiling) + 1
+}
+
+// isRetryable reports whether the error is a rate limit (429, or 403 with a rate limit
+// reason) or a server error, which Google asks clients to retry with exponential backoff.
+func isRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests, apiErr.Code >= http.StatusInternalServerError:
+		return true
+	case apiErr.Code == http.StatusForbidden:
+		for _, item := range apiErr.Errors {
+			if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasStatus reports whether err is a Google API error with one of the status codes.
+func hasStatus(err error, codes ...int) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// newEventID returns a random event ID in the alphabet Google Calendar accepts for
+// client-chosen IDs (base32hex, lower case). Choosing the ID makes a retried insert safe:
+// if the first attempt went through, the retry fails with 409 instead of duplicating the event.
+func newEventID() string {
+	buf := make([]byte, 16)
+	_, _ = crand.Read(buf)
+	return strings.ToLower(base32.HexEncoding.WithPadding(base32.NoPadding).EncodeToString(buf))
+}
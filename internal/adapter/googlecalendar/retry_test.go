@@ -0,0 +1,90 @@
+package googlecalendar
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"too many requests", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"server error", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"rate limit exceeded", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, true},
+		{"user rate limit exceeded", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, true},
+		{"forbidden", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}, false},
+		{"not found", &googleapi.Error{Code: http.StatusNotFound}, false},
+		{"wrapped", errors.Join(errors.New("context"), &googleapi.Error{Code: http.StatusBadGateway}), true},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	var slept []time.Duration
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { sleep = time.Sleep }()
+
+	calls := 0
+	err := withRetry(func(attempt int) error {
+		calls++
+		if attempt < 3 {
+			return &googleapi.Error{Code: http.StatusTooManyRequests}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 || len(slept) != 2 {
+		t.Errorf("withRetry() = %v after %d calls and %d sleeps, want success after 3 calls and 2 sleeps", err, calls, len(slept))
+	}
+	for i, d := range slept {
+		if ceiling := retryBaseDelay << i; d <= 0 || d > ceiling {
+			t.Errorf("sleep %d = %v, want within (0, %v]", i, d, ceiling)
+		}
+	}
+
+	calls = 0
+	err = withRetry(func(int) error {
+		calls++
+		return &googleapi.Error{Code: http.StatusBadRequest}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("withRetry() = %v after %d calls, want the error after 1 call", err, calls)
+	}
+
+	calls = 0
+	err = withRetry(func(int) error {
+		calls++
+		return &googleapi.Error{Code: http.StatusInternalServerError}
+	})
+	if err == nil || calls != retryAttempts {
+		t.Errorf("withRetry() = %v after %d calls, want the error after %d calls", err, calls, retryAttempts)
+	}
+}
+
+func TestNewEventID(t *testing.T) {
+	id := newEventID()
+	if len(id) < 5 || len(id) > 1024 {
+		t.Fatalf("len(newEventID()) = %d, want 5..1024", len(id))
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'v') {
+			t.Fatalf("newEventID() = %q contains %q, want only base32hex characters", id, c)
+		}
+	}
+	if newEventID() == id {
+		t.Error("newEventID() returned the same ID twice")
+	}
+}
@@ -0,0 +1,195 @@
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+const defaultFilesDir = "./holidays"
+
+// FilesDir returns the directory local iCalendar files are read from.
+// Reads HOLIDAY_FILES_DIR (default "./holidays"). Config paths are resolved relative to it
+// so that a config can never read arbitrary files from the server.
+func FilesDir() string {
+	if dir := os.Getenv("HOLIDAY_FILES_DIR"); dir != "" {
+		return dir
+	}
+	return defaultFilesDir
+}
+
+// event is a VEVENT reduced to what matters for holidays.
+type event struct {
+	start  time.Time // All-day start date (midnight UTC).
+	days   int       // Number of days covered; DTEND is exclusive.
+	name   string
+	yearly bool // RRULE:FREQ=YEARLY — repeats on the same month/day every year.
+}
+
+// FileSource is a domain.HolidaySource backed by a local iCalendar (.ics) file.
+// Every event in the file counts as a holiday.
+type FileSource struct {
+	path   string
+	events []event
+}
+
+// Open reads and parses the iCalendar file at path inside dir.
+func Open(dir, path string) (*FileSource, error) {
+	if !fs.ValidPath(path) {
+		return nil, fmt.Errorf("invalid iCalendar file path %q: must be relative to the holiday files directory", path)
+	}
+	data, err := fs.ReadFile(os.DirFS(dir), path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read iCalendar file %q: %w", path, err)
+	}
+	events, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse iCalendar file %q: %w", path, err)
+	}
+	return &FileSource{path: path, events: events}, nil
+}
+
+func (s *FileSource) Name() string {
+	return "iCalendar file (" + s.path + ")"
+}
+
+// HolidaysInRange returns every day in [rangeStart, rangeEnd) covered by an event in the file.
+func (s *FileSource) HolidaysInRange(rangeStart, rangeEnd time.Time) ([]domain.Holiday, error) {
+	var holidays []domain.Holiday
+	for _, ev := range s.events {
+		for _, start := range ev.occurrences(rangeStart, rangeEnd) {
+			for i := 0; i < ev.days; i++ {
+				d := start.AddDate(0, 0, i)
+				if !d.Before(rangeStart) && d.Before(rangeEnd) {
+					holidays = append(holidays, domain.Holiday{Date: d, Name: ev.name})
+				}
+			}
+		}
+	}
+	return holidays, nil
+}
+
+// occurrences returns the start dates of the event that may overlap [rangeStart, rangeEnd).
+func (ev event) occurrences(rangeStart, rangeEnd time.Time) []time.Time {
+	if !ev.yearly {
+		return []time.Time{ev.start}
+	}
+	var out []time.Time
+	// Start one year early so multi-day events spanning New Year are included.
+	for y := max(ev.start.Year(), rangeStart.Year()-1); y <= rangeEnd.Year(); y++ {
+		out = append(out, time.Date(y, ev.start.Month(), ev.start.Day(), 0, 0, 0, 0, time.UTC))
+	}
+	return out
+}
+
+// parse extracts the events of an iCalendar document.
+// Only DTSTART, DTEND, SUMMARY, STATUS and RRULE:FREQ=YEARLY are interpreted;
+// other recurrence rules are treated as a single occurrence.
+func parse(data []byte) ([]event, error) {
+	lines := unfold(data)
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+
+	var events []event
+	var cur *event
+	var end time.Time
+	var cancelled bool
+	for i, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			cur, end, cancelled = &event{}, time.Time{}, false
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if cur == nil || cur.start.IsZero() {
+				return nil, fmt.Errorf("line %d: event without DTSTART", i+1)
+			}
+			cur.days = 1
+			if !end.IsZero() && end.After(cur.start) {
+				cur.days = int(end.Sub(cur.start).Hours() / 24)
+			}
+			if !cancelled {
+				events = append(events, *cur)
+			}
+			cur = nil
+		case cur == nil:
+			continue
+		case name == "DTSTART":
+			d, err := parseDate(params, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			cur.start = d
+		case name == "DTEND":
+			d, err := parseDate(params, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			// Only all-day events span days; a timed event covers its start day.
+			if isDateValue(params, value) {
+				end = d
+			}
+		case name == "SUMMARY":
+			cur.name = unescapeText(value)
+		case name == "STATUS":
+			cancelled = strings.EqualFold(value, "CANCELLED")
+		case name == "RRULE":
+			cur.yearly = strings.Contains(strings.ToUpper(value), "FREQ=YEARLY")
+		}
+	}
+	return events, nil
+}
+
+// unfold joins folded content lines (continuations start with a space or tab).
+func unfold(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitProperty splits "NAME;PARAM=X:value" into its upper-cased name, params and value.
+func splitProperty(line string) (string, string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	name, params, _ := strings.Cut(head, ";")
+	return strings.ToUpper(name), strings.ToUpper(params), value
+}
+
+func isDateValue(params, value string) bool {
+	return strings.Contains(params, "VALUE=DATE") && !strings.Contains(params, "VALUE=DATE-TIME") || len(value) == 8
+}
+
+// parseDate returns the calendar date of a DATE or DATE-TIME value as midnight UTC.
+func parseDate(params, value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	if !isDateValue(params, value) && (len(value) < 15 || value[8] != 'T') {
+		return time.Time{}, fmt.Errorf("invalid date-time %q", value)
+	}
+	d, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return d, nil
+}
+
+func unescapeText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,83 @@
+package ics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261229\r\n" +
+	"DTEND;VALUE=DATE:20270101\r\n" +
+	"SUMMARY:Year-end\\, shutdown\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20200501\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"SUMMARY:Founding\r\n" +
+	"  Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20261120T090000Z\r\n" +
+	"DTEND:20261120T180000Z\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261121\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"SUMMARY:Cancelled\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestFileSource_HolidaysInRange(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "company.ics"), []byte(testCalendar), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src, err := Open(dir, "company.ics")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	got, err := src.HolidaysInRange(day("2026-04-01"), day("2026-12-31"))
+	if err != nil {
+		t.Fatalf("HolidaysInRange() error = %v", err)
+	}
+	want := map[string]string{
+		"2026-12-29": "Year-end, shutdown",
+		"2026-12-30": "Year-end, shutdown",
+		"2026-05-01": "Founding Day",
+		"2026-11-20": "Offsite",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("HolidaysInRange() = %v, want %d holidays", got, len(want))
+	}
+	for _, h := range got {
+		if name, ok := want[h.Date.Format(time.DateOnly)]; !ok || name != h.Name {
+			t.Errorf("unexpected holiday %s %q", h.Date.Format(time.DateOnly), h.Name)
+		}
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.ics"), []byte("not a calendar"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"../etc/passwd.ics", "missing.ics", "bad.ics"} {
+		if _, err := Open(dir, path); err == nil {
+			t.Errorf("Open(%q) expected error, got nil", path)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/nvat/tgifreezeday/internal/domain"
 	"github.com/nvat/tgifreezeday/internal/helpers"
 	"gopkg.in/yaml.v3"
 )
@@ -14,14 +16,107 @@ type SharedConfig struct {
 
 type ReadFromConfig struct {
 	GoogleCalendar GoogleCalendarReadConfig `yaml:"googleCalendar"`
+	// Optional extra holiday sources, merged with the Google public holidays.
+	ICSFile     *ICSFileReadConfig `yaml:"icsFile,omitempty"`
+	HolidayList []HolidayListEntry `yaml:"holidayList,omitempty"`
+	// Dates forced to be business or non-business days, applied after the holiday sources.
+	Overrides []OverrideEntry `yaml:"overrides,omitempty"`
+	// Which reported holidays count as non-business days.
+	HolidayPolicy HolidayPolicyConfig `yaml:"holidayPolicy,omitempty"`
+	// Named freeze windows, merged with the todayIsFreezeDayIf rules.
+	FreezeWindows []FreezeWindowEntry `yaml:"freezeWindows,omitempty"`
+}
+
+// FreezeWindowEntry is a named span of freeze days. Set either from/to, or
+// lastBusinessDaysOfQuarter.
+type FreezeWindowEntry struct {
+	Name string `yaml:"name"`
+	// YYYY-MM-DD, or MM-DD when yearly is set
+	From string `yaml:"from,omitempty"`
+	To   string `yaml:"to,omitempty"`
+	// Repeat from..to every year; the range may wrap over the year end
+	Yearly bool `yaml:"yearly,omitempty"`
+	// Freeze the last N business days of each quarter, through the quarter end
+	LastBusinessDaysOfQuarter int `yaml:"lastBusinessDaysOfQuarter,omitempty"`
+	// 1-12; month the fiscal year, and so its quarters, start in. Default 1 (January)
+	FiscalYearStartMonth int `yaml:"fiscalYearStartMonth,omitempty"`
+	// Name of a writeTo.googleCalendar.ifTodayIsFreezeDay.templates entry; default when empty
+	Template string `yaml:"template,omitempty"`
+}
+
+// HolidayPolicyConfig decides which holidays from the sources count as non-business days.
+// By default only public holidays and the config's own declared holidays count.
+type HolidayPolicyConfig struct {
+	CountObservances      bool `yaml:"countObservances,omitempty"`
+	CountRegionalHolidays bool `yaml:"countRegionalHolidays,omitempty"`
+	// Holiday names always counted (case-insensitive)
+	Include []string `yaml:"include,omitempty"`
+	// Holiday names never counted (case-insensitive); wins over include
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// ICSFileReadConfig points to a local iCalendar file whose events are holidays.
+type ICSFileReadConfig struct {
+	// Path relative to the HOLIDAY_FILES_DIR directory
+	Path string `yaml:"path"`
+}
+
+// Values of OverrideEntry.Is.
+const (
+	OverrideBusinessDay    = "businessDay"
+	OverrideNonBusinessDay = "nonBusinessDay"
+)
+
+// OverrideEntry forces one date to be a business or non-business day.
+type OverrideEntry struct {
+	// YYYY-MM-DD
+	Date string `yaml:"date"`
+	// "businessDay" or "nonBusinessDay"
+	Is     string `yaml:"is"`
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// HolidayListEntry is a company holiday declared inline in the config.
+type HolidayListEntry struct {
+	// YYYY-MM-DD
+	Date string `yaml:"date"`
+	Name string `yaml:"name,omitempty"`
 }
 
 type GoogleCalendarReadConfig struct {
-	// ISO 3166 A-3 country code
-	CountryCode        string                `yaml:"countryCode"`
+	// ISO 3166 A-3 country code. Single-country form kept for existing configs; use CountryCodes.
+	CountryCode string `yaml:"countryCode,omitempty"`
+	// ISO 3166 A-3 country codes; may be empty when icsFile or holidayList is set
+	CountryCodes []string `yaml:"countryCodes,omitempty"`
+	// How the countries' holidays combine: "any" (default) or "all"
+	CountryCombination string                `yaml:"countryCombination,omitempty"`
 	TodayIsFreezeDayIf []map[string][]string `yaml:"todayIsFreezeDayIf"`
 }
 
+// Values of GoogleCalendarReadConfig.CountryCombination.
+const (
+	// A day is a holiday if it is a holiday in any of the countries.
+	CountryCombinationAny = "any"
+	// A day is a holiday only if it is a holiday in all of the countries.
+	CountryCombinationAll = "all"
+)
+
+// Countries returns the configured country codes, from countryCodes or the legacy countryCode.
+func (g *GoogleCalendarReadConfig) Countries() []string {
+	if len(g.CountryCodes) > 0 {
+		return g.CountryCodes
+	}
+	if g.CountryCode != "" {
+		return []string{g.CountryCode}
+	}
+	return nil
+}
+
+// MatchAllCountries reports whether a day must be a holiday in every country to count.
+func (g *GoogleCalendarReadConfig) MatchAllCountries() bool {
+	return g.CountryCombination == CountryCombinationAll
+}
+
 type WriteToConfig struct {
 	GoogleCalendar GoogleCalendarWriteConfig `yaml:"googleCalendar"`
 }
@@ -29,10 +124,22 @@ type WriteToConfig struct {
 type GoogleCalendarWriteConfig struct {
 	ID                 string                   `yaml:"id"`
 	IfTodayIsFreezeDay IfTodayIsFreezeDayConfig `yaml:"ifTodayIsFreezeDay"`
+	// OnManualEdit is what a sync does with blockers people edited or deleted by hand:
+	// "overwrite" (default), "respect" or "stop".
+	OnManualEdit string `yaml:"onManualEdit,omitempty"`
 }
 
 type IfTodayIsFreezeDayConfig struct {
 	Default DefaultConfig `yaml:"default"`
+	// Named event templates picked by rule groups (`template: [name]`) and freeze windows.
+	// Fields left unset are taken from Default.
+	Templates map[string]DefaultConfig `yaml:"templates,omitempty"`
+	// MergeConsecutiveDays writes one multi-day event per run of consecutive freeze days
+	// picking the same template, instead of one event per day.
+	MergeConsecutiveDays bool `yaml:"mergeConsecutiveDays,omitempty"`
+	// SendUpdates is who gets invitation and update emails for blockers with attendees:
+	// "all", "externalOnly" or "none" (default).
+	SendUpdates string `yaml:"sendUpdates,omitempty"`
 }
 
 type DefaultConfig struct {
@@ -41,8 +148,45 @@ type DefaultConfig struct {
 	StartTime   *string `yaml:"startTime,omitempty"`
 	EndTime     *string `yaml:"endTime,omitempty"`
 	AllDay      *bool   `yaml:"allDay,omitempty"`
+	// Google Calendar event colour, "1" to "11"
+	ColorID *string `yaml:"colorId,omitempty"`
+	// "default", "public" or "private"
+	Visibility *string `yaml:"visibility,omitempty"`
+	// "busy" (default) blocks free/busy; "free" shows the time as available
+	Transparency *string `yaml:"transparency,omitempty"`
+	// Reminder overrides; unset uses the calendar's default reminders, an empty list none
+	Reminders *[]ReminderConfig `yaml:"reminders,omitempty"`
+	// Whether guests can see who else is invited (default true)
+	GuestsCanSeeOtherGuests *bool `yaml:"guestsCanSeeOtherGuests,omitempty"`
+	// Email addresses of people or groups invited to the blocker
+	Attendees *[]string `yaml:"attendees,omitempty"`
 }
 
+// ReminderConfig is one reminder on a blocker event.
+type ReminderConfig struct {
+	Method  string `yaml:"method"` // "popup" or "email"
+	Minutes int    `yaml:"minutes"`
+}
+
+// Values of DefaultConfig.Visibility.
+const (
+	VisibilityDefault = "default"
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// Values of DefaultConfig.Transparency.
+const (
+	TransparencyBusy = "busy"
+	TransparencyFree = "free"
+)
+
+// Values of ReminderConfig.Method.
+const (
+	ReminderMethodPopup = "popup"
+	ReminderMethodEmail = "email"
+)
+
 type Config struct {
 	Shared   SharedConfig   `yaml:"shared"`
 	ReadFrom ReadFromConfig `yaml:"readFrom"`
@@ -61,6 +205,8 @@ func LoadWithDefaultFromByteArray(data []byte) (*Config, error) {
 
 const defaultSummary = "Today is FREEZE-DAY. no PROD operation is allowed."
 const defaultDescription = "Managed by tgifreezeday, do not modify."
+const defaultStartTime = "08:00"
+const defaultEndTime = "20:00"
 
 // ToYAML marshals the config back to YAML bytes.
 func (c *Config) ToYAML() (string, error) {
@@ -82,10 +228,208 @@ func (c *Config) SetDefault() {
 	allDay := c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.AllDay
 	if allDay == nil || !*allDay {
 		if c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.StartTime == nil {
-			c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.StartTime = helpers.StringPtr("08:00")
+			c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.StartTime = helpers.StringPtr(defaultStartTime)
 		}
 		if c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.EndTime == nil {
-			c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.EndTime = helpers.StringPtr("20:00")
+			c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.EndTime = helpers.StringPtr(defaultEndTime)
+		}
+	}
+}
+
+// BlockerTemplate converts the default blocker event settings into the domain template.
+// Call after SetDefault so that summary, description and times are populated.
+func (c *Config) BlockerTemplate() domain.BlockerTemplate {
+	return toBlockerTemplate(c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default)
+}
+
+// DriftPolicy returns the domain policy for blockers changed by hand.
+func (c *Config) DriftPolicy() domain.DriftPolicy {
+	if c.WriteTo.GoogleCalendar.OnManualEdit == "" {
+		return domain.DriftPolicyOverwrite
+	}
+	return domain.DriftPolicy(c.WriteTo.GoogleCalendar.OnManualEdit)
+}
+
+// BlockerTemplates converts the default and named blocker event settings into the domain
+// templates; configName is available to summary and description templates. Call after SetDefault.
+func (c *Config) BlockerTemplates(configName string) *domain.BlockerTemplates {
+	templates := &domain.BlockerTemplates{
+		Default:    c.BlockerTemplate(),
+		Named:      make(map[string]domain.BlockerTemplate, len(c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates)),
+		ConfigName: configName,
+
+		MergeConsecutiveDays: c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.MergeConsecutiveDays,
+		SendUpdates:          c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.SendUpdates,
+	}
+	for name := range c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates {
+		templates.Named[name] = toBlockerTemplate(c.ResolvedTemplate(name))
+	}
+	return templates
+}
+
+// ResolvedTemplate returns the named template with every unset field taken from the default
+// block. A timed template without times of its own or in the default uses 08:00-20:00.
+// Call after SetDefault.
+func (c *Config) ResolvedTemplate(name string) DefaultConfig {
+	d := c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default
+	t := c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates[name]
+	pick := func(own, fallback *string) *string {
+		if own != nil {
+			return own
+		}
+		return fallback
+	}
+	resolved := DefaultConfig{
+		Summary:     pick(t.Summary, d.Summary),
+		Description: pick(t.Description, d.Description),
+		StartTime:   pick(t.StartTime, d.StartTime),
+		EndTime:     pick(t.EndTime, d.EndTime),
+		AllDay:      d.AllDay,
+		ColorID:     pick(t.ColorID, d.ColorID),
+		Visibility:  pick(t.Visibility, d.Visibility),
+
+		Transparency:            pick(t.Transparency, d.Transparency),
+		Reminders:               d.Reminders,
+		GuestsCanSeeOtherGuests: d.GuestsCanSeeOtherGuests,
+		Attendees:               d.Attendees,
+	}
+	if t.Attendees != nil {
+		resolved.Attendees = t.Attendees
+	}
+	if t.Reminders != nil {
+		resolved.Reminders = t.Reminders
+	}
+	if t.GuestsCanSeeOtherGuests != nil {
+		resolved.GuestsCanSeeOtherGuests = t.GuestsCanSeeOtherGuests
+	}
+	if t.AllDay != nil {
+		resolved.AllDay = t.AllDay
+	}
+	if resolved.AllDay == nil || !*resolved.AllDay {
+		resolved.StartTime = pick(resolved.StartTime, helpers.StringPtr(defaultStartTime))
+		resolved.EndTime = pick(resolved.EndTime, helpers.StringPtr(defaultEndTime))
+	}
+	return resolved
+}
+
+func toBlockerTemplate(d DefaultConfig) domain.BlockerTemplate {
+	tmpl := domain.BlockerTemplate{
+		Summary:     *d.Summary,
+		Description: *d.Description,
+		AllDay:      d.AllDay != nil && *d.AllDay,
+	}
+	if !tmpl.AllDay {
+		tmpl.StartTime = *d.StartTime
+		tmpl.EndTime = *d.EndTime
+	}
+	if d.ColorID != nil {
+		tmpl.ColorID = *d.ColorID
+	}
+	if d.Visibility != nil && *d.Visibility != VisibilityDefault {
+		tmpl.Visibility = *d.Visibility
+	}
+	if d.Transparency != nil && *d.Transparency == TransparencyFree {
+		tmpl.Free = true
+	}
+	if d.Reminders != nil {
+		tmpl.Reminders = make([]domain.Reminder, 0, len(*d.Reminders))
+		for _, r := range *d.Reminders {
+			tmpl.Reminders = append(tmpl.Reminders, domain.Reminder{Method: r.Method, Minutes: r.Minutes})
+		}
+		domain.SortReminders(tmpl.Reminders)
+	}
+	if d.GuestsCanSeeOtherGuests != nil && !*d.GuestsCanSeeOtherGuests {
+		tmpl.HideOtherGuests = true
+	}
+	if d.Attendees != nil {
+		tmpl.Attendees = domain.NormalizeAttendees(*d.Attendees)
+	}
+	// Validate rejects templates that do not parse; Render reports the error for them.
+	_ = tmpl.Parse()
+	return tmpl
+}
+
+const holidayListLabel = "Company holidays (holidayList)"
+
+// HolidayListSource converts readFrom.holidayList into a holiday source, or nil when empty.
+// Call after Validate so that every date parses.
+func (c *Config) HolidayListSource() *domain.HolidayList {
+	if len(c.ReadFrom.HolidayList) == 0 {
+		return nil
+	}
+	list := &domain.HolidayList{Label: holidayListLabel}
+	for _, e := range c.ReadFrom.HolidayList {
+		d, err := time.Parse(time.DateOnly, e.Date)
+		if err != nil {
+			continue
+		}
+		list.Holidays = append(list.Holidays, domain.Holiday{Date: d, Name: e.Name})
+	}
+	return list
+}
+
+// DayOverrides converts readFrom.overrides into domain overrides.
+// Call after Validate so that every date parses.
+func (c *Config) DayOverrides() []domain.DayOverride {
+	overrides := make([]domain.DayOverride, 0, len(c.ReadFrom.Overrides))
+	for _, o := range c.ReadFrom.Overrides {
+		d, err := time.Parse(time.DateOnly, o.Date)
+		if err != nil {
+			continue
 		}
+		overrides = append(overrides, domain.DayOverride{
+			Date:        d,
+			BusinessDay: o.Is == OverrideBusinessDay,
+			Reason:      o.Reason,
+		})
+	}
+	return overrides
+}
+
+// Date layouts of FreezeWindowEntry.From and To.
+const (
+	freezeWindowDateLayout   = time.DateOnly
+	freezeWindowYearlyLayout = "01-02"
+)
+
+// FreezeRules converts todayIsFreezeDayIf and readFrom.freezeWindows into the domain rules.
+// Call after Validate so that every window date parses.
+func (c *Config) FreezeRules() *domain.FreezeRules {
+	var windows []domain.FreezeWindow
+	for _, e := range c.ReadFrom.FreezeWindows {
+		w := domain.FreezeWindow{
+			Name:                      e.Name,
+			Yearly:                    e.Yearly,
+			LastBusinessDaysOfQuarter: e.LastBusinessDaysOfQuarter,
+			FiscalYearStartMonth:      time.Month(e.FiscalYearStartMonth),
+			Template:                  e.Template,
+		}
+		if e.LastBusinessDaysOfQuarter == 0 {
+			layout := freezeWindowDateLayout
+			if e.Yearly {
+				layout = freezeWindowYearlyLayout
+			}
+			from, errFrom := time.Parse(layout, e.From)
+			to, errTo := time.Parse(layout, e.To)
+			if errFrom != nil || errTo != nil {
+				continue
+			}
+			w.From, w.To = from, to
+		}
+		windows = append(windows, w)
+	}
+	// Validate rejects rule groups that do not parse; NewFreezeRules logs and skips them.
+	rules, _ := domain.NewFreezeRules(domain.TodayIsFreezeDayIf(c.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf), windows)
+	return rules
+}
+
+// HolidayPolicy converts readFrom.holidayPolicy into the domain policy.
+func (c *Config) HolidayPolicy() domain.HolidayPolicy {
+	p := c.ReadFrom.HolidayPolicy
+	return domain.HolidayPolicy{
+		CountObservances:      p.CountObservances,
+		CountRegionalHolidays: p.CountRegionalHolidays,
+		Include:               p.Include,
+		Exclude:               p.Exclude,
 	}
 }
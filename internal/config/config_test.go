@@ -1,9 +1,11 @@
 package config
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/nvat/tgifreezeday/internal/domain"
 	"github.com/nvat/tgifreezeday/internal/helpers"
 )
 
@@ -133,3 +135,107 @@ func TestToYAML_ContainsExpectedFields(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaYAML_CountryEnumFromRegistry(t *testing.T) {
+	schema, ok := SchemaYAML(CurrentSchemaVersion)
+	if !ok {
+		t.Fatal("SchemaYAML() current version not found")
+	}
+	s := string(schema)
+	if strings.Contains(s, countryEnumPlaceholder) {
+		t.Error("schema still contains the country enum placeholder")
+	}
+	for _, code := range []string{"jpn", "sgp", "usa", "deu", "ind"} {
+		if !strings.Contains(s, code) {
+			t.Errorf("schema country enum is missing %s", code)
+		}
+	}
+}
+
+func TestValidateCountryCode_Registry(t *testing.T) {
+	cfg := &Config{ReadFrom: ReadFromConfig{GoogleCalendar: GoogleCalendarReadConfig{CountryCode: "sgp"}}}
+	if err := cfg.ValidateReadFromGoogleCalendarCountryCode(); err != nil {
+		t.Errorf("sgp should be supported: %v", err)
+	}
+	cfg.ReadFrom.GoogleCalendar.CountryCode = "sg"
+	if err := cfg.ValidateReadFromGoogleCalendarCountryCode(); err == nil {
+		t.Error("alpha-2 code sg should be rejected")
+	}
+}
+
+func TestBlockerTemplates_InheritFromDefault(t *testing.T) {
+	cfg, err := LoadWithDefaultFromByteArray([]byte(mockConfigYamlTemplates))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	templates := cfg.BlockerTemplates("")
+
+	monthEnd := templates.For("monthEnd")
+	if monthEnd.Summary != "Month-end freeze" || monthEnd.AllDay || monthEnd.StartTime != "08:00" || monthEnd.ColorID != "11" {
+		t.Errorf("monthEnd = %+v, want a timed 08:00 event with colour 11", monthEnd)
+	}
+	if monthEnd.Description != defaultDescription {
+		t.Errorf("monthEnd.Description = %q, want the default description", monthEnd.Description)
+	}
+	yearEnd := templates.For("yearEnd")
+	if !yearEnd.AllDay || yearEnd.Visibility != VisibilityPrivate {
+		t.Errorf("yearEnd = %+v, want an all-day private event", yearEnd)
+	}
+	if !reflect.DeepEqual(templates.For(""), templates.Default) {
+		t.Error("For(\"\") should return the default template")
+	}
+}
+
+func TestValidate_SummaryTemplate(t *testing.T) {
+	cfg, err := LoadWithDefaultFromByteArray([]byte(mockConfigYamlValidSummaryTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := cfg.BlockerTemplate().Summary; got != "🚫 Freeze: tomorrow is {{.NextHolidayName}}" {
+		t.Errorf("BlockerTemplate().Summary = %q, want the template unrendered", got)
+	}
+}
+
+func TestBlockerTemplates_EventSettings(t *testing.T) {
+	cfg, err := LoadWithDefaultFromByteArray([]byte(mockConfigYamlEventSettings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	templates := cfg.BlockerTemplates("")
+
+	def := templates.Default
+	wantReminders := []domain.Reminder{{Method: ReminderMethodEmail, Minutes: 1440}, {Method: ReminderMethodPopup, Minutes: 30}}
+	if def.Free || !def.HideOtherGuests || !reflect.DeepEqual(def.Reminders, wantReminders) {
+		t.Errorf("default = %+v, want busy, guests hidden and two reminders", def)
+	}
+
+	// The template turns reminders off and inherits the rest.
+	quiet := templates.For("quiet")
+	if !quiet.Free || quiet.Reminders == nil || len(quiet.Reminders) != 0 {
+		t.Errorf("quiet = %+v, want free with reminders off", quiet)
+	}
+	if quiet.ColorID != "11" || !quiet.HideOtherGuests {
+		t.Errorf("quiet = %+v, want the default colour and guest setting", quiet)
+	}
+
+	// Attendees are lowercased and sorted; the template invites nobody.
+	wantAttendees := []string{"oncall@example.com", "release-managers@example.com"}
+	if !reflect.DeepEqual(def.Attendees, wantAttendees) {
+		t.Errorf("default Attendees = %v, want %v", def.Attendees, wantAttendees)
+	}
+	if len(quiet.Attendees) != 0 {
+		t.Errorf("quiet Attendees = %v, want none", quiet.Attendees)
+	}
+	if templates.SendUpdates != domain.SendUpdatesExternalOnly {
+		t.Errorf("SendUpdates = %q, want %q", templates.SendUpdates, domain.SendUpdatesExternalOnly)
+	}
+}
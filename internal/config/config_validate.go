@@ -2,33 +2,79 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
+	"maps"
+	"net/mail"
+	"path/filepath"
+	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nvat/tgifreezeday/internal/consts"
+	"github.com/nvat/tgifreezeday/internal/domain"
 )
 
 // v1 config:
 // // readFrom:
 // //   googleCalendar:
-// //     countryCode: <supported country code> # "jpn", "vnm", A-3 ISO 3166 country code
+// //     countryCodes: [<supported country code>] # "jpn", "sgp", ... A-3 ISO 3166 country codes from consts.Countries; empty to use only the sources below
+// //     countryCode: <supported country code> # legacy single-country form, same as countryCodes: [<code>]
+// //     countryCombination: any | all # holiday in any country (default) or only in all of them
 // //     todayIsFreezeDayIf:
-// //     - [yesterday, today, tomorrow]: # with this block, rules are AND together. To do OR, specify multiple items with same key.
+// //     - <anchor>: # yesterday, today, tomorrow, nextDay, or an offset: -3d, +2d (calendar days), +1bd (business days). With this block, rules are AND together. To do OR, specify multiple items with same key.
 // //       - isTheFirstBusinessDayOfTheMonth
 // //       - isTheLastBusinessDayOfTheMonth
 // //       - isNonBusinessDay
+// //     - expr: # rule expressions, AND together. See domain.RuleExpr for the grammar.
+// //       - isNthBusinessDayOfMonth(3) or isLastWeekdayOfQuarter(friday)
+// //       - not at(tomorrow, isNonBusinessDay)
+// //       template: [<template name>] # optional; the first matching group with a template picks the event
+// //   icsFile: # optional
+// //     path: <path of a .ics file, relative to HOLIDAY_FILES_DIR>
+// //   holidayList: # optional
+// //   - date: YYYY-MM-DD
+// //     name: <holiday name>
+// //   overrides: # optional, applied after all holiday sources
+// //   - date: YYYY-MM-DD
+// //     is: businessDay | nonBusinessDay
+// //     reason: <why>
+// //   holidayPolicy: # optional
+// //     countObservances: false
+// //     countRegionalHolidays: false
+// //     include: [<holiday name>]
+// //     exclude: [<holiday name>]
+// //   freezeWindows: # optional, merged with todayIsFreezeDayIf
+// //   - name: <unique name>
+// //     from: YYYY-MM-DD # or MM-DD with yearly: true
+// //     to: YYYY-MM-DD
+// //     yearly: false
+// //   - name: <unique name>
+// //     lastBusinessDaysOfQuarter: <1-20>
+// //     fiscalYearStartMonth: <1-12> # default 1
+// //     template: <template name> # optional
 // // writeTo:
 // //   googleCalendar:
 // //     id: <google calendary id to read>
+// //     onManualEdit: overwrite | respect | stop # optional, default overwrite
 // //     ifTodayIsFreezeDay:
 // //       default:
 // //         summary: "string|null" # if `null`, use default message
+// //         colorId: "1".."11" # optional
+// //         visibility: default | public | private # optional
+// //         transparency: busy | free # optional, default busy
+// //         reminders: # optional, unset for the calendar's default reminders, [] for none
+// //           - method: popup | email
+// //             minutes: <0-40320>
+// //         guestsCanSeeOtherGuests: true # optional
+// //         attendees: [oncall@example.com] # optional, invited people or groups
+// //       templates: # optional, picked by `template: [<name>]` in a todayIsFreezeDayIf group
+// //         <name>: # same fields as default; unset fields are taken from default
+// //           summary: "string"
+// //       mergeConsecutiveDays: false # one multi-day event per run of consecutive freeze days
+// //       sendUpdates: all | externalOnly | none # optional, default none; who attendees' emails go to
 
-var supportedDates = []string{
-	"today",
-	"tomorrow",
-	"nextDay",
-}
 var supportedChecks = []string{
 	"isTheFirstBusinessDayOfTheMonth",
 	"isTheLastBusinessDayOfTheMonth",
@@ -44,7 +90,24 @@ func (c *Config) Validate() error {
 	// Validate ReadFrom block
 	// // Validate country
 	if err := c.ValidateReadFromGoogleCalendarCountryCode(); err != nil {
-		return fmt.Errorf("invalid readFrom.googleCalendar.countryCode: %w", err)
+		return fmt.Errorf("invalid readFrom.googleCalendar.countryCodes: %w", err)
+	}
+
+	// // Validate extra holiday sources
+	if err := c.ValidateReadFromICSFile(); err != nil {
+		return fmt.Errorf("invalid readFrom.icsFile: %w", err)
+	}
+	if err := c.ValidateReadFromHolidayList(); err != nil {
+		return fmt.Errorf("invalid readFrom.holidayList: %w", err)
+	}
+	if err := c.ValidateReadFromOverrides(); err != nil {
+		return fmt.Errorf("invalid readFrom.overrides: %w", err)
+	}
+	if err := c.ValidateReadFromHolidayPolicy(); err != nil {
+		return fmt.Errorf("invalid readFrom.holidayPolicy: %w", err)
+	}
+	if err := c.ValidateReadFromFreezeWindows(); err != nil {
+		return fmt.Errorf("invalid readFrom.freezeWindows: %w", err)
 	}
 
 	// // Validate freeze day rules
@@ -57,22 +120,41 @@ func (c *Config) Validate() error {
 	if err := c.ValidateWriteToGoogleCalendarID(); err != nil {
 		return fmt.Errorf("invalid writeTo.googleCalendar.id: %w", err)
 	}
+	if err := c.ValidateWriteToGoogleCalendarOnManualEdit(); err != nil {
+		return fmt.Errorf("invalid writeTo.googleCalendar.onManualEdit: %w", err)
+	}
 
 	if err := c.SetDefaultAndValidateWriteToGoogleCalendarIfTodayIsFreezeDay(); err != nil {
 		return fmt.Errorf("invalid writeTo.googleCalendar.ifTodayIsFreezeDay: %w", err)
 	}
+	if err := c.ValidateTemplateReferences(); err != nil {
+		return fmt.Errorf("invalid template reference: %w", err)
+	}
 
 	return nil
 }
 
 func (c *Config) ValidateReadFromGoogleCalendarTodayIsFreezeDayIf() error {
-	if len(c.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf) == 0 {
-		return fmt.Errorf("readFrom.googleCalendar.todayIsFreezeDayIf cannot be empty")
+	if len(c.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf) == 0 && len(c.ReadFrom.FreezeWindows) == 0 {
+		return fmt.Errorf("readFrom.googleCalendar.todayIsFreezeDayIf cannot be empty when no freeze window is configured")
 	}
-	for _, rule := range c.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf {
+	for i, rule := range c.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf {
+		if _, ok := rule[domain.RuleTemplateKey]; ok && len(rule) == 1 {
+			return fmt.Errorf("group #%d has a template but no condition", i+1)
+		}
 		for date, checks := range rule {
-			if !slices.Contains(supportedDates, date) {
-				return fmt.Errorf("unsupported date: %s. Supported dates: %v", date, supportedDates)
+			if date == domain.RuleTemplateKey {
+				continue // checked by ValidateTemplateReferences
+			}
+			if date == domain.RuleExprKey {
+				if err := validateRuleExprs(checks); err != nil {
+					return err
+				}
+				continue
+			}
+			// same grammar as evaluation, so an accepted anchor cannot fail during sync
+			if _, err := domain.ParseAnchor(date); err != nil {
+				return err
 			}
 			for _, check := range checks {
 				if !slices.Contains(supportedChecks, check) {
@@ -84,11 +166,174 @@ func (c *Config) ValidateReadFromGoogleCalendarTodayIsFreezeDayIf() error {
 	return nil
 }
 
-// ValidateCountry checks if a country is supported
+// validateRuleExprs parses and type-checks the expressions of an expr group.
+func validateRuleExprs(exprs []string) error {
+	if len(exprs) == 0 {
+		return fmt.Errorf("%s group cannot be empty", domain.RuleExprKey)
+	}
+	for _, src := range exprs {
+		if _, err := domain.ParseRuleExpr(src); err != nil {
+			return fmt.Errorf("invalid expression %q: %w", src, err)
+		}
+	}
+	return nil
+}
+
+// ValidateCountry checks that every country is supported and the combination mode is known.
+// The country list may be empty only when another holiday source is configured.
 func (c *Config) ValidateReadFromGoogleCalendarCountryCode() error {
-	country := c.ReadFrom.GoogleCalendar.CountryCode
-	if !slices.Contains(consts.SupportedCountries, country) {
-		return fmt.Errorf("unsupported country: %s. Supported countries: %v", country, consts.SupportedCountries)
+	g := c.ReadFrom.GoogleCalendar
+	if g.CountryCode != "" && len(g.CountryCodes) > 0 {
+		return fmt.Errorf("set either countryCode or countryCodes, not both")
+	}
+	switch g.CountryCombination {
+	case "", CountryCombinationAny, CountryCombinationAll:
+	default:
+		return fmt.Errorf("unsupported countryCombination: %s. Supported: %s, %s", g.CountryCombination, CountryCombinationAny, CountryCombinationAll)
+	}
+
+	countries := g.Countries()
+	if len(countries) == 0 {
+		if c.ReadFrom.ICSFile != nil || len(c.ReadFrom.HolidayList) > 0 {
+			return nil
+		}
+		return fmt.Errorf("at least one country is required when no other holiday source is configured. Supported countries: %v", consts.SupportedCountries)
+	}
+	seen := make(map[string]bool)
+	for _, country := range countries {
+		if !slices.Contains(consts.SupportedCountries, country) {
+			return fmt.Errorf("unsupported country: %s. Supported countries: %v", country, consts.SupportedCountries)
+		}
+		if seen[country] {
+			return fmt.Errorf("country %s is listed more than once", country)
+		}
+		seen[country] = true
+	}
+	return nil
+}
+
+// ValidateReadFromICSFile checks the iCalendar file path. The file itself is read when
+// the holiday sources are built, so that a missing file is reported on save.
+func (c *Config) ValidateReadFromICSFile() error {
+	if c.ReadFrom.ICSFile == nil {
+		return nil
+	}
+	path := c.ReadFrom.ICSFile.Path
+	if path == "" {
+		return fmt.Errorf("readFrom.icsFile.path cannot be empty")
+	}
+	if !fs.ValidPath(path) {
+		return fmt.Errorf("readFrom.icsFile.path %q must be a relative path without \"..\"", path)
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".ics") {
+		return fmt.Errorf("readFrom.icsFile.path %q must end with .ics", path)
+	}
+	return nil
+}
+
+// ValidateReadFromHolidayList checks that every company holiday has a valid, unique date.
+func (c *Config) ValidateReadFromHolidayList() error {
+	seen := make(map[string]bool)
+	for i, e := range c.ReadFrom.HolidayList {
+		if _, err := time.Parse(time.DateOnly, e.Date); err != nil {
+			return fmt.Errorf("readFrom.holidayList[%d].date %q is not a valid YYYY-MM-DD date", i, e.Date)
+		}
+		if seen[e.Date] {
+			return fmt.Errorf("readFrom.holidayList[%d].date %s is listed more than once", i, e.Date)
+		}
+		seen[e.Date] = true
+	}
+	return nil
+}
+
+// ValidateReadFromOverrides checks that every override has a valid, unique date and a known kind.
+func (c *Config) ValidateReadFromOverrides() error {
+	seen := make(map[string]bool)
+	for i, o := range c.ReadFrom.Overrides {
+		if _, err := time.Parse(time.DateOnly, o.Date); err != nil {
+			return fmt.Errorf("readFrom.overrides[%d].date %q is not a valid YYYY-MM-DD date", i, o.Date)
+		}
+		if seen[o.Date] {
+			return fmt.Errorf("readFrom.overrides[%d].date %s is listed more than once", i, o.Date)
+		}
+		seen[o.Date] = true
+		if o.Is != OverrideBusinessDay && o.Is != OverrideNonBusinessDay {
+			return fmt.Errorf("readFrom.overrides[%d].is %q must be %q or %q", i, o.Is, OverrideBusinessDay, OverrideNonBusinessDay)
+		}
+	}
+	return nil
+}
+
+// ValidateReadFromFreezeWindows checks that every window has a unique name and exactly one
+// kind: a date range, a yearly range, or the last business days of each quarter.
+func (c *Config) ValidateReadFromFreezeWindows() error {
+	seen := make(map[string]bool)
+	for i, w := range c.ReadFrom.FreezeWindows {
+		name := strings.TrimSpace(w.Name)
+		if name == "" {
+			return fmt.Errorf("readFrom.freezeWindows[%d].name cannot be empty", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("readFrom.freezeWindows[%d].name %q is used more than once", i, name)
+		}
+		seen[name] = true
+
+		hasRange := w.From != "" || w.To != ""
+		if w.LastBusinessDaysOfQuarter != 0 {
+			if hasRange || w.Yearly {
+				return fmt.Errorf("readFrom.freezeWindows[%d] %q: set either from/to or lastBusinessDaysOfQuarter, not both", i, name)
+			}
+			if w.LastBusinessDaysOfQuarter < 1 || w.LastBusinessDaysOfQuarter > 20 {
+				return fmt.Errorf("readFrom.freezeWindows[%d].lastBusinessDaysOfQuarter must be 1-20, got %d", i, w.LastBusinessDaysOfQuarter)
+			}
+			if w.FiscalYearStartMonth < 0 || w.FiscalYearStartMonth > 12 {
+				return fmt.Errorf("readFrom.freezeWindows[%d].fiscalYearStartMonth must be 1-12, got %d", i, w.FiscalYearStartMonth)
+			}
+			continue
+		}
+		if w.FiscalYearStartMonth != 0 {
+			return fmt.Errorf("readFrom.freezeWindows[%d].fiscalYearStartMonth only applies to lastBusinessDaysOfQuarter", i)
+		}
+		if !hasRange {
+			return fmt.Errorf("readFrom.freezeWindows[%d] %q: set from/to or lastBusinessDaysOfQuarter", i, name)
+		}
+		layout, format := freezeWindowDateLayout, "YYYY-MM-DD"
+		if w.Yearly {
+			layout, format = freezeWindowYearlyLayout, "MM-DD"
+		}
+		from, err := time.Parse(layout, w.From)
+		if err != nil {
+			return fmt.Errorf("readFrom.freezeWindows[%d].from %q is not a valid %s date", i, w.From, format)
+		}
+		to, err := time.Parse(layout, w.To)
+		if err != nil {
+			return fmt.Errorf("readFrom.freezeWindows[%d].to %q is not a valid %s date", i, w.To, format)
+		}
+		if !w.Yearly && to.Before(from) {
+			return fmt.Errorf("readFrom.freezeWindows[%d] %q: to %s is before from %s", i, name, w.To, w.From)
+		}
+	}
+	return nil
+}
+
+// ValidateReadFromHolidayPolicy checks that the include/exclude name lists have no empty
+// names and do not contradict each other.
+func (c *Config) ValidateReadFromHolidayPolicy() error {
+	p := c.ReadFrom.HolidayPolicy
+	for i, name := range p.Include {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("readFrom.holidayPolicy.include[%d] cannot be empty", i)
+		}
+	}
+	for i, name := range p.Exclude {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("readFrom.holidayPolicy.exclude[%d] cannot be empty", i)
+		}
+		for _, inc := range p.Include {
+			if strings.EqualFold(strings.TrimSpace(inc), strings.TrimSpace(name)) {
+				return fmt.Errorf("holiday %q is both included and excluded", name)
+			}
+		}
 	}
 	return nil
 }
@@ -118,40 +363,173 @@ func (c *Config) ValidateWriteToGoogleCalendarID() error {
 	return nil
 }
 
+var supportedManualEditPolicies = []string{
+	string(domain.DriftPolicyOverwrite), string(domain.DriftPolicyRespect), string(domain.DriftPolicyStop),
+}
+
+func (c *Config) ValidateWriteToGoogleCalendarOnManualEdit() error {
+	if p := c.WriteTo.GoogleCalendar.OnManualEdit; p != "" && !slices.Contains(supportedManualEditPolicies, p) {
+		return fmt.Errorf("writeTo.googleCalendar.onManualEdit must be one of %v, got %q", supportedManualEditPolicies, p)
+	}
+	return nil
+}
+
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+var supportedVisibilities = []string{VisibilityDefault, VisibilityPublic, VisibilityPrivate}
+
+var supportedTransparencies = []string{TransparencyBusy, TransparencyFree}
+
+var supportedReminderMethods = []string{ReminderMethodPopup, ReminderMethodEmail}
+
+var supportedSendUpdates = []string{domain.SendUpdatesAll, domain.SendUpdatesExternalOnly, domain.SendUpdatesNone}
+
+// Google Calendar accepts at most 5 reminder overrides, each at most 4 weeks before the event.
+const (
+	maxReminders       = 5
+	maxReminderMinutes = 40320
+)
+
 // Validate the event to write on the WriteTo calendar
 // SIDE EFFECT!! if summary or description is nil, set it to default message
 func (c *Config) SetDefaultAndValidateWriteToGoogleCalendarIfTodayIsFreezeDay() error {
+	if err := validateEventConfig("writeTo.googleCalendar.ifTodayIsFreezeDay.default", c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates)) {
+		if !templateNamePattern.MatchString(name) {
+			return fmt.Errorf("template name %q may only contain letters, digits, '-' and '_'", name)
+		}
+		if err := validateEventConfig("writeTo.googleCalendar.ifTodayIsFreezeDay.templates."+name, c.ResolvedTemplate(name)); err != nil {
+			return err
+		}
+	}
+	if s := c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.SendUpdates; s != "" && !slices.Contains(supportedSendUpdates, s) {
+		return fmt.Errorf("writeTo.googleCalendar.ifTodayIsFreezeDay.sendUpdates must be one of %v, got %q", supportedSendUpdates, s)
+	}
+	return nil
+}
+
+// validateEventConfig checks one event template, with defaults already applied.
+func validateEventConfig(path string, d DefaultConfig) error {
 	// enforce limits so that it won't be rejected by Google Calendar API
-	if len([]rune(*c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.Summary)) > 250 {
-		return fmt.Errorf("writeTo.googleCalendar.ifTodayIsFreezeDay.default.summary cannot be longer than 250 characters")
+	// The rendered text is checked again by CheckRender below.
+	if len([]rune(*d.Summary)) > domain.MaxSummaryLength {
+		return fmt.Errorf("%s.summary cannot be longer than %d characters", path, domain.MaxSummaryLength)
 	}
 
 	// Google Calendar API has a limit on description field (typically around 8192 characters)
-	if len([]rune(*c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.Description)) > 8000 {
-		return fmt.Errorf("writeTo.googleCalendar.ifTodayIsFreezeDay.default.description cannot be longer than 8000 characters")
+	if len([]rune(*d.Description)) > domain.MaxDescriptionLength {
+		return fmt.Errorf("%s.description cannot be longer than %d characters", path, domain.MaxDescriptionLength)
+	}
+
+	if d.ColorID != nil {
+		if n, err := strconv.Atoi(*d.ColorID); err != nil || n < 1 || n > 11 {
+			return fmt.Errorf("%s.colorId must be a Google Calendar event colour from \"1\" to \"11\", got %q", path, *d.ColorID)
+		}
+	}
+	if d.Visibility != nil && !slices.Contains(supportedVisibilities, *d.Visibility) {
+		return fmt.Errorf("%s.visibility must be one of %v, got %q", path, supportedVisibilities, *d.Visibility)
+	}
+	if d.Transparency != nil && !slices.Contains(supportedTransparencies, *d.Transparency) {
+		return fmt.Errorf("%s.transparency must be one of %v, got %q", path, supportedTransparencies, *d.Transparency)
+	}
+	if d.Reminders != nil {
+		if len(*d.Reminders) > maxReminders {
+			return fmt.Errorf("%s.reminders cannot have more than %d entries", path, maxReminders)
+		}
+		for i, r := range *d.Reminders {
+			if !slices.Contains(supportedReminderMethods, r.Method) {
+				return fmt.Errorf("%s.reminders[%d].method must be one of %v, got %q", path, i, supportedReminderMethods, r.Method)
+			}
+			if r.Minutes < 0 || r.Minutes > maxReminderMinutes {
+				return fmt.Errorf("%s.reminders[%d].minutes must be between 0 and %d, got %d", path, i, maxReminderMinutes, r.Minutes)
+			}
+		}
+	}
+	if d.Attendees != nil {
+		if err := validateAttendees(*d.Attendees); err != nil {
+			return fmt.Errorf("%s.attendees: %w", path, err)
+		}
+	}
+
+	// Summary and description are templates; render them for a sample day to catch
+	// syntax errors and unknown fields now rather than during a sync.
+	sample := domain.BlockerTemplate{Summary: *d.Summary, Description: *d.Description}
+	if err := sample.CheckRender(); err != nil {
+		return fmt.Errorf("%s: invalid summary or description template: %w", path, err)
 	}
 
 	// All-day events do not use startTime/endTime — skip time validation.
-	allDay := c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.AllDay
-	if allDay != nil && *allDay {
+	if d.AllDay != nil && *d.AllDay {
 		return nil
 	}
 
-	startT, err := parseHHMM(*c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.StartTime)
+	startT, err := parseHHMM(*d.StartTime)
 	if err != nil {
-		return fmt.Errorf("writeTo.googleCalendar.ifTodayIsFreezeDay.default.startTime: %w", err)
+		return fmt.Errorf("%s.startTime: %w", path, err)
 	}
-	endT, err := parseHHMM(*c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default.EndTime)
+	endT, err := parseHHMM(*d.EndTime)
 	if err != nil {
-		return fmt.Errorf("writeTo.googleCalendar.ifTodayIsFreezeDay.default.endTime: %w", err)
+		return fmt.Errorf("%s.endTime: %w", path, err)
 	}
 	if !startT.Before(endT) {
-		return fmt.Errorf("writeTo.googleCalendar.ifTodayIsFreezeDay.default.startTime must be before endTime")
+		return fmt.Errorf("%s.startTime must be before endTime", path)
 	}
 
 	return nil
 }
 
+// validateAttendees checks that every attendee is a bare email address, listed once.
+func validateAttendees(emails []string) error {
+	seen := make(map[string]bool, len(emails))
+	for i, email := range emails {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email || addr.Name != "" {
+			return fmt.Errorf("entry %d: %q is not an email address like oncall@example.com", i+1, email)
+		}
+		key := strings.ToLower(email)
+		if seen[key] {
+			return fmt.Errorf("entry %d: %q is listed twice", i+1, email)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ValidateTemplateReferences checks that every template picked by a rule group or freeze
+// window is defined in writeTo.googleCalendar.ifTodayIsFreezeDay.templates.
+func (c *Config) ValidateTemplateReferences() error {
+	templates := c.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates
+	check := func(where, name string) error {
+		if _, ok := templates[name]; !ok {
+			return fmt.Errorf("%s uses template %q, which is not defined. Defined templates: %v", where, name, slices.Sorted(maps.Keys(templates)))
+		}
+		return nil
+	}
+	for i, rule := range c.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf {
+		names, ok := rule[domain.RuleTemplateKey]
+		if !ok {
+			continue
+		}
+		if len(names) != 1 {
+			return fmt.Errorf("todayIsFreezeDayIf group #%d: template must name exactly one template", i+1)
+		}
+		if err := check(fmt.Sprintf("todayIsFreezeDayIf group #%d", i+1), names[0]); err != nil {
+			return err
+		}
+	}
+	for _, w := range c.ReadFrom.FreezeWindows {
+		if w.Template == "" {
+			continue
+		}
+		if err := check(fmt.Sprintf("freeze window %q", w.Name), w.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func parseHHMM(s string) (time.Time, error) {
 	t, err := time.Parse("15:04", s)
 	if err != nil {
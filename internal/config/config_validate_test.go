@@ -53,7 +53,20 @@ func configsEqual(a, b *Config) bool {
 		return false
 	}
 
-	return true
+	if !stringPtrsEqual(aDefault.ColorID, bDefault.ColorID) || !stringPtrsEqual(aDefault.Visibility, bDefault.Visibility) {
+		return false
+	}
+
+	if !stringPtrsEqual(aDefault.Transparency, bDefault.Transparency) || !boolPtrsEqual(aDefault.GuestsCanSeeOtherGuests, bDefault.GuestsCanSeeOtherGuests) {
+		return false
+	}
+
+	if !reflect.DeepEqual(aDefault.Reminders, bDefault.Reminders) {
+		return false
+	}
+
+	// DeepEqual follows pointers, so named templates compare by value
+	return reflect.DeepEqual(a.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates, b.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates)
 }
 
 // boolPtrsEqual compares two bool pointers by their values
@@ -94,6 +107,32 @@ func Test_ConfigValidate(t *testing.T) {
 		{name: "invalid_endTime_format", yaml: mockConfigYamlInvalidEndTimeFormat, want: nil},
 		{name: "invalid_startTime_after_endTime", yaml: mockConfigYamlInvalidStartAfterEnd, want: nil},
 		{name: "invalid_startTime_equals_endTime", yaml: mockConfigYamlInvalidStartEqualsEnd, want: nil},
+		{name: "valid_offline_holidays", yaml: mockConfigYamlOfflineHolidays, want: mockOfflineHolidaysParsedConfig},
+		{name: "invalid_no_holiday_source", yaml: mockConfigYamlNoHolidaySource, want: nil},
+		{name: "invalid_icsFile_path", yaml: mockConfigYamlInvalidICSPath, want: nil},
+		{name: "invalid_holidayList_date", yaml: mockConfigYamlInvalidHolidayListDate, want: nil},
+		{name: "invalid_override_kind", yaml: mockConfigYamlInvalidOverrideKind, want: nil},
+		{name: "valid_multiple_countries", yaml: mockConfigYamlMultipleCountries, want: mockMultipleCountriesParsedConfig},
+		{name: "invalid_countryCombination", yaml: mockConfigYamlInvalidCountryCombination, want: nil},
+		{name: "invalid_both_country_fields", yaml: mockConfigYamlInvalidBothCountryFields, want: nil},
+		{name: "invalid_holidayPolicy_contradiction", yaml: mockConfigYamlInvalidHolidayPolicy, want: nil},
+		{name: "valid_rule_expr", yaml: mockConfigYamlRuleExpr, want: mockRuleExprParsedConfig},
+		{name: "invalid_rule_expr_type", yaml: mockConfigYamlInvalidRuleExprType, want: nil},
+		{name: "invalid_rule_expr_syntax", yaml: mockConfigYamlInvalidRuleExprSyntax, want: nil},
+		{name: "valid_offset_anchors", yaml: mockConfigYamlOffsetAnchors, want: mockOffsetAnchorsParsedConfig},
+		{name: "invalid_anchor", yaml: mockConfigYamlInvalidAnchor, want: nil},
+		{name: "valid_freeze_windows", yaml: mockConfigYamlFreezeWindows, want: mockFreezeWindowsParsedConfig},
+		{name: "invalid_freeze_window_range", yaml: mockConfigYamlInvalidFreezeWindowRange, want: nil},
+		{name: "invalid_freeze_window_kinds", yaml: mockConfigYamlInvalidFreezeWindowKinds, want: nil},
+		{name: "valid_templates", yaml: mockConfigYamlTemplates, want: mockTemplatesParsedConfig},
+		{name: "invalid_template_reference", yaml: mockConfigYamlInvalidTemplateReference, want: nil},
+		{name: "invalid_template_color", yaml: mockConfigYamlInvalidTemplateColor, want: nil},
+		{name: "invalid_summary_template", yaml: mockConfigYamlInvalidSummaryTemplate, want: nil},
+		{name: "invalid_transparency", yaml: mockConfigYamlInvalidTransparency, want: nil},
+		{name: "invalid_reminder", yaml: mockConfigYamlInvalidReminder, want: nil},
+		{name: "invalid_attendee", yaml: mockConfigYamlInvalidAttendee, want: nil},
+		{name: "invalid_send_updates", yaml: mockConfigYamlInvalidSendUpdates, want: nil},
+		{name: "invalid_on_manual_edit", yaml: mockConfigYamlInvalidOnManualEdit, want: nil},
 	}
 
 	for _, test := range tests {
@@ -301,3 +301,752 @@ writeTo:
         summary: "Today is FREEZE-DAY. no PROD operation is allowed." 
         description: "Managed by tgifreezeday, do not modify."
 `
+
+const mockConfigYamlOfflineHolidays = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: ""
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+  icsFile:
+    path: "company/holidays.ics"
+  holidayList:
+    - date: "2026-12-30"
+      name: "Year-end shutdown"
+  overrides:
+    - date: "2026-05-04"
+      is: businessDay
+      reason: "Working holiday"
+    - date: "2026-08-14"
+      is: nonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        allDay: true
+`
+
+var mockOfflineHolidaysParsedConfig = &Config{
+	Shared: SharedConfig{
+		LookbackDays:  20,
+		LookaheadDays: 60,
+	},
+	ReadFrom: ReadFromConfig{
+		GoogleCalendar: GoogleCalendarReadConfig{
+			TodayIsFreezeDayIf: []map[string][]string{
+				{testAnchorToday: []string{testCondNonBusiness}},
+			},
+		},
+		ICSFile: &ICSFileReadConfig{Path: "company/holidays.ics"},
+		HolidayList: []HolidayListEntry{
+			{Date: "2026-12-30", Name: "Year-end shutdown"},
+		},
+		Overrides: []OverrideEntry{
+			{Date: "2026-05-04", Is: OverrideBusinessDay, Reason: "Working holiday"},
+			{Date: "2026-08-14", Is: OverrideNonBusinessDay},
+		},
+	},
+	WriteTo: WriteToConfig{
+		GoogleCalendar: GoogleCalendarWriteConfig{
+			ID: "example-freeze@example.com",
+			IfTodayIsFreezeDay: IfTodayIsFreezeDayConfig{
+				Default: DefaultConfig{
+					Summary:     helpers.StringPtr("Today is FREEZE-DAY. no PROD operation is allowed."),
+					Description: helpers.StringPtr("Managed by tgifreezeday, do not modify."),
+					AllDay:      helpers.BoolPtr(true),
+				},
+			},
+		},
+	},
+}
+
+const mockConfigYamlNoHolidaySource = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: ""
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidICSPath = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+  icsFile:
+    path: "../../etc/holidays.ics"
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidHolidayListDate = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+  holidayList:
+    - date: "2026/12/30"
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidOverrideKind = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+  overrides:
+    - date: "2026-05-04"
+      is: workday
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlMultipleCountries = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCodes: ["jpn", "vnm"]
+    countryCombination: all
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        allDay: true
+`
+
+var mockMultipleCountriesParsedConfig = &Config{
+	Shared: SharedConfig{
+		LookbackDays:  20,
+		LookaheadDays: 60,
+	},
+	ReadFrom: ReadFromConfig{
+		GoogleCalendar: GoogleCalendarReadConfig{
+			CountryCodes:       []string{"jpn", "vnm"},
+			CountryCombination: CountryCombinationAll,
+			TodayIsFreezeDayIf: []map[string][]string{
+				{testAnchorToday: []string{testCondNonBusiness}},
+			},
+		},
+	},
+	WriteTo: WriteToConfig{
+		GoogleCalendar: GoogleCalendarWriteConfig{
+			ID: "example-freeze@example.com",
+			IfTodayIsFreezeDay: IfTodayIsFreezeDayConfig{
+				Default: DefaultConfig{
+					Summary:     helpers.StringPtr("Today is FREEZE-DAY. no PROD operation is allowed."),
+					Description: helpers.StringPtr("Managed by tgifreezeday, do not modify."),
+					AllDay:      helpers.BoolPtr(true),
+				},
+			},
+		},
+	},
+}
+
+const mockConfigYamlInvalidCountryCombination = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCodes: ["jpn", "vnm"]
+    countryCombination: majority
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidBothCountryFields = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    countryCodes: ["vnm"]
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidHolidayPolicy = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+  holidayPolicy:
+    include: ["Christmas Eve"]
+    exclude: ["christmas eve"]
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlRuleExpr = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - expr:
+        - isNthBusinessDayOfMonth(3) or isLastWeekdayOfQuarter(friday)
+        - not at(tomorrow, isNonBusinessDay)
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+var mockRuleExprParsedConfig = &Config{
+	Shared: SharedConfig{
+		LookbackDays:  20,
+		LookaheadDays: 60,
+	},
+	ReadFrom: ReadFromConfig{
+		GoogleCalendar: GoogleCalendarReadConfig{
+			CountryCode: "jpn",
+			TodayIsFreezeDayIf: []map[string][]string{
+				{"expr": []string{
+					"isNthBusinessDayOfMonth(3) or isLastWeekdayOfQuarter(friday)",
+					"not at(tomorrow, isNonBusinessDay)",
+				}},
+				{testAnchorToday: []string{testCondNonBusiness}},
+			},
+		},
+	},
+	WriteTo: WriteToConfig{
+		GoogleCalendar: GoogleCalendarWriteConfig{
+			ID: "example-freeze@example.com",
+			IfTodayIsFreezeDay: IfTodayIsFreezeDayConfig{
+				Default: DefaultConfig{
+					Summary:     helpers.StringPtr("Today is FREEZE-DAY. no PROD operation is allowed."),
+					Description: helpers.StringPtr("Managed by tgifreezeday, do not modify."),
+					StartTime:   helpers.StringPtr("08:00"),
+					EndTime:     helpers.StringPtr("20:00"),
+				},
+			},
+		},
+	},
+}
+
+const mockConfigYamlInvalidRuleExprType = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - expr:
+        - isLastWeekdayOfQuarter(5)
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidRuleExprSyntax = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - expr:
+        - isHoliday and (not isWeekend
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlOffsetAnchors = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - yesterday:
+        - isNonBusinessDay
+      - nextDay:
+        - isNonBusinessDay
+      - "+1bd":
+        - isTheFirstBusinessDayOfTheMonth
+      - "-3d":
+        - isTheLastBusinessDayOfTheMonth
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+var mockOffsetAnchorsParsedConfig = &Config{
+	Shared: SharedConfig{
+		LookbackDays:  20,
+		LookaheadDays: 60,
+	},
+	ReadFrom: ReadFromConfig{
+		GoogleCalendar: GoogleCalendarReadConfig{
+			CountryCode: "jpn",
+			TodayIsFreezeDayIf: []map[string][]string{
+				{"yesterday": []string{testCondNonBusiness}},
+				{"nextDay": []string{testCondNonBusiness}},
+				{"+1bd": []string{"isTheFirstBusinessDayOfTheMonth"}},
+				{"-3d": []string{"isTheLastBusinessDayOfTheMonth"}},
+			},
+		},
+	},
+	WriteTo: WriteToConfig{
+		GoogleCalendar: GoogleCalendarWriteConfig{
+			ID: "example-freeze@example.com",
+			IfTodayIsFreezeDay: IfTodayIsFreezeDayConfig{
+				Default: DefaultConfig{
+					Summary:     helpers.StringPtr("Today is FREEZE-DAY. no PROD operation is allowed."),
+					Description: helpers.StringPtr("Managed by tgifreezeday, do not modify."),
+					StartTime:   helpers.StringPtr("08:00"),
+					EndTime:     helpers.StringPtr("20:00"),
+				},
+			},
+		},
+	},
+}
+
+const mockConfigYamlInvalidAnchor = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - "+2w":
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlFreezeWindows = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf: []
+  freezeWindows:
+    - name: Year-end
+      from: "12-24"
+      to: "01-03"
+      yearly: true
+    - name: Product launch
+      from: "2026-11-10"
+      to: "2026-11-11"
+    - name: Quarter close
+      lastBusinessDaysOfQuarter: 5
+      fiscalYearStartMonth: 4
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+var mockFreezeWindowsParsedConfig = &Config{
+	Shared: SharedConfig{
+		LookbackDays:  20,
+		LookaheadDays: 60,
+	},
+	ReadFrom: ReadFromConfig{
+		GoogleCalendar: GoogleCalendarReadConfig{
+			CountryCode:        "jpn",
+			TodayIsFreezeDayIf: []map[string][]string{},
+		},
+		FreezeWindows: []FreezeWindowEntry{
+			{Name: "Year-end", From: "12-24", To: "01-03", Yearly: true},
+			{Name: "Product launch", From: "2026-11-10", To: "2026-11-11"},
+			{Name: "Quarter close", LastBusinessDaysOfQuarter: 5, FiscalYearStartMonth: 4},
+		},
+	},
+	WriteTo: WriteToConfig{
+		GoogleCalendar: GoogleCalendarWriteConfig{
+			ID: "example-freeze@example.com",
+			IfTodayIsFreezeDay: IfTodayIsFreezeDayConfig{
+				Default: DefaultConfig{
+					Summary:     helpers.StringPtr("Today is FREEZE-DAY. no PROD operation is allowed."),
+					Description: helpers.StringPtr("Managed by tgifreezeday, do not modify."),
+					StartTime:   helpers.StringPtr("08:00"),
+					EndTime:     helpers.StringPtr("20:00"),
+				},
+			},
+		},
+	},
+}
+
+const mockConfigYamlInvalidFreezeWindowRange = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+  freezeWindows:
+    - name: Backwards
+      from: "2026-11-11"
+      to: "2026-11-10"
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidFreezeWindowKinds = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+  freezeWindows:
+    - name: Both
+      from: "12-24"
+      to: "01-03"
+      yearly: true
+      lastBusinessDaysOfQuarter: 5
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlTemplates = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isTheLastBusinessDayOfTheMonth
+        template: [monthEnd]
+      - tomorrow:
+        - isNonBusinessDay
+  freezeWindows:
+    - name: Year-end
+      from: "12-24"
+      to: "01-03"
+      yearly: true
+      template: yearEnd
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        allDay: true
+      templates:
+        monthEnd:
+          summary: "Month-end freeze"
+          allDay: false
+          colorId: "11"
+        yearEnd:
+          summary: "Year-end freeze"
+          visibility: private
+`
+
+var mockTemplatesParsedConfig = &Config{
+	Shared: SharedConfig{
+		LookbackDays:  20,
+		LookaheadDays: 60,
+	},
+	ReadFrom: ReadFromConfig{
+		GoogleCalendar: GoogleCalendarReadConfig{
+			CountryCode: "jpn",
+			TodayIsFreezeDayIf: []map[string][]string{
+				{"today": []string{"isTheLastBusinessDayOfTheMonth"}, "template": []string{"monthEnd"}},
+				{testAnchorTomorrow: []string{testCondNonBusiness}},
+			},
+		},
+		FreezeWindows: []FreezeWindowEntry{
+			{Name: "Year-end", From: "12-24", To: "01-03", Yearly: true, Template: "yearEnd"},
+		},
+	},
+	WriteTo: WriteToConfig{
+		GoogleCalendar: GoogleCalendarWriteConfig{
+			ID: "example-freeze@example.com",
+			IfTodayIsFreezeDay: IfTodayIsFreezeDayConfig{
+				Default: DefaultConfig{
+					Summary:     helpers.StringPtr("Today is FREEZE-DAY. no PROD operation is allowed."),
+					Description: helpers.StringPtr("Managed by tgifreezeday, do not modify."),
+					AllDay:      helpers.BoolPtr(true),
+				},
+				Templates: map[string]DefaultConfig{
+					"monthEnd": {
+						Summary: helpers.StringPtr("Month-end freeze"),
+						AllDay:  helpers.BoolPtr(false),
+						ColorID: helpers.StringPtr("11"),
+					},
+					"yearEnd": {
+						Summary:    helpers.StringPtr("Year-end freeze"),
+						Visibility: helpers.StringPtr("private"),
+					},
+				},
+			},
+		},
+	},
+}
+
+const mockConfigYamlInvalidTemplateReference = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+        template: [missing]
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+const mockConfigYamlInvalidTemplateColor = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+        template: [red]
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      templates:
+        red:
+          colorId: "12"
+`
+
+const mockConfigYamlInvalidSummaryTemplate = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - tomorrow:
+        - isNonBusinessDay
+        template: [beforeHoliday]
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      templates:
+        beforeHoliday:
+          summary: "🚫 Freeze: tomorrow is {{.NextHolidayNme}}"
+`
+
+const mockConfigYamlValidSummaryTemplate = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - tomorrow:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        summary: "🚫 Freeze: tomorrow is {{.NextHolidayName}}"
+        description: "{{.ConfigName}}: back on {{.NextBusinessDay.Format \"Mon Jan 2\"}}"
+`
+
+const mockConfigYamlInvalidTransparency = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        transparency: opaque
+`
+
+const mockConfigYamlInvalidReminder = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        reminders:
+          - method: sms
+            minutes: 10
+`
+
+const mockConfigYamlInvalidAttendee = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        attendees:
+          - oncall@example.com
+          - OnCall@example.com
+`
+
+const mockConfigYamlInvalidSendUpdates = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      sendUpdates: everyone
+`
+
+const mockConfigYamlInvalidOnManualEdit = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    onManualEdit: ignore
+`
+
+const mockConfigYamlEventSettings = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - today:
+        - isNonBusinessDay
+      - tomorrow:
+        - isNonBusinessDay
+        template: [quiet]
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        colorId: "11"
+        transparency: busy
+        guestsCanSeeOtherGuests: false
+        reminders:
+          - method: email
+            minutes: 1440
+          - method: popup
+            minutes: 30
+        attendees:
+          - Release-Managers@example.com
+          - oncall@example.com
+      templates:
+        quiet:
+          transparency: free
+          reminders: []
+          attendees: []
+      sendUpdates: externalOnly
+`
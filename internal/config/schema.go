@@ -1,9 +1,20 @@
 package config
 
-import _ "embed"
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/nvat/tgifreezeday/internal/consts"
+)
 
 //go:embed schema-v1.yaml
-var schemaV1YAML []byte
+var schemaV1Template string
+
+// countryEnumPlaceholder in the schema is replaced by the codes of the country registry.
+const countryEnumPlaceholder = "__COUNTRY_CODES__"
+
+var schemaV1YAML = []byte(strings.Replace(schemaV1Template, countryEnumPlaceholder,
+	strings.Join(consts.SupportedCountries, ", "), 1))
 
 const CurrentSchemaVersion = "v1"
 
@@ -1,8 +1,64 @@
 package consts
 
+// Country is a country whose Google public holiday calendar can be read.
+type Country struct {
+	Code       string // ISO 3166-1 alpha-3 country code, as used in readFrom.googleCalendar.countryCode
+	Name       string // Display name
+	CalendarID string // Google public holiday calendar ID
+}
+
+// Countries is the registry of supported countries, ordered by display name.
+// Validation, the config form dropdown and the schema enum are all generated from it,
+// so adding a country only needs a new entry here.
+var Countries = []Country{
+	{Code: "aus", Name: "Australia", CalendarID: "en.australian#holiday@group.v.calendar.google.com"},
+	{Code: "bra", Name: "Brazil", CalendarID: "en.brazilian#holiday@group.v.calendar.google.com"},
+	{Code: "can", Name: "Canada", CalendarID: "en.canadian#holiday@group.v.calendar.google.com"},
+	{Code: "chn", Name: "China", CalendarID: "en.china#holiday@group.v.calendar.google.com"},
+	{Code: "fra", Name: "France", CalendarID: "en.french#holiday@group.v.calendar.google.com"},
+	{Code: "deu", Name: "Germany", CalendarID: "en.german#holiday@group.v.calendar.google.com"},
+	{Code: "hkg", Name: "Hong Kong", CalendarID: "en.hong_kong#holiday@group.v.calendar.google.com"},
+	{Code: "ind", Name: "India", CalendarID: "en.indian#holiday@group.v.calendar.google.com"},
+	{Code: "idn", Name: "Indonesia", CalendarID: "en.indonesian#holiday@group.v.calendar.google.com"},
+	{Code: "irl", Name: "Ireland", CalendarID: "en.irish#holiday@group.v.calendar.google.com"},
+	{Code: "ita", Name: "Italy", CalendarID: "en.italian#holiday@group.v.calendar.google.com"},
+	{Code: "jpn", Name: "Japan", CalendarID: "ja.japanese#holiday@group.v.calendar.google.com"},
+	{Code: "mys", Name: "Malaysia", CalendarID: "en.malaysia#holiday@group.v.calendar.google.com"},
+	{Code: "nld", Name: "Netherlands", CalendarID: "en.dutch#holiday@group.v.calendar.google.com"},
+	{Code: "phl", Name: "Philippines", CalendarID: "en.philippines#holiday@group.v.calendar.google.com"},
+	{Code: "sgp", Name: "Singapore", CalendarID: "en.singapore#holiday@group.v.calendar.google.com"},
+	{Code: "kor", Name: "South Korea", CalendarID: "en.south_korea#holiday@group.v.calendar.google.com"},
+	{Code: "esp", Name: "Spain", CalendarID: "en.spain#holiday@group.v.calendar.google.com"},
+	{Code: "twn", Name: "Taiwan", CalendarID: "en.taiwan#holiday@group.v.calendar.google.com"},
+	{Code: "tha", Name: "Thailand", CalendarID: "en.th#holiday@group.v.calendar.google.com"},
+	{Code: "gbr", Name: "United Kingdom", CalendarID: "en.uk#holiday@group.v.calendar.google.com"},
+	{Code: "usa", Name: "United States", CalendarID: "en.usa#holiday@group.v.calendar.google.com"},
+	{Code: "vnm", Name: "Vietnam", CalendarID: "vi.vietnamese#holiday@group.v.calendar.google.com"},
+}
+
 // List of supported countries for holiday calendars
 // ISO 3166-1 alpha-3 country codes
-var SupportedCountries = []string{
-	"jpn",
-	"vnm",
+var SupportedCountries = countryCodes()
+
+func countryCodes() []string {
+	codes := make([]string, 0, len(Countries))
+	for _, c := range Countries {
+		codes = append(codes, c.Code)
+	}
+	return codes
+}
+
+// LookupCountry returns the registry entry for an ISO 3166-1 alpha-3 code.
+func LookupCountry(code string) (Country, bool) {
+	for _, c := range Countries {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Country{}, false
+}
+
+// Label returns the display label used in the UI, e.g. "Japan (jpn)".
+func (c Country) Label() string {
+	return c.Name + " (" + c.Code + ")"
 }
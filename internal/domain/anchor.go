@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// maxAnchorOffset bounds anchor offsets; anything further is outside any sensible lookahead.
+const maxAnchorOffset = 366
+
+// Anchor is the day a freeze rule is evaluated on, relative to the day being synced.
+// It is either a calendar-day offset or a business-day offset.
+//
+// Grammar, shared by config validation and evaluation:
+//
+//	anchor = "yesterday" | "today" | "tomorrow" | "nextDay" | ("+" | "-") digits ("d" | "bd")
+//
+// e.g. "-3d" is three calendar days earlier and "+1bd" is the next business day.
+type Anchor struct {
+	Offset       int
+	BusinessDays bool
+}
+
+// namedAnchors are the anchor names kept from the original config format.
+var namedAnchors = map[string]Anchor{
+	"yesterday": {Offset: -1},
+	"today":     {Offset: 0},
+	"tomorrow":  {Offset: 1},
+	"nextDay":   {Offset: 1},
+}
+
+var anchorOffsetPattern = regexp.MustCompile(`^([+-]\d+)(d|bd)$`)
+
+// ParseAnchor parses an anchor such as "today", "-3d" or "+1bd".
+func ParseAnchor(s string) (Anchor, error) {
+	if a, ok := namedAnchors[s]; ok {
+		return a, nil
+	}
+	m := anchorOffsetPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Anchor{}, fmt.Errorf("unsupported anchor %q: use yesterday, today, tomorrow, nextDay, or an offset like -3d, +2d, +1bd", s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n < -maxAnchorOffset || n > maxAnchorOffset {
+		return Anchor{}, fmt.Errorf("anchor %q: offset must be within ±%d", s, maxAnchorOffset)
+	}
+	return Anchor{Offset: n, BusinessDays: m[2] == "bd"}, nil
+}
+
+// String returns a human-readable description, e.g. "next business day".
+func (a Anchor) String() string {
+	unit, units := "day", "days"
+	if a.BusinessDays {
+		unit, units = "business day", "business days"
+	}
+	switch {
+	case a.Offset == 0:
+		return "today"
+	case a.Offset == 1:
+		return "next " + unit
+	case a.Offset == -1:
+		return "previous " + unit
+	case a.Offset > 0:
+		return fmt.Sprintf("%d %s later", a.Offset, units)
+	default:
+		return fmt.Sprintf("%d %s earlier", -a.Offset, units)
+	}
+}
+
+// At returns the day the anchor points to from d, or nil when it is outside the mapping.
+func (d *TGIFDay) At(a Anchor) *TGIFDay {
+	if a.BusinessDays {
+		return d.OffsetBusinessDays(a.Offset)
+	}
+	return d.Offset(a.Offset)
+}
+
+// OffsetBusinessDays returns the nth business day after d, or before it when n is negative.
+// d itself is not counted, so OffsetBusinessDays(1) is the next business day even when d is
+// not a business day. Returns nil when the walk leaves the mapping.
+func (d *TGIFDay) OffsetBusinessDays(n int) *TGIFDay {
+	if n == 0 {
+		return d
+	}
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	cur := d
+	for n > 0 {
+		cur = cur.lookup(step)
+		if cur == nil {
+			logger.Warnf("parentMapping ends before business day offset from %s", d.Key)
+			return nil
+		}
+		if cur.IsBusinessDay {
+			n--
+		}
+	}
+	return cur
+}
@@ -0,0 +1,85 @@
+package domain
+
+import "testing"
+
+func TestParseAnchor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Anchor
+		wantErr bool
+	}{
+		{in: "yesterday", want: Anchor{Offset: -1}},
+		{in: "today", want: Anchor{}},
+		{in: "nextDay", want: Anchor{Offset: 1}},
+		{in: "-3d", want: Anchor{Offset: -3}},
+		{in: "+2d", want: Anchor{Offset: 2}},
+		{in: "+1bd", want: Anchor{Offset: 1, BusinessDays: true}},
+		{in: "-10bd", want: Anchor{Offset: -10, BusinessDays: true}},
+		{in: "2d", wantErr: true},
+		{in: "+2w", wantErr: true},
+		{in: "+1 bd", wantErr: true},
+		{in: "+400d", wantErr: true},
+		{in: "Today", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseAnchor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAnchor(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAnchor(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffsetBusinessDays(t *testing.T) {
+	// 2026-09-14 .. 2026-09-27, with a holiday on Mon 2026-09-21.
+	m := newTestMapping(date("2026-09-14"), 14, "2026-09-21")
+	fri := (*m)["2026-09-18"]
+
+	for n, want := range map[int]DateKey{
+		0:  "2026-09-18",
+		1:  "2026-09-22", // skips the weekend and the holiday
+		2:  "2026-09-23",
+		-1: "2026-09-17",
+	} {
+		if got := fri.OffsetBusinessDays(n); got == nil || got.Key != want {
+			t.Errorf("OffsetBusinessDays(%d) = %v, want %s", n, got, want)
+		}
+	}
+	// Sun 09-20 is not a business day; +1bd is still the next one.
+	if got := (*m)["2026-09-20"].OffsetBusinessDays(1); got == nil || got.Key != "2026-09-22" {
+		t.Errorf("OffsetBusinessDays(1) from Sunday = %v, want 2026-09-22", got)
+	}
+	if got := fri.OffsetBusinessDays(10); got != nil {
+		t.Errorf("OffsetBusinessDays(10) = %s, want nil past the mapping", got.Key)
+	}
+}
+
+func TestFreezeRules_Match_Anchors(t *testing.T) {
+	m := newTestMapping(date("2026-09-14"), 14, "2026-09-21")
+	tests := []struct {
+		anchor string
+		day    DateKey
+		want   bool
+	}{
+		// nextDay used to be accepted by validation and then panic during sync.
+		{"nextDay", "2026-09-18", true},
+		{"-3d", "2026-09-24", true},
+		{"+1bd", "2026-09-18", false},
+		{"+2d", "2026-09-17", true},
+		// Out of the mapping: never a freeze day, no panic.
+		{"+5d", "2026-09-26", false},
+	}
+	for _, tt := range tests {
+		rules, err := NewFreezeRules(TodayIsFreezeDayIf{{tt.anchor: {ruleIsNonBusiness}}}, nil)
+		if err != nil {
+			t.Fatalf("NewFreezeRules(%s): %v", tt.anchor, err)
+		}
+		if _, got := rules.Match((*m)[tt.day]); got != tt.want {
+			t.Errorf("Match(%s: %s) = %v, want %v", tt.anchor, tt.day, got, tt.want)
+		}
+	}
+}
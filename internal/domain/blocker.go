@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BlockerSignature is appended to every blocker description so people reading the
+// calendar know the event is managed by the app.
+const BlockerSignature = "Managed by tgifreezeday, do not modify."
+
+// Blocker is a managed blocker event on the target calendar. It is used both for the
+// desired state computed from the freeze-day rules and for events read back from the calendar.
+type Blocker struct {
+	EventID string    // Calendar event ID; empty until the blocker is written.
+	Date    time.Time // The freeze day this blocker covers (midnight UTC), the first one when merged.
+	// EndDate is the last freeze day of a blocker merged over consecutive days (midnight
+	// UTC); zero for a single-day blocker.
+	EndDate     time.Time
+	Summary     string
+	Description string // Full event description, including BlockerSignature.
+	StartTime   string // "HH:MM" in the calendar's timezone; empty for all-day blockers.
+	EndTime     string
+	AllDay      bool
+	ColorID     string // Google Calendar event colour "1".."11"; empty for the calendar's colour.
+	Visibility  string // "public" or "private"; empty for the calendar's default.
+	Free        bool   // Shown as available in free/busy instead of busy.
+	// Reminders overrides the calendar's default reminders; nil keeps the defaults and an
+	// empty slice turns reminders off. Sorted, see SortReminders.
+	Reminders       []Reminder
+	HideOtherGuests bool // Guests cannot see who else is invited.
+	// Attendees are the invited email addresses, lower-cased and sorted.
+	Attendees []string
+	// SendUpdates is who Google Calendar notifies when the blocker is written, one of the
+	// SendUpdates* values; empty for nobody. It is a write option, not event content.
+	SendUpdates string
+	// Rule is the label of the freeze window or rule group that produced the blocker, see
+	// FreezeRules.Match. Empty for blockers read back from the calendar and not planned.
+	Rule string
+	// MovedByHand marks an event people moved by hand, whose Date and EndDate have been
+	// set back to where the app wrote it so that the sync moves it back. It never has the
+	// same content as a desired blocker.
+	MovedByHand bool
+}
+
+// Key returns the date key of the freeze day this blocker covers.
+func (b *Blocker) Key() DateKey {
+	return NewDateKey(b.Date)
+}
+
+// LastDate returns the last freeze day the blocker covers.
+func (b *Blocker) LastDate() time.Time {
+	if b.EndDate.After(b.Date) {
+		return b.EndDate
+	}
+	return b.Date
+}
+
+// SameContent reports whether two blockers render as the same calendar event.
+// Event IDs are ignored.
+func (b *Blocker) SameContent(o *Blocker) bool {
+	if b.MovedByHand || o.MovedByHand {
+		return false
+	}
+	if b.Key() != o.Key() || b.AllDay != o.AllDay {
+		return false
+	}
+	if NewDateKey(b.LastDate()) != NewDateKey(o.LastDate()) {
+		return false
+	}
+	if b.Summary != o.Summary || b.Description != o.Description {
+		return false
+	}
+	if b.ColorID != o.ColorID || b.Visibility != o.Visibility {
+		return false
+	}
+	if b.Free != o.Free || b.HideOtherGuests != o.HideOtherGuests || !sameReminders(b.Reminders, o.Reminders) {
+		return false
+	}
+	if !slices.Equal(b.Attendees, o.Attendees) {
+		return false
+	}
+	if b.AllDay {
+		return true
+	}
+	return b.StartTime == o.StartTime && b.EndTime == o.EndTime
+}
+
+// sameForAttendees reports whether attendees see the same event in both blockers: the same
+// summary on the same days at the same times. Other changes, such as a new description or
+// colour, are no reason to notify them again.
+func (b *Blocker) sameForAttendees(o *Blocker) bool {
+	if b.MovedByHand || o.MovedByHand {
+		return false
+	}
+	if b.Key() != o.Key() || NewDateKey(b.LastDate()) != NewDateKey(o.LastDate()) || b.AllDay != o.AllDay {
+		return false
+	}
+	if b.Summary != o.Summary {
+		return false
+	}
+	return b.AllDay || (b.StartTime == o.StartTime && b.EndTime == o.EndTime)
+}
+
+// BlockerTemplate describes the blocker event written on each freeze day. Summary and
+// Description may be templates, see BlockerData.
+type BlockerTemplate struct {
+	Summary     string
+	Description string
+	StartTime   string // "HH:MM"; ignored for all-day blockers.
+	EndTime     string
+	AllDay      bool
+	ColorID     string
+	Visibility  string
+
+	Free            bool
+	Reminders       []Reminder
+	HideOtherGuests bool
+	Attendees       []string // Lower-cased and sorted, see NormalizeAttendees.
+
+	parsed *parsedBlockerText // Set by Parse.
+}
+
+// Who Google Calendar notifies when a blocker with attendees is created or changed.
+const (
+	SendUpdatesAll          = "all"
+	SendUpdatesExternalOnly = "externalOnly"
+	SendUpdatesNone         = "none"
+)
+
+// NormalizeAttendees lower-cases and sorts attendee emails, so that attendee lists compare
+// equal whatever case and order the config or the calendar uses.
+func NormalizeAttendees(emails []string) []string {
+	out := make([]string, 0, len(emails))
+	for _, e := range emails {
+		out = append(out, strings.ToLower(e))
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Reminder is a notification before a blocker event starts.
+type Reminder struct {
+	Method  string // "popup" or "email".
+	Minutes int
+}
+
+// SortReminders orders reminders by method and minutes, so that reminder lists compare
+// equal whatever order the config or the calendar lists them in.
+func SortReminders(reminders []Reminder) {
+	slices.SortFunc(reminders, func(a, b Reminder) int {
+		if c := cmp.Compare(a.Method, b.Method); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Minutes, b.Minutes)
+	})
+}
+
+// sameReminders reports whether two sorted reminder lists are the same; nil (the calendar's
+// defaults) differs from an empty list (no reminders).
+func sameReminders(a, b []Reminder) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return slices.Equal(a, b)
+}
+
+// BlockerTemplates are the default template and the named templates freeze rules can pick.
+type BlockerTemplates struct {
+	Default BlockerTemplate
+	Named   map[string]BlockerTemplate
+	// ConfigName is available to summary and description templates, see BlockerData.
+	ConfigName string
+	// MergeConsecutiveDays writes one blocker per run of consecutive freeze days that pick
+	// the same template, instead of one per day.
+	MergeConsecutiveDays bool
+	// SendUpdates is who is notified when blockers are written, see Blocker.SendUpdates.
+	SendUpdates string
+}
+
+// For returns the named template, or the default one when name is empty or unknown.
+func (t *BlockerTemplates) For(name string) BlockerTemplate {
+	if tmpl, ok := t.Named[name]; ok {
+		return tmpl
+	}
+	return t.Default
+}
+
+// BlockerOn renders the template into the blocker for the given freeze day.
+func (t BlockerTemplate) BlockerOn(date time.Time) *Blocker {
+	b := &Blocker{
+		Date:        date,
+		Summary:     t.Summary,
+		Description: signedDescription(t.Description),
+		AllDay:      t.AllDay,
+		ColorID:     t.ColorID,
+		Visibility:  t.Visibility,
+
+		Free:            t.Free,
+		Reminders:       t.Reminders,
+		HideOtherGuests: t.HideOtherGuests,
+		Attendees:       t.Attendees,
+	}
+	if !t.AllDay {
+		b.StartTime = t.StartTime
+		b.EndTime = t.EndTime
+	}
+	return b
+}
+
+// signedDescription combines a custom description with the blocker signature.
+func signedDescription(description string) string {
+	if description == "" || description == BlockerSignature {
+		return BlockerSignature
+	}
+	return description + BlockerSignature
+}
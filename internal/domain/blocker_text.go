@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// Longest rendered summary and description, signature included, that Google Calendar accepts.
+const (
+	MaxSummaryLength     = 250
+	MaxDescriptionLength = 8000
+)
+
+// BlockerData is what blocker summaries and descriptions are rendered with. The summary
+// is a text/template and the description an html/template, so values inserted into the
+// description are HTML-escaped. The fields of the freeze day are promoted, e.g.
+//
+//	🚫 Freeze: tomorrow is {{.NextHolidayName}}
+//	{{.Date.Format "Mon Jan 2"}} ({{.Rule}}), back on {{.NextBusinessDay.Format "Jan 2"}}
+type BlockerData struct {
+	*TGIFDay
+
+	Rule       string // Label of the window or rule group that made the day a freeze day.
+	ConfigName string
+	// NextBusinessDay is the first business day after the freeze day; zero when it is
+	// past the end of the synced range.
+	NextBusinessDay time.Time
+	// HolidayName is the holiday that caused the freeze: the freeze day's own holiday,
+	// or NextHolidayName when the day is not a holiday itself.
+	HolidayName string
+	// NextHolidayName is the first holiday between the freeze day and the next business
+	// day, e.g. the holiday a Friday blocker precedes; empty when there is none.
+	NextHolidayName string
+}
+
+// NewBlockerData gathers the template data for a freeze day.
+func NewBlockerData(d *TGIFDay, rule, configName string) *BlockerData {
+	data := &BlockerData{TGIFDay: d, Rule: rule, ConfigName: configName}
+	for cur := d.lookup(1); cur != nil; cur = cur.lookup(1) {
+		if cur.IsBusinessDay {
+			data.NextBusinessDay = cur.Date
+			break
+		}
+		if data.NextHolidayName == "" && len(cur.HolidayNames) > 0 {
+			data.NextHolidayName = cur.HolidayNames[0]
+		}
+	}
+	data.HolidayName = data.NextHolidayName
+	if len(d.HolidayNames) > 0 {
+		data.HolidayName = d.HolidayNames[0]
+	}
+	return data
+}
+
+// sampleBlockerData is the day templates are checked against when a config is saved:
+// Thu 2026-12-24, the day before Christmas, with New Year's Day the week after.
+func sampleBlockerData() *BlockerData {
+	start := time.Date(2026, time.December, 21, 0, 0, 0, 0, time.UTC)
+	mapping, err := BuildTGIFMapping(start, start.AddDate(0, 0, 21), &BusinessCalendar{
+		Sources: []HolidaySource{&HolidayList{Label: "sample", Holidays: []Holiday{
+			{Date: time.Date(2026, time.December, 25, 0, 0, 0, 0, time.UTC), Name: "Christmas Day", Kind: HolidayKindPublic},
+			{Date: time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), Name: "New Year's Day", Kind: HolidayKindPublic},
+		}}},
+	})
+	if err != nil {
+		panic(err) // a HolidayList never fails
+	}
+	return NewBlockerData((*mapping)["2026-12-24"], DayRuleLabel(0), "Sample config")
+}
+
+// parsedBlockerText is a template's summary and description after parsing. A nil template
+// is text without template actions, which is used as is.
+type parsedBlockerText struct {
+	summary     *template.Template
+	description *htmltemplate.Template
+	err         error
+}
+
+// Parse parses the summary and description once, so that Render reuses them for every
+// freeze day. A template that is not parsed is parsed by each Render.
+func (t *BlockerTemplate) Parse() error {
+	t.parsed = parseBlockerText(t.Summary, t.Description)
+	return t.parsed.err
+}
+
+func parseBlockerText(summary, description string) *parsedBlockerText {
+	p := &parsedBlockerText{}
+	if strings.Contains(summary, "{{") {
+		if p.summary, p.err = template.New("summary").Option("missingkey=error").Parse(summary); p.err != nil {
+			return p
+		}
+	}
+	if strings.Contains(description, "{{") {
+		p.description, p.err = htmltemplate.New("description").Option("missingkey=error").Parse(description)
+	}
+	return p
+}
+
+// Render renders the template's summary and description for a freeze day. Text without
+// template actions is used as is.
+func (t BlockerTemplate) Render(data *BlockerData) (*Blocker, error) {
+	p := t.parsed
+	if p == nil {
+		p = parseBlockerText(t.Summary, t.Description)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	summary, description := t.Summary, t.Description
+	var err error
+	if p.summary != nil {
+		if summary, err = execute(p.summary, data); err != nil {
+			return nil, err
+		}
+	}
+	if p.description != nil {
+		if description, err = execute(p.description, data); err != nil {
+			return nil, err
+		}
+	}
+	b := t.BlockerOn(data.Date)
+	b.Summary = summary
+	b.Description = signedDescription(description)
+	b.Rule = data.Rule
+	return b, nil
+}
+
+// CheckRender parses the template and renders it against a sample day, so that syntax
+// errors, unknown fields and text too long for Google Calendar are reported when the
+// config is saved rather than during a sync.
+func (t BlockerTemplate) CheckRender() error {
+	if err := t.Parse(); err != nil {
+		return err
+	}
+	b, err := t.Render(sampleBlockerData())
+	if err != nil {
+		return err
+	}
+	if n := utf8.RuneCountInString(b.Summary); n > MaxSummaryLength {
+		return fmt.Errorf("rendered summary is %d characters for a sample day, longer than %d", n, MaxSummaryLength)
+	}
+	if n := utf8.RuneCountInString(b.Description); n > MaxDescriptionLength {
+		return fmt.Errorf("rendered description is %d characters for a sample day, longer than %d", n, MaxDescriptionLength)
+	}
+	return nil
+}
+
+func execute(tmpl interface{ Execute(io.Writer, any) error }, data *BlockerData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// BlockerFor renders the blocker for a freeze day with the template the match picks. A
+// template that fails to render (which config validation should have caught) is logged
+// and written unrendered rather than failing the whole sync. A summary or description
+// that renders longer than Google Calendar accepts, e.g. with many holiday names, is
+// truncated.
+func (t *BlockerTemplates) BlockerFor(d *TGIFDay, m FreezeMatch) *Blocker {
+	tmpl := t.For(m.Template)
+	b, err := tmpl.Render(NewBlockerData(d, m.Rule, t.ConfigName))
+	if err != nil {
+		logger.Errorf("failed to render blocker template for %s: %v", d.Key, err)
+		b = tmpl.BlockerOn(d.Date)
+		b.Rule = m.Rule
+	}
+	if utf8.RuneCountInString(b.Summary) > MaxSummaryLength {
+		logger.Warnf("truncating the blocker summary for %s to %d characters", d.Key, MaxSummaryLength)
+		b.Summary = truncateRunes(b.Summary, MaxSummaryLength)
+	}
+	if utf8.RuneCountInString(b.Description) > MaxDescriptionLength {
+		logger.Warnf("truncating the blocker description for %s to %d characters", d.Key, MaxDescriptionLength)
+		// Keep the signature, which marks the event as managed by the app.
+		text := strings.TrimSuffix(b.Description, BlockerSignature)
+		b.Description = truncateRunes(text, MaxDescriptionLength-utf8.RuneCountInString(BlockerSignature)) + BlockerSignature
+	}
+	b.SendUpdates = t.SendUpdates
+	return b
+}
+
+// truncateRunes shortens s to at most n characters, ending it with "…" when cut.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// OwnsLegacyBlocker reports whether a blocker written before blockers were tagged with their
+// config belongs to the config with these rules and templates: its day is a freeze day in
+// the mapping and its summary is the one the config renders for that day. Legacy blockers
+// of other configs on a shared calendar fail the check and are left alone.
+func OwnsLegacyBlocker(b *Blocker, mapping *TGIFMapping, rules *FreezeRules, templates *BlockerTemplates) bool {
+	day, ok := (*mapping)[b.Key()]
+	if !ok {
+		return false
+	}
+	m, ok := rules.Match(day)
+	if !ok {
+		return false
+	}
+	return templates.BlockerFor(day, m).Summary == b.Summary
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBlockerTemplates_BlockerFor_RendersTemplates(t *testing.T) {
+	// Mon 2026-05-04 .. Sun 2026-05-17; Wed 05-06 is a holiday and so is Mon 05-11.
+	m := newTestMapping(date("2026-05-04"), 14, "2026-05-06", "2026-05-11")
+	(*m)["2026-05-06"].HolidayNames = []string{"Constitution Day"}
+	(*m)["2026-05-11"].HolidayNames = []string{"Company Day"}
+	templates := &BlockerTemplates{
+		Default: BlockerTemplate{
+			Summary:     "🚫 Freeze: next is {{.NextHolidayName}}",
+			Description: `{{.ConfigName}}: {{.HolidayName}}, back {{.NextBusinessDay.Format "Jan 2"}} ({{.Rule}})<br>`,
+			AllDay:      true,
+		},
+		ConfigName: "Ops <prod>",
+	}
+
+	tue := templates.BlockerFor((*m)["2026-05-05"], FreezeMatch{Rule: DayRuleLabel(0)})
+	if tue.Summary != "🚫 Freeze: next is Constitution Day" {
+		t.Errorf("Summary = %q", tue.Summary)
+	}
+	wantDesc := "Ops &lt;prod&gt;: Constitution Day, back May 7 (todayIsFreezeDayIf #1)<br>" + BlockerSignature
+	if tue.Description != wantDesc {
+		t.Errorf("Description = %q, want %q", tue.Description, wantDesc)
+	}
+	if tue.Rule != DayRuleLabel(0) {
+		t.Errorf("Rule = %q, want %q", tue.Rule, DayRuleLabel(0))
+	}
+
+	// Friday before a weekend and a Monday holiday: the holiday after the weekend counts.
+	fri := templates.BlockerFor((*m)["2026-05-08"], FreezeMatch{})
+	if fri.Summary != "🚫 Freeze: next is Company Day" {
+		t.Errorf("Friday Summary = %q", fri.Summary)
+	}
+
+	// The holiday itself: its own name, and no holiday ahead before Tuesday.
+	mon := NewBlockerData((*m)["2026-05-11"], "", "")
+	if mon.HolidayName != "Company Day" || mon.NextHolidayName != "" || NewDateKey(mon.NextBusinessDay) != "2026-05-12" {
+		t.Errorf("BlockerData on the holiday = %+v", mon)
+	}
+}
+
+func TestBlockerTemplate_CheckRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    BlockerTemplate
+		wantErr bool
+	}{
+		{"static", BlockerTemplate{Summary: "Freeze", Description: "<strong>No deploys</strong>"}, false},
+		{"fields", BlockerTemplate{Summary: "{{.Key}} {{.Rule}} {{if .IsHoliday}}holiday{{end}}", Description: "{{.HolidayName}}"}, false},
+		{"syntax error", BlockerTemplate{Summary: "{{.NextHolidayName"}, true},
+		{"unknown field", BlockerTemplate{Summary: "Freeze", Description: "{{.NextHoliday}}"}, true},
+		{"rendered summary too long", BlockerTemplate{Summary: strings.Repeat("{{.ConfigName}}", 20)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tmpl.CheckRender(); (err != nil) != tt.wantErr {
+				t.Errorf("CheckRender() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBlockerTemplate_Parse(t *testing.T) {
+	m := newTestMapping(date("2026-05-04"), 7)
+	data := NewBlockerData((*m)["2026-05-05"], DayRuleLabel(0), "Ops")
+
+	tmpl := BlockerTemplate{Summary: "Freeze {{.Key}}", Description: "{{.ConfigName}}"}
+	if err := tmpl.Parse(); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	// Render uses what Parse parsed, not the source.
+	tmpl.Summary = "{{.Key"
+	b, err := tmpl.Render(data)
+	if err != nil || b.Summary != "Freeze 2026-05-05" || b.Description != "Ops"+BlockerSignature {
+		t.Errorf("Render() = %+v, %v; want the parsed template rendered", b, err)
+	}
+
+	broken := BlockerTemplate{Summary: "{{.Key"}
+	if err := broken.Parse(); err == nil {
+		t.Fatal("Parse() accepted a syntax error")
+	}
+	if _, err := broken.Render(data); err == nil {
+		t.Error("Render() of a template that failed to parse succeeded")
+	}
+}
+
+func TestBlockerTemplates_BlockerFor_TruncatesLongText(t *testing.T) {
+	mapping := newTestMapping(date("2026-10-01"), 31)
+	templates := &BlockerTemplates{
+		Default:    BlockerTemplate{Summary: "{{.ConfigName}}", Description: "{{.ConfigName}}"},
+		ConfigName: strings.Repeat("x", MaxDescriptionLength),
+	}
+
+	b := templates.BlockerFor((*mapping)["2026-10-03"], FreezeMatch{Rule: DayRuleLabel(0)})
+	if n := utf8.RuneCountInString(b.Summary); n != MaxSummaryLength || !strings.HasSuffix(b.Summary, "…") {
+		t.Errorf("summary is %d characters, want it truncated to %d", n, MaxSummaryLength)
+	}
+	if n := utf8.RuneCountInString(b.Description); n != MaxDescriptionLength || !strings.HasSuffix(b.Description, BlockerSignature) {
+		t.Errorf("description is %d characters, want it truncated to %d with the signature kept", n, MaxDescriptionLength)
+	}
+}
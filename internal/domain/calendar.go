@@ -30,6 +30,15 @@ type TGIFDay struct {
 	IsNonBusinessDay          bool
 	IsFirstBusinessDayOfMonth *bool
 	IsLastBusinessDayOfMonth  *bool
+
+	HolidayNames []string     // Names of the counted holidays on this day, from all holiday sources.
+	Override     *DayOverride // Set when a config override forces this day's business status.
+
+	// Holiday names per ISO 3166-1 alpha-3 country code, from the country holiday calendars.
+	// Nil when no country calendar has a holiday on this day.
+	HolidayNamesByCountry map[string][]string
+	// Every holiday reported on this day, counted or ignored by the policy.
+	Holidays []ClassifiedHoliday
 }
 
 func NewDateKey(date time.Time) DateKey {
@@ -55,6 +64,14 @@ func NewTGIFDay(
 	return day
 }
 
+// applyOverride forces the business status of the day. IsHoliday and IsWeekend keep
+// describing the calendar facts.
+func (d *TGIFDay) applyOverride(o DayOverride) {
+	d.Override = &o
+	d.IsBusinessDay = o.BusinessDay
+	d.IsNonBusinessDay = !o.BusinessDay
+}
+
 // FillMonthInfo fills the month info for each day in the mapping
 // It sets IsFirstBusinessDayOfMonth and IsLastBusinessDayOfMonth for each day
 func (m *TGIFMapping) FillMonthInfo() {
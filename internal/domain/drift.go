@@ -0,0 +1,266 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DriftPolicy decides what a sync does with blocker events people changed by hand.
+type DriftPolicy string
+
+const (
+	// DriftPolicyOverwrite puts edited and deleted blockers back as the rules say. The default.
+	DriftPolicyOverwrite DriftPolicy = "overwrite"
+	// DriftPolicyRespect leaves edited blockers as they are and does not recreate deleted ones.
+	DriftPolicyRespect DriftPolicy = "respect"
+	// DriftPolicyStop writes nothing while there is drift, until someone picks one of the
+	// other policies for a sync.
+	DriftPolicyStop DriftPolicy = "stop"
+)
+
+// Kinds of manual change to a blocker event.
+const (
+	DriftSummary = "summary"
+	DriftTime    = "time"
+	DriftDeleted = "deleted"
+)
+
+// Drift is a manual change to a blocker event since a sync last wrote it.
+type Drift struct {
+	Kind     string // DriftSummary, DriftTime or DriftDeleted.
+	Date     DateKey
+	EventID  string
+	Expected string // What the sync wrote.
+	Actual   string // What the event shows now; empty when it was deleted.
+}
+
+func (d Drift) String() string {
+	switch d.Kind {
+	case DriftDeleted:
+		return fmt.Sprintf("%s: blocker deleted or moved out of the synced range", d.Date)
+	case DriftTime:
+		return fmt.Sprintf("%s: moved from %s to %s", d.Date, d.Expected, d.Actual)
+	default:
+		return fmt.Sprintf("%s: renamed from %q to %q", d.Date, d.Expected, d.Actual)
+	}
+}
+
+// BlockerLedger remembers the blocker events a config's syncs left on the calendar, as
+// they were written. Drift is measured against it, so blockers the rules now want to
+// change are not mistaken for manual edits.
+type BlockerLedger interface {
+	WrittenBlockers() ([]*Blocker, error)
+	RecordWrittenBlockers(blockers []*Blocker) error
+}
+
+// DetectDrift compares the blocker events on the calendar with the ones the ledger says were
+// written for days in [rangeStart, rangeEnd), ordered by date.
+func DetectDrift(written, existing []*Blocker, rangeStart, rangeEnd time.Time) []Drift {
+	existingByID := make(map[string]*Blocker, len(existing))
+	for _, b := range existing {
+		existingByID[b.EventID] = b
+	}
+	var drift []Drift
+	for _, w := range written {
+		if w.Date.Before(rangeStart) || !w.Date.Before(rangeEnd) {
+			continue
+		}
+		b, ok := existingByID[w.EventID]
+		if !ok {
+			drift = append(drift, Drift{Kind: DriftDeleted, Date: w.Key(), EventID: w.EventID, Expected: SpanLabel(w)})
+			continue
+		}
+		if b.Summary != w.Summary {
+			drift = append(drift, Drift{Kind: DriftSummary, Date: w.Key(), EventID: w.EventID, Expected: w.Summary, Actual: b.Summary})
+		}
+		if SpanLabel(b) != SpanLabel(w) {
+			drift = append(drift, Drift{Kind: DriftTime, Date: w.Key(), EventID: w.EventID, Expected: SpanLabel(w), Actual: SpanLabel(b)})
+		}
+	}
+	sort.SliceStable(drift, func(i, j int) bool { return drift[i].Date < drift[j].Date })
+	return drift
+}
+
+// SpanLabel describes when a blocker takes place, e.g. "2026-08-29 08:00-20:00" or
+// "2026-08-29..2026-08-31 all day".
+func SpanLabel(b *Blocker) string {
+	days := string(b.Key())
+	if last := NewDateKey(b.LastDate()); last != b.Key() {
+		days += ".." + string(last)
+	}
+	if b.AllDay {
+		return days + " all day"
+	}
+	return days + " " + b.StartTime + "-" + b.EndTime
+}
+
+// PlanSyncWithDrift plans the sync like PlanSync after comparing the calendar with the
+// blockers the last sync wrote. The drift found is recorded on the plan and handled by the
+// policy: overwrite patches edited blockers back (moving moved ones back to their day) and
+// recreates deleted ones; respect keeps edited blockers and leaves the days of deleted ones
+// empty; stop plans like overwrite, but Stopped tells the caller not to apply the plan.
+func PlanSyncWithDrift(
+	mapping *TGIFMapping,
+	existing, written []*Blocker,
+	rules *FreezeRules,
+	templates *BlockerTemplates,
+	policy DriftPolicy,
+) *SyncPlan {
+	days := mapping.sortedDays()
+	var drift []Drift
+	if len(days) > 0 {
+		drift = DetectDrift(written, existing, days[0].Date, days[len(days)-1].Date.AddDate(0, 0, 1))
+	}
+	if len(drift) == 0 {
+		return PlanSync(mapping, existing, rules, templates)
+	}
+
+	writtenByID := make(map[string]*Blocker, len(written))
+	for _, w := range written {
+		writtenByID[w.EventID] = w
+	}
+	moved := make(map[string]bool)
+	drifted := make(map[string]bool)
+	for _, d := range drift {
+		drifted[d.EventID] = true
+		if d.Kind == DriftTime {
+			moved[d.EventID] = true
+		}
+	}
+
+	var plan *SyncPlan
+	if policy == DriftPolicyRespect {
+		plan = planRespectingDrift(mapping, existing, writtenByID, drifted, rules, templates)
+	} else {
+		adjusted := make([]*Blocker, 0, len(existing))
+		for _, b := range existing {
+			if moved[b.EventID] {
+				w := writtenByID[b.EventID]
+				back := *b
+				back.Date, back.EndDate, back.MovedByHand = w.Date, w.EndDate, true
+				b = &back
+			}
+			adjusted = append(adjusted, b)
+		}
+		plan = PlanSync(mapping, adjusted, rules, templates)
+	}
+	plan.Drift = drift
+	plan.DriftPolicy = policy
+	return plan
+}
+
+// planRespectingDrift plans the sync without touching drifted events or the days they were
+// written for. Edited events are kept as people left them and the ledger keeps what was
+// written, so the drift is still reported on the next sync.
+func planRespectingDrift(
+	mapping *TGIFMapping,
+	existing []*Blocker,
+	writtenByID map[string]*Blocker,
+	drifted map[string]bool,
+	rules *FreezeRules,
+	templates *BlockerTemplates,
+) *SyncPlan {
+	untouched := make([]*Blocker, 0, len(existing))
+	var edited []*Blocker
+	for _, b := range existing {
+		if drifted[b.EventID] {
+			edited = append(edited, b)
+			continue
+		}
+		untouched = append(untouched, b)
+	}
+	plan := PlanSync(mapping, untouched, rules, templates)
+
+	leftAlone := make(map[DateKey]bool)
+	for id := range drifted {
+		w := writtenByID[id]
+		for d := w.Date; !d.After(w.LastDate()); d = d.AddDate(0, 0, 1) {
+			leftAlone[NewDateKey(d)] = true
+		}
+		plan.respected = append(plan.respected, w)
+	}
+	creates := plan.Create[:0]
+	for _, b := range plan.Create {
+		if !coversAny(b, leftAlone) {
+			creates = append(creates, b)
+		}
+	}
+	plan.Create = creates
+	plan.Keep = append(plan.Keep, edited...)
+	sort.SliceStable(plan.Keep, func(i, j int) bool { return plan.Keep[i].Date.Before(plan.Keep[j].Date) })
+	return plan
+}
+
+// applied reports whether change i went through; ok is nil when nothing was applied.
+func applied(ok []bool, i int) bool {
+	return i < len(ok) && ok[i]
+}
+
+// coversAny reports whether the blocker covers any of the days.
+func coversAny(b *Blocker, days map[DateKey]bool) bool {
+	for d := b.Date; !d.After(b.LastDate()); d = d.AddDate(0, 0, 1) {
+		if days[NewDateKey(d)] {
+			return true
+		}
+	}
+	return false
+}
+
+// Stopped reports whether the plan must not be applied: the drift policy is stop and
+// blockers were changed by hand.
+func (p *SyncPlan) Stopped() bool {
+	return p.DriftPolicy == DriftPolicyStop && len(p.Drift) > 0
+}
+
+// WrittenAfter returns the blockers to record in the ledger once the plan has been applied
+// with the given result. Blockers whose change failed, and drifted ones the plan respected,
+// keep their ledger entry.
+func (p *SyncPlan) WrittenAfter(result *SyncResult, written []*Blocker) []*Blocker {
+	writtenByID := make(map[string]*Blocker, len(written))
+	for _, w := range written {
+		writtenByID[w.EventID] = w
+	}
+	var out []*Blocker
+	seen := make(map[string]bool)
+	add := func(b *Blocker) {
+		if b.EventID != "" && !seen[b.EventID] {
+			seen[b.EventID] = true
+			out = append(out, b)
+		}
+	}
+	previous := func(b *Blocker) *Blocker {
+		if w, ok := writtenByID[b.EventID]; ok {
+			return w
+		}
+		return b
+	}
+
+	for _, w := range p.respected {
+		add(w)
+	}
+	for _, b := range p.Keep {
+		add(b)
+	}
+	for i, b := range p.Create {
+		if applied(result.createdOK, i) {
+			add(b)
+		}
+	}
+	for i, u := range p.Update {
+		if applied(result.updatedOK, i) {
+			patched := *u.Desired
+			patched.EventID = u.Existing.EventID
+			add(&patched)
+		} else {
+			add(previous(u.Existing))
+		}
+	}
+	for i, b := range p.Delete {
+		if !applied(result.deletedOK, i) {
+			add(previous(b))
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out
+}
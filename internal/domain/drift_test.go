@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"testing"
+)
+
+// writtenBlocker is a blocker the last sync wrote as event id on day.
+func writtenBlocker(id, day string) *Blocker {
+	b := testTemplate.BlockerOn(date(day))
+	b.EventID = id
+	return b
+}
+
+func TestDetectDrift(t *testing.T) {
+	written := []*Blocker{
+		writtenBlocker("kept", "2026-05-09"),
+		writtenBlocker("renamed", "2026-05-10"),
+		writtenBlocker("moved", "2026-05-16"),
+		writtenBlocker("deleted", "2026-05-17"),
+		writtenBlocker("outside", "2026-06-20"),
+	}
+	renamed := writtenBlocker("renamed", "2026-05-10")
+	renamed.Summary = "Freeze (ask Kim)"
+	moved := writtenBlocker("moved", "2026-05-15")
+	existing := []*Blocker{writtenBlocker("kept", "2026-05-09"), renamed, moved}
+
+	drift := DetectDrift(written, existing, date("2026-05-04"), date("2026-05-31"))
+
+	want := []Drift{
+		{Kind: DriftSummary, Date: "2026-05-10", EventID: "renamed", Expected: "Freeze", Actual: "Freeze (ask Kim)"},
+		{Kind: DriftTime, Date: "2026-05-16", EventID: "moved", Expected: "2026-05-16 08:00-20:00", Actual: "2026-05-15 08:00-20:00"},
+		{Kind: DriftDeleted, Date: "2026-05-17", EventID: "deleted", Expected: "2026-05-17 08:00-20:00"},
+	}
+	if len(drift) != len(want) {
+		t.Fatalf("drift = %v, want %v", drift, want)
+	}
+	for i := range want {
+		if drift[i] != want[i] {
+			t.Errorf("drift[%d] = %+v, want %+v", i, drift[i], want[i])
+		}
+	}
+}
+
+func TestPlanSyncWithDrift_Overwrite(t *testing.T) {
+	// Sat 2026-05-09 and Sun 2026-05-10 are freeze days. The Saturday blocker was moved to
+	// Friday by hand and the Sunday one deleted.
+	m := newTestMapping(date("2026-05-04"), 7)
+	written := []*Blocker{writtenBlocker("sat", "2026-05-09"), writtenBlocker("sun", "2026-05-10")}
+	existing := []*Blocker{writtenBlocker("sat", "2026-05-08")}
+
+	plan := PlanSyncWithDrift(m, existing, written, todayNonBusiness, testTemplates, DriftPolicyOverwrite)
+
+	if len(plan.Drift) != 2 {
+		t.Fatalf("len(Drift) = %d, want 2", len(plan.Drift))
+	}
+	if len(plan.Update) != 1 || plan.Update[0].Existing.EventID != "sat" || plan.Update[0].Desired.Key() != "2026-05-09" {
+		t.Errorf("Update = %+v, want the moved blocker patched back to 2026-05-09", plan.Update)
+	}
+	if len(plan.Create) != 1 || plan.Create[0].Key() != "2026-05-10" {
+		t.Errorf("Create = %+v, want the deleted blocker recreated on 2026-05-10", plan.Create)
+	}
+	if len(plan.Delete) != 0 {
+		t.Errorf("len(Delete) = %d, want 0", len(plan.Delete))
+	}
+	if plan.Stopped() {
+		t.Error("Stopped() = true, want false")
+	}
+}
+
+func TestPlanSyncWithDrift_Respect(t *testing.T) {
+	m := newTestMapping(date("2026-05-04"), 7)
+	written := []*Blocker{writtenBlocker("sat", "2026-05-09"), writtenBlocker("sun", "2026-05-10")}
+	existing := []*Blocker{writtenBlocker("sat", "2026-05-08")}
+
+	plan := PlanSyncWithDrift(m, existing, written, todayNonBusiness, testTemplates, DriftPolicyRespect)
+
+	if len(plan.Create)+len(plan.Update)+len(plan.Delete) != 0 {
+		t.Errorf("plan changes = %d create, %d update, %d delete; want none", len(plan.Create), len(plan.Update), len(plan.Delete))
+	}
+	if len(plan.Keep) != 1 || plan.Keep[0].Key() != "2026-05-08" {
+		t.Errorf("Keep = %+v, want the moved blocker on 2026-05-08", plan.Keep)
+	}
+
+	// Both drifted blockers stay in the ledger as written, so the drift is still reported.
+	after := plan.WrittenAfter(&SyncResult{}, written)
+	if len(after) != 2 || after[0].Key() != "2026-05-09" || after[1].Key() != "2026-05-10" {
+		t.Errorf("WrittenAfter = %+v, want the written blockers unchanged", after)
+	}
+}
+
+func TestPlanSyncWithDrift_Stop(t *testing.T) {
+	m := newTestMapping(date("2026-05-04"), 7)
+	written := []*Blocker{writtenBlocker("sat", "2026-05-09"), writtenBlocker("sun", "2026-05-10")}
+
+	plan := PlanSyncWithDrift(m, []*Blocker{written[0]}, written, todayNonBusiness, testTemplates, DriftPolicyStop)
+	if !plan.Stopped() {
+		t.Error("Stopped() = false with a deleted blocker, want true")
+	}
+
+	plan = PlanSyncWithDrift(m, written, written, todayNonBusiness, testTemplates, DriftPolicyStop)
+	if plan.Stopped() {
+		t.Error("Stopped() = true without drift, want false")
+	}
+}
+
+func TestWrittenAfter_PartialApply(t *testing.T) {
+	// The Saturday blocker is created, the Sunday one fails, and so does deleting the stale
+	// Friday one; the ledger must list Saturday's new event and still list Friday's.
+	m := newTestMapping(date("2026-05-04"), 7)
+	stale := writtenBlocker("fri", "2026-05-08")
+	plan := PlanSync(m, []*Blocker{stale}, todayNonBusiness, testTemplates)
+
+	result, _ := ApplySyncPlan(&fakeRepo{failOn: "2026-05-10", failOnID: "fri"}, plan)
+	after := plan.WrittenAfter(result, []*Blocker{stale})
+
+	var ids []string
+	for _, b := range after {
+		ids = append(ids, b.EventID)
+	}
+	if len(ids) != 2 || ids[0] != "fri" || ids[1] != "new-2026-05-09" {
+		t.Errorf("WrittenAfter ids = %v, want [fri new-2026-05-09]", ids)
+	}
+}
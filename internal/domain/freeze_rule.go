@@ -1,41 +1,28 @@
 package domain
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 type TodayIsFreezeDayIf []map[string][]string
 
 // v1 config:
 // // ...
 // // todayIsFreezeDayIf:
-// // - [yesterday, today, tomorrow]: # with this block, rules are AND together. To do OR, specify multiple items with same key.
+// // - <anchor>: # yesterday, today, tomorrow, nextDay, -3d, +2d, +1bd... See Anchor. Rules are AND together. To do OR, specify multiple items with same key.
 // //   - isTheFirstBusinessDayOfTheMonth
 // //   - isTheLastBusinessDayOfTheMonth
 // //   - isNonBusinessDay
+// // - expr: # rule expressions, AND together. See RuleExpr.
+// //   - isNthBusinessDayOfMonth(3)
+// //   template: [<name>] # optional, blocker template for days this group matches
 
-func getRelativeDate(relativeDate string) int {
-	switch relativeDate {
-	case "yesterday":
-		return -1
-	case "today":
-		return 0
-	case "tomorrow":
-		return 1
-	default:
-		panic(fmt.Sprintf("invalid relative date: %s", relativeDate))
-	}
-}
-
-func evaluateDateRule(rule string, targetDate *TGIFDay) bool {
-	switch rule {
-	case "isTheFirstBusinessDayOfTheMonth":
-		return targetDate.FnIsFirstBusinessDayOfMonth()
-	case "isTheLastBusinessDayOfTheMonth":
-		return targetDate.FnIsLastBusinessDayOfMonth()
-	case "isNonBusinessDay":
-		return targetDate.FnIsNonBusinessDay()
-	default:
-		panic(fmt.Sprintf("invalid rule: %s", rule))
-	}
+// dateRules are the conditions an anchor of a todayIsFreezeDayIf group can check.
+var dateRules = map[string]func(*TGIFDay) bool{
+	"isTheFirstBusinessDayOfTheMonth": (*TGIFDay).FnIsFirstBusinessDayOfMonth,
+	"isTheLastBusinessDayOfTheMonth":  (*TGIFDay).FnIsLastBusinessDayOfMonth,
+	"isNonBusinessDay":                (*TGIFDay).FnIsNonBusinessDay,
 }
 
 // // - [yesterday, today, tomorrow]: # with this block, rules are AND together. To do OR, specify multiple items with same key.
@@ -52,20 +39,104 @@ func evaluateDateRule(rule string, targetDate *TGIFDay) bool {
 // // // - OR, today is non-business day.
 // // This is a bad example for AND, as it doesn't occur in reality so it's always false, just for the sake of example.
 
-func (d *TGIFDay) IsTodayFreezeDay(rules TodayIsFreezeDayIf) bool {
-	for _, rule := range rules {
-		for relativeDate, rules := range rule {
-			andResult := true
-			targetDate := d.Offset(getRelativeDate(relativeDate))
+// RuleTemplateKey is the key of a todayIsFreezeDayIf group that names the blocker template
+// for days the group matches: `template: [monthEnd]`. It is not a condition.
+const RuleTemplateKey = "template"
 
-			for _, rule := range rules {
-				andResult = andResult && evaluateDateRule(rule, targetDate)
-			}
+// RuleGroupTemplate returns the blocker template the group picks, or "" for the default.
+func RuleGroupTemplate(group map[string][]string) string {
+	if names := group[RuleTemplateKey]; len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// ruleGroup is a todayIsFreezeDayIf group with its anchors and rule expressions parsed,
+// so that evaluating it for every day of a range parses nothing.
+type ruleGroup struct {
+	anchors []anchorRules
+	// exprs must all hold; hasExprs is false when the group has no expr key.
+	exprs    []RuleExpr
+	hasExprs bool
+}
 
-			if andResult {
-				return true // short circuit
+// anchorRules are the conditions, AND'd, checked on the day an anchor points to.
+type anchorRules struct {
+	anchor Anchor
+	checks []func(*TGIFDay) bool
+}
+
+// parseRuleGroup parses one todayIsFreezeDayIf group. Anchors, conditions and expressions
+// that do not parse are reported and never match; config validation rejects them.
+func parseRuleGroup(group map[string][]string) (ruleGroup, error) {
+	var g ruleGroup
+	var errs []error
+	for key, rules := range group {
+		switch key {
+		case RuleTemplateKey:
+			continue
+		case RuleExprKey:
+			g.hasExprs = true
+			for _, src := range rules {
+				expr, err := ParseRuleExpr(src)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("invalid rule expression %q: %w", src, err))
+					g.exprs = append(g.exprs, falseExpr{})
+					continue
+				}
+				g.exprs = append(g.exprs, expr)
 			}
+			continue
+		}
+		anchor, err := ParseAnchor(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		checks := make([]func(*TGIFDay) bool, 0, len(rules))
+		for _, rule := range rules {
+			check, ok := dateRules[rule]
+			if !ok {
+				errs = append(errs, fmt.Errorf("unsupported condition %q for anchor %q", rule, key))
+				check = func(*TGIFDay) bool { return false }
+			}
+			checks = append(checks, check)
+		}
+		g.anchors = append(g.anchors, anchorRules{anchor: anchor, checks: checks})
+	}
+	return g, errors.Join(errs...)
+}
+
+// falseExpr stands in for an expression that does not parse, so its group never matches.
+type falseExpr struct{}
+
+func (falseExpr) Eval(*TGIFDay) bool { return false }
+func (falseExpr) String() string     { return "false" }
+
+// matches reports whether any anchor (or the expressions) of the group matches the day.
+// Within one anchor, all conditions must match.
+func (g ruleGroup) matches(d *TGIFDay) bool {
+	if g.hasExprs && g.evalExprs(d) {
+		return true // short circuit
+	}
+	for _, a := range g.anchors {
+		targetDate := d.At(a.anchor)
+		andResult := true
+		for _, check := range a.checks {
+			andResult = andResult && check(targetDate)
+		}
+		if andResult {
+			return true // short circuit
 		}
 	}
 	return false
 }
+
+func (g ruleGroup) evalExprs(d *TGIFDay) bool {
+	for _, expr := range g.exprs {
+		if !expr.Eval(d) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FreezeWindow is a named span of freeze days declared up front, as opposed to the
+// day-by-day todayIsFreezeDayIf rules. Exactly one kind is set:
+//   - a date range: From..To, inclusive;
+//   - a yearly range: From..To with Yearly set, only month and day are used, and the
+//     range may wrap over the year end (Dec 24 to Jan 3);
+//   - the last LastBusinessDaysOfQuarter business days of each quarter, through the
+//     quarter's last day, with quarters counted from FiscalYearStartMonth.
+type FreezeWindow struct {
+	Name   string
+	From   time.Time
+	To     time.Time
+	Yearly bool
+
+	LastBusinessDaysOfQuarter int
+	FiscalYearStartMonth      time.Month // January when zero.
+
+	Template string // Name of the blocker template for days in the window; empty for the default.
+}
+
+// Contains reports whether the day falls in the window.
+func (w *FreezeWindow) Contains(d *TGIFDay) bool {
+	if d == nil {
+		return false
+	}
+	switch {
+	case w.LastBusinessDaysOfQuarter > 0:
+		return d.isInLastBusinessDaysOfQuarter(w.LastBusinessDaysOfQuarter, w.FiscalYearStartMonth)
+	case w.Yearly:
+		day := monthDay(d.Date)
+		from, to := monthDay(w.From), monthDay(w.To)
+		if from <= to {
+			return from <= day && day <= to
+		}
+		return day >= from || day <= to // wraps over the year end
+	default:
+		return !d.Date.Before(w.From) && !d.Date.After(w.To)
+	}
+}
+
+func monthDay(t time.Time) int {
+	return int(t.Month())*100 + t.Day()
+}
+
+// fiscalQuarterEnd returns the last day of the fiscal quarter containing t.
+func fiscalQuarterEnd(t time.Time, fiscalYearStart time.Month) time.Time {
+	if fiscalYearStart == 0 {
+		fiscalYearStart = time.January
+	}
+	monthsIntoYear := (int(t.Month()) - int(fiscalYearStart) + 12) % 12
+	monthsLeft := 3 - monthsIntoYear%3
+	firstOfNextQuarter := time.Date(t.Year(), t.Month()+time.Month(monthsLeft), 1, 0, 0, 0, 0, t.Location())
+	return firstOfNextQuarter.AddDate(0, 0, -1)
+}
+
+// isInLastBusinessDaysOfQuarter reports whether d is on or after the nth-last business day
+// of its fiscal quarter. False when the rest of the quarter is not in the mapping.
+func (d *TGIFDay) isInLastBusinessDaysOfQuarter(n int, fiscalYearStart time.Month) bool {
+	quarterEnd := fiscalQuarterEnd(d.Date, fiscalYearStart)
+	remaining := 0 // business days in [d, quarterEnd]
+	for offset := 0; !d.Date.AddDate(0, 0, offset).After(quarterEnd); offset++ {
+		cur := d.lookup(offset)
+		if cur == nil {
+			return false // quarter end not known
+		}
+		if cur.IsBusinessDay {
+			remaining++
+		}
+		if remaining > n {
+			return false
+		}
+	}
+	// A non-business day belongs to the window only once the nth-last business day is past.
+	return d.IsBusinessDay || remaining < n
+}
+
+// String describes the window, e.g. "every year 12-24 to 01-03".
+func (w *FreezeWindow) String() string {
+	switch {
+	case w.LastBusinessDaysOfQuarter > 0:
+		s := fmt.Sprintf("last %d business days of each quarter", w.LastBusinessDaysOfQuarter)
+		if w.FiscalYearStartMonth > time.January {
+			s += fmt.Sprintf(" (fiscal year from %s)", w.FiscalYearStartMonth)
+		}
+		return s
+	case w.Yearly:
+		return fmt.Sprintf("every year %s to %s", w.From.Format("01-02"), w.To.Format("01-02"))
+	default:
+		return fmt.Sprintf("%s to %s", w.From.Format(time.DateOnly), w.To.Format(time.DateOnly))
+	}
+}
+
+// FreezeRules is everything that decides whether a day is a freeze day: the named freeze
+// windows and the todayIsFreezeDayIf rule groups. Build it with NewFreezeRules, which
+// parses the rule groups once; a literal parses them on first use.
+type FreezeRules struct {
+	DayRules TodayIsFreezeDayIf
+	Windows  []FreezeWindow
+
+	parseOnce sync.Once
+	parsed    []ruleGroup
+	parseErr  error
+}
+
+// NewFreezeRules builds the rules and parses their anchors and rule expressions. Rule
+// groups that do not parse, which config validation rejects, are reported; the parts of
+// them that do parse still apply.
+func NewFreezeRules(dayRules TodayIsFreezeDayIf, windows []FreezeWindow) (*FreezeRules, error) {
+	r := &FreezeRules{DayRules: dayRules, Windows: windows}
+	r.ruleGroups()
+	return r, r.parseErr
+}
+
+// ruleGroups returns the parsed DayRules, parsing them on first use.
+func (r *FreezeRules) ruleGroups() []ruleGroup {
+	r.parseOnce.Do(func() {
+		var errs []error
+		for i, group := range r.DayRules {
+			g, err := parseRuleGroup(group)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", DayRuleLabel(i), err))
+			}
+			r.parsed = append(r.parsed, g)
+		}
+		if r.parseErr = errors.Join(errs...); r.parseErr != nil {
+			logger.Errorf("skipping invalid freeze rules: %v", r.parseErr)
+		}
+	})
+	return r.parsed
+}
+
+// FreezeMatch is the window or rule group that made a day a freeze day.
+type FreezeMatch struct {
+	Rule     string // Label of the window or rule group.
+	Template string // Blocker template it picks; empty for the default.
+}
+
+// Match returns the window or rule group that makes the day a freeze day. Windows come
+// before rule groups, each in config order. The first match that picks a blocker template
+// wins; when none picks one, the first match is returned with the default template.
+func (r *FreezeRules) Match(d *TGIFDay) (FreezeMatch, bool) {
+	var first *FreezeMatch
+	for i := range r.Windows {
+		w := &r.Windows[i]
+		if !w.Contains(d) {
+			continue
+		}
+		m := FreezeMatch{Rule: FreezeWindowLabel(w.Name), Template: w.Template}
+		if m.Template != "" {
+			return m, true
+		}
+		if first == nil {
+			first = &m
+		}
+	}
+	for i, g := range r.ruleGroups() {
+		if !g.matches(d) {
+			continue
+		}
+		m := FreezeMatch{Rule: DayRuleLabel(i), Template: RuleGroupTemplate(r.DayRules[i])}
+		if m.Template != "" {
+			return m, true
+		}
+		if first == nil {
+			first = &m
+		}
+	}
+	if first == nil {
+		return FreezeMatch{}, false
+	}
+	return *first, true
+}
+
+// FreezeWindowLabel is the rule label of blockers produced by the named window.
+func FreezeWindowLabel(name string) string {
+	return "freezeWindow: " + name
+}
+
+// DayRuleLabel is the rule label of blockers produced by the todayIsFreezeDayIf group at index i.
+func DayRuleLabel(i int) string {
+	return fmt.Sprintf("todayIsFreezeDayIf #%d", i+1)
+}
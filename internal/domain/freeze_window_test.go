@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFreezeWindow_Contains(t *testing.T) {
+	// 2026-12-01 .. 2027-01-31; Fri 2026-12-25 is a holiday.
+	m := newTestMapping(date("2026-12-01"), 62, "2026-12-25")
+	yearEnd := FreezeWindow{Name: "Year-end", From: date("0000-12-24"), To: date("0000-01-03"), Yearly: true}
+	launch := FreezeWindow{Name: "Launch", From: date("2026-12-10"), To: date("2026-12-11")}
+	// Q4 2026 ends Thu 12-31; its last 3 business days are 12-29, 12-30 and 12-31.
+	quarterClose := FreezeWindow{Name: "Quarter close", LastBusinessDaysOfQuarter: 3}
+	// With a fiscal year starting in February, a quarter ends on 2027-01-31 (Sun);
+	// its last 2 business days are Thu 01-28 and Fri 01-29.
+	fiscalClose := FreezeWindow{Name: "Fiscal close", LastBusinessDaysOfQuarter: 2, FiscalYearStartMonth: time.February}
+
+	tests := []struct {
+		w    FreezeWindow
+		day  DateKey
+		want bool
+	}{
+		{yearEnd, "2026-12-23", false},
+		{yearEnd, "2026-12-24", true},
+		{yearEnd, "2027-01-03", true},
+		{yearEnd, "2027-01-04", false},
+		{launch, "2026-12-09", false},
+		{launch, "2026-12-11", true},
+		{quarterClose, "2026-12-28", false},
+		{quarterClose, "2026-12-29", true},
+		{quarterClose, "2026-12-31", true},
+		// Q1 2027 does not end inside the mapping: unknown, never matches.
+		{quarterClose, "2027-01-29", false},
+		{fiscalClose, "2027-01-27", false},
+		{fiscalClose, "2027-01-28", true},
+		{fiscalClose, "2027-01-30", true},  // weekend after the window started
+		{fiscalClose, "2027-01-24", false}, // weekend before it
+	}
+	for _, tt := range tests {
+		if got := tt.w.Contains((*m)[tt.day]); got != tt.want {
+			t.Errorf("%s (%s).Contains(%s) = %v, want %v", tt.w.Name, tt.w.String(), tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestPlanSync_TagsBlockersWithRule(t *testing.T) {
+	// Mon 2026-05-04 .. Sun 2026-05-10.
+	m := newTestMapping(date("2026-05-04"), 7)
+	rules := &FreezeRules{
+		DayRules: TodayIsFreezeDayIf{{ruleToday: {ruleIsNonBusiness}}},
+		Windows:  []FreezeWindow{{Name: "Launch", From: date("2026-05-06"), To: date("2026-05-09")}},
+	}
+
+	plan := PlanSync(m, nil, rules, testTemplates)
+
+	want := map[DateKey]string{
+		"2026-05-06": FreezeWindowLabel("Launch"),
+		"2026-05-07": FreezeWindowLabel("Launch"),
+		"2026-05-08": FreezeWindowLabel("Launch"),
+		"2026-05-09": FreezeWindowLabel("Launch"), // in both; the window wins
+		"2026-05-10": DayRuleLabel(0),
+	}
+	if len(plan.Create) != len(want) {
+		t.Fatalf("len(Create) = %d, want %d", len(plan.Create), len(want))
+	}
+	for _, b := range plan.Create {
+		if b.Rule != want[b.Key()] {
+			t.Errorf("blocker on %s Rule = %q, want %q", b.Key(), b.Rule, want[b.Key()])
+		}
+	}
+}
+
+func TestPlanSync_TemplatePrecedence(t *testing.T) {
+	// Mon 2026-08-24 .. Mon 2026-08-31; Mon 08-31 is the last business day of the month.
+	m := newTestMapping(date("2026-08-24"), 8)
+	rules := &FreezeRules{
+		DayRules: TodayIsFreezeDayIf{
+			{ruleToday: {ruleIsNonBusiness}},
+			{ruleToday: {"isTheLastBusinessDayOfTheMonth"}, RuleTemplateKey: {"monthEnd"}},
+			{"tomorrow": {ruleIsNonBusiness}, RuleTemplateKey: {"beforeBreak"}},
+		},
+	}
+	templates := &BlockerTemplates{
+		Default: testTemplate,
+		Named: map[string]BlockerTemplate{
+			"monthEnd":    {Summary: "Month-end freeze", AllDay: true, ColorID: "11"},
+			"beforeBreak": {Summary: "Weekend ahead", StartTime: "15:00", EndTime: "20:00", Visibility: "private"},
+		},
+	}
+
+	plan := PlanSync(m, nil, rules, templates)
+
+	want := map[DateKey]struct{ summary, rule string }{
+		"2026-08-28": {"Weekend ahead", DayRuleLabel(2)},
+		// Saturday matches group 1 (no template) and group 3 (tomorrow is Sunday): the template wins.
+		"2026-08-29": {"Weekend ahead", DayRuleLabel(2)},
+		// Sunday only matches group 1: default template.
+		"2026-08-30": {testBlockerSummary, DayRuleLabel(0)},
+		"2026-08-31": {"Month-end freeze", DayRuleLabel(1)},
+	}
+	if len(plan.Create) != len(want) {
+		t.Fatalf("len(Create) = %d, want %d", len(plan.Create), len(want))
+	}
+	for _, b := range plan.Create {
+		w := want[b.Key()]
+		if b.Summary != w.summary || b.Rule != w.rule {
+			t.Errorf("blocker on %s = (%q, %q), want (%q, %q)", b.Key(), b.Summary, b.Rule, w.summary, w.rule)
+		}
+	}
+	if b := plan.Create[3]; b.ColorID != "11" || !b.AllDay {
+		t.Errorf("month-end blocker = %+v, want all-day with colour 11", b)
+	}
+	if b := plan.Create[0]; b.Visibility != "private" || b.StartTime != "15:00" {
+		t.Errorf("before-break blocker = %+v, want private from 15:00", b)
+	}
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Holiday is a day reported by a holiday source. Whether it counts as a non-business day
+// is decided by the config's HolidayPolicy.
+type Holiday struct {
+	Date time.Time
+	Name string
+	// Country is the ISO 3166-1 alpha-3 code for country holiday calendars, empty otherwise.
+	Country string
+	Kind    HolidayKind
+	// Description is the source's own type text, e.g. "Public holiday", kept for display.
+	Description string
+	// IgnoreReason, when set by a source, makes the policy ignore the holiday with this reason.
+	IgnoreReason string
+}
+
+// HolidaySource provides the non-business days in a date range, e.g. a public holiday
+// calendar, a local iCalendar file, or a list of company holidays.
+// It is separate from TGIFCalendarRepository, which only manages the target calendar.
+type HolidaySource interface {
+	// Name identifies the source in error messages and on the config detail page.
+	Name() string
+	// HolidaysInRange returns the holidays in [rangeStart, rangeEnd).
+	HolidaysInRange(rangeStart, rangeEnd time.Time) ([]Holiday, error)
+}
+
+// HolidayList is a fixed list of holidays, e.g. company shutdown days declared in the config.
+type HolidayList struct {
+	Label    string
+	Holidays []Holiday
+}
+
+func (l *HolidayList) Name() string {
+	return l.Label
+}
+
+func (l *HolidayList) HolidaysInRange(rangeStart, rangeEnd time.Time) ([]Holiday, error) {
+	var out []Holiday
+	for _, h := range l.Holidays {
+		if !h.Date.Before(rangeStart) && h.Date.Before(rangeEnd) {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+// HolidayIntersection counts a day only when every member source has a holiday on it that
+// the policy counts, e.g. the holidays shared by all of a team's countries. The holidays of
+// all members are returned so that each country's holiday name is kept; on days not shared
+// by every member they carry an IgnoreReason.
+type HolidayIntersection struct {
+	Label   string
+	Sources []HolidaySource
+	Policy  HolidayPolicy
+}
+
+func (h *HolidayIntersection) Name() string {
+	return h.Label
+}
+
+func (h *HolidayIntersection) HolidaysInRange(rangeStart, rangeEnd time.Time) ([]Holiday, error) {
+	byKey := make(map[DateKey][]Holiday)
+	sourcesByKey := make(map[DateKey]int)
+	for _, src := range h.Sources {
+		holidays, err := src.HolidaysInRange(rangeStart, rangeEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read holidays from %s: %w", src.Name(), err)
+		}
+		seen := make(map[DateKey]bool)
+		for _, hol := range holidays {
+			key := NewDateKey(hol.Date)
+			byKey[key] = append(byKey[key], hol)
+			if !seen[key] && h.Policy.counts(hol) {
+				seen[key] = true
+				sourcesByKey[key]++
+			}
+		}
+	}
+
+	var out []Holiday
+	for key, holidays := range byKey {
+		shared := sourcesByKey[key] == len(h.Sources)
+		for _, hol := range holidays {
+			if !shared && hol.IgnoreReason == "" && h.Policy.counts(hol) {
+				hol.IgnoreReason = "not a holiday in every country (" + h.Label + ")"
+			}
+			out = append(out, hol)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
+
+// DayOverride forces a date to be a business or non-business day, whatever its weekday
+// and holidays say, e.g. a national holiday the company works on.
+type DayOverride struct {
+	Date        time.Time
+	BusinessDay bool
+	Reason      string
+}
+
+// BusinessCalendar is everything that decides whether a day is a business day:
+// the holiday sources, the policy that classifies their holidays, and the per-date
+// overrides applied on top of them.
+type BusinessCalendar struct {
+	Sources   []HolidaySource
+	Policy    HolidayPolicy
+	Overrides []DayOverride
+}
+
+// ClassifyHolidaysInRange reads every source and classifies its holidays in
+// [rangeStart, rangeEnd) with the calendar's policy, ordered by date.
+func (cal *BusinessCalendar) ClassifyHolidaysInRange(rangeStart, rangeEnd time.Time) ([]ClassifiedHoliday, error) {
+	var out []ClassifiedHoliday
+	for _, src := range cal.Sources {
+		holidays, err := src.HolidaysInRange(rangeStart, rangeEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read holidays from %s: %w", src.Name(), err)
+		}
+		for _, h := range holidays {
+			counted, reason := cal.Policy.Classify(h)
+			out = append(out, ClassifiedHoliday{Holiday: h, Source: src.Name(), Counted: counted, Reason: reason})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
+
+// BuildTGIFMapping builds the mapping for [rangeStart, rangeEnd) from the merged holidays
+// of all sources. A day is a holiday if the policy counts a holiday any source reports on it.
+// Overrides are applied before the first and last business days of each month are computed.
+func BuildTGIFMapping(rangeStart, rangeEnd time.Time, cal *BusinessCalendar) (*TGIFMapping, error) {
+	classified, err := cal.ClassifyHolidaysInRange(rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	holidaysByKey := make(map[DateKey][]ClassifiedHoliday)
+	holidayNames := make(map[DateKey][]string)
+	countryNames := make(map[DateKey]map[string][]string)
+	for _, h := range classified {
+		key := NewDateKey(h.Date)
+		holidaysByKey[key] = append(holidaysByKey[key], h)
+		if !h.Counted {
+			continue
+		}
+		holidayNames[key] = append(holidayNames[key], h.Name)
+		if h.Country != "" {
+			if countryNames[key] == nil {
+				countryNames[key] = make(map[string][]string)
+			}
+			countryNames[key][h.Country] = append(countryNames[key][h.Country], h.Name)
+		}
+	}
+
+	tgifMapping := make(TGIFMapping)
+	for currDate := rangeStart; currDate.Before(rangeEnd); currDate = currDate.AddDate(0, 0, 1) {
+		dateKey := NewDateKey(currDate)
+		names, isHoliday := holidayNames[dateKey]
+
+		tgifDay := NewTGIFDay(currDate, &tgifMapping, isHoliday)
+		tgifDay.HolidayNames = names
+		tgifDay.HolidayNamesByCountry = countryNames[dateKey]
+		tgifDay.Holidays = holidaysByKey[dateKey]
+		tgifMapping[dateKey] = tgifDay
+	}
+	for _, o := range cal.Overrides {
+		if day, ok := tgifMapping[NewDateKey(o.Date)]; ok {
+			day.applyOverride(o)
+		}
+	}
+	// CRITICAL: Fill month info for first/last business day calculations
+	// This is required for freeze day rules to work properly
+	tgifMapping.FillMonthInfo()
+
+	return &tgifMapping, nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HolidayKind is what a holiday source says a holiday is.
+type HolidayKind string
+
+const (
+	// HolidayKindDeclared is a day declared as a holiday by the config itself, e.g. a company
+	// holiday list or iCalendar file. Always counted unless excluded by name.
+	HolidayKindDeclared HolidayKind = ""
+	// HolidayKindPublic is a nationwide public holiday.
+	HolidayKindPublic HolidayKind = "public"
+	// HolidayKindRegional is a holiday only in some regions of the country.
+	HolidayKindRegional HolidayKind = "regional"
+	// HolidayKindObservance is a cultural or commemorative day that is normally a working day.
+	HolidayKindObservance HolidayKind = "observance"
+	// HolidayKindUnknown is an event the source could not classify.
+	HolidayKindUnknown HolidayKind = "unknown"
+)
+
+// HolidayPolicy decides which holidays count as non-business days.
+// Name lists are matched case-insensitively against the holiday name; exclusions win.
+type HolidayPolicy struct {
+	CountObservances      bool
+	CountRegionalHolidays bool
+	Include               []string // Names always counted, whatever their kind.
+	Exclude               []string // Names never counted.
+}
+
+// ClassifiedHoliday is a holiday with the policy's decision and the reason for it.
+type ClassifiedHoliday struct {
+	Holiday
+	Source  string // Name of the holiday source that reported it.
+	Counted bool
+	Reason  string
+}
+
+func containsFold(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(n string) bool {
+		return strings.EqualFold(strings.TrimSpace(n), strings.TrimSpace(name))
+	})
+}
+
+// Classify reports whether the holiday counts as a non-business day and why.
+func (p *HolidayPolicy) Classify(h Holiday) (bool, string) {
+	if h.IgnoreReason != "" {
+		return false, h.IgnoreReason
+	}
+	if containsFold(p.Exclude, h.Name) {
+		return false, "excluded by name"
+	}
+	if containsFold(p.Include, h.Name) {
+		return true, "included by name"
+	}
+	switch h.Kind {
+	case HolidayKindDeclared:
+		return true, "declared in config"
+	case HolidayKindPublic:
+		return true, "public holiday"
+	case HolidayKindRegional:
+		if p.CountRegionalHolidays {
+			return true, "regional holiday, counted by policy"
+		}
+		return false, "regional holiday"
+	case HolidayKindObservance:
+		if p.CountObservances {
+			return true, "observance, counted by policy"
+		}
+		return false, "observance"
+	default:
+		if h.Description != "" {
+			return false, fmt.Sprintf("unrecognized holiday type %q", h.Description)
+		}
+		return false, "unrecognized holiday type"
+	}
+}
+
+// counts reports whether the policy counts the holiday.
+func (p *HolidayPolicy) counts(h Holiday) bool {
+	counted, _ := p.Classify(h)
+	return counted
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "testing"
+
+func TestHolidayPolicy_Classify(t *testing.T) {
+	policy := HolidayPolicy{
+		CountObservances: true,
+		Include:          []string{"Christmas Eve"},
+		Exclude:          []string{"vernal equinox day"},
+	}
+	tests := []struct {
+		name    string
+		holiday Holiday
+		counted bool
+		reason  string
+	}{
+		{"public", Holiday{Name: "New Year's Day", Kind: HolidayKindPublic}, true, "public holiday"},
+		{"declared", Holiday{Name: "Shutdown"}, true, "declared in config"},
+		{"observance counted by policy", Holiday{Name: "Valentine's Day", Kind: HolidayKindObservance}, true, "observance, counted by policy"},
+		{"regional ignored", Holiday{Name: "Carnival", Kind: HolidayKindRegional}, false, "regional holiday"},
+		{"excluded wins over kind", Holiday{Name: "Vernal Equinox Day", Kind: HolidayKindPublic}, false, "excluded by name"},
+		{"included whatever the kind", Holiday{Name: "christmas eve", Kind: HolidayKindUnknown}, true, "included by name"},
+		{"unknown", Holiday{Name: "Something", Kind: HolidayKindUnknown, Description: "Feast"}, false, `unrecognized holiday type "Feast"`},
+		{"ignored by source", Holiday{Name: "Tết", Kind: HolidayKindPublic, IgnoreReason: "not shared"}, false, "not shared"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counted, reason := policy.Classify(tt.holiday)
+			if counted != tt.counted || reason != tt.reason {
+				t.Errorf("Classify() = (%v, %q), want (%v, %q)", counted, reason, tt.counted, tt.reason)
+			}
+		})
+	}
+}
+
+func TestBuildTGIFMapping_KeepsIgnoredHolidays(t *testing.T) {
+	src := &HolidayList{Label: "jpn", Holidays: []Holiday{
+		{Date: date("2026-02-14"), Name: "Valentine's Day", Kind: HolidayKindObservance},
+		{Date: date("2026-02-11"), Name: "National Foundation Day", Kind: HolidayKindPublic},
+	}}
+	m, err := BuildTGIFMapping(date("2026-02-09"), date("2026-02-16"), &BusinessCalendar{Sources: []HolidaySource{src}})
+	if err != nil {
+		t.Fatalf("BuildTGIFMapping() error = %v", err)
+	}
+	valentine := (*m)["2026-02-14"]
+	if valentine.IsHoliday || len(valentine.Holidays) != 1 || valentine.Holidays[0].Counted {
+		t.Errorf("2026-02-14 = %+v, want an ignored observance that is not a holiday", valentine)
+	}
+	if !(*m)["2026-02-11"].IsHoliday {
+		t.Error("2026-02-11 should be a holiday")
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"time"
 )
 
+// TGIFCalendarRepository manages the blocker events on the target calendar.
 type TGIFCalendarRepository interface {
-	GetFreezeDaysInRange(rangeStart, rangeEnd time.Time) (*TGIFMapping, error)
+	ListBlockersInRange(startDate, endDate time.Time) ([]*Blocker, error)
 	WipeAllBlockersInRange(startDate, endDate time.Time) error
-	WriteBlockerOnDate(date time.Time, summary, description, startTime, endTime string, allDay bool) error
+	WriteBlockerOnDate(b *Blocker) error // Sets b.EventID to the new event's ID.
+	PatchBlocker(eventID string, b *Blocker) error
+	DeleteBlocker(eventID string) error
 }
@@ -0,0 +1,537 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RuleExprKey is the key of a todayIsFreezeDayIf group whose items are rule expressions
+// instead of fixed conditions. The expressions of one group are AND'd.
+//
+//	todayIsFreezeDayIf:
+//	- expr:
+//	  - isNthBusinessDayOfMonth(3) or isLastWeekdayOfQuarter(friday)
+//	  - not at(tomorrow, isNonBusinessDay)
+const RuleExprKey = "expr"
+
+// RuleExpr is a parsed, type-checked freeze rule expression. It evaluates to a bool for a day.
+//
+// Grammar:
+//
+//	expr    = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | primary
+//	primary = "(" expr ")" | name [ "(" arg { "," arg } ")" ]
+//	arg     = integer | weekday | anchor | expr
+//
+// Every function has a fixed signature, see ruleFuncs; argument types and ranges are
+// checked when parsing.
+type RuleExpr interface {
+	Eval(d *TGIFDay) bool
+	String() string
+}
+
+// ruleArgType is the type of a function argument.
+type ruleArgType int
+
+const (
+	argInt ruleArgType = iota
+	argWeekday
+	argDay
+	argBool
+)
+
+func (t ruleArgType) String() string {
+	switch t {
+	case argInt:
+		return "integer"
+	case argWeekday:
+		return "weekday"
+	case argDay:
+		return "anchor"
+	default:
+		return "expression"
+	}
+}
+
+// ruleArg is a checked function argument; only the field of its type is set.
+type ruleArg struct {
+	typ     ruleArgType
+	n       int
+	weekday time.Weekday
+	day     string
+	anchor  Anchor
+	expr    RuleExpr
+}
+
+func (a ruleArg) String() string {
+	switch a.typ {
+	case argInt:
+		return strconv.Itoa(a.n)
+	case argWeekday:
+		return strings.ToLower(a.weekday.String())
+	case argDay:
+		return a.day
+	default:
+		return a.expr.String()
+	}
+}
+
+// ruleFunc is the signature and implementation of a rule function.
+type ruleFunc struct {
+	params []ruleArgType
+	// check validates argument values beyond their type, e.g. ranges. Optional.
+	check func(args []ruleArg) error
+	eval  func(d *TGIFDay, args []ruleArg) bool
+}
+
+var ruleFuncs = map[string]ruleFunc{
+	"isBusinessDay": {eval: func(d *TGIFDay, _ []ruleArg) bool {
+		return d != nil && d.IsBusinessDay
+	}},
+	"isNonBusinessDay": {eval: func(d *TGIFDay, _ []ruleArg) bool {
+		return d.FnIsNonBusinessDay()
+	}},
+	"isHoliday": {eval: func(d *TGIFDay, _ []ruleArg) bool {
+		return d != nil && d.IsHoliday
+	}},
+	"isWeekend": {eval: func(d *TGIFDay, _ []ruleArg) bool {
+		return d != nil && d.IsWeekend
+	}},
+	"isTheFirstBusinessDayOfTheMonth": {eval: func(d *TGIFDay, _ []ruleArg) bool {
+		return d.FnIsFirstBusinessDayOfMonth()
+	}},
+	"isTheLastBusinessDayOfTheMonth": {eval: func(d *TGIFDay, _ []ruleArg) bool {
+		return d.FnIsLastBusinessDayOfMonth()
+	}},
+	"isNthBusinessDayOfMonth": {
+		params: []ruleArgType{argInt},
+		check: func(args []ruleArg) error {
+			if n := args[0].n; n == 0 || n < -23 || n > 23 {
+				return fmt.Errorf("n must be 1..23, or -1..-23 to count from the end of the month, got %d", n)
+			}
+			return nil
+		},
+		eval: func(d *TGIFDay, args []ruleArg) bool {
+			return d.isNthBusinessDayOfMonth(args[0].n)
+		},
+	},
+	"isWeekday": {
+		params: []ruleArgType{argWeekday},
+		eval: func(d *TGIFDay, args []ruleArg) bool {
+			return d != nil && d.Date.Weekday() == args[0].weekday
+		},
+	},
+	"isLastWeekdayOfMonth": {
+		params: []ruleArgType{argWeekday},
+		eval: func(d *TGIFDay, args []ruleArg) bool {
+			return d != nil && d.Date.Weekday() == args[0].weekday &&
+				d.Date.AddDate(0, 0, 7).Month() != d.Date.Month()
+		},
+	},
+	"isLastWeekdayOfQuarter": {
+		params: []ruleArgType{argWeekday},
+		eval: func(d *TGIFDay, args []ruleArg) bool {
+			return d != nil && d.Date.Weekday() == args[0].weekday &&
+				quarterOf(d.Date.AddDate(0, 0, 7)) != quarterOf(d.Date)
+		},
+	},
+	"isWithinBusinessDaysOfHoliday": {
+		params: []ruleArgType{argInt},
+		check: func(args []ruleArg) error {
+			if n := args[0].n; n < 0 || n > 10 {
+				return fmt.Errorf("n must be 0..10, got %d", n)
+			}
+			return nil
+		},
+		eval: func(d *TGIFDay, args []ruleArg) bool {
+			return d.isWithinBusinessDaysOfHoliday(args[0].n)
+		},
+	},
+	"at": {
+		params: []ruleArgType{argDay, argBool},
+		eval: func(d *TGIFDay, args []ruleArg) bool {
+			if d == nil {
+				return false
+			}
+			return args[1].expr.Eval(d.At(args[0].anchor))
+		},
+	},
+}
+
+var ruleWeekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+func quarterOf(t time.Time) int {
+	return t.Year()*4 + (int(t.Month())-1)/3
+}
+
+// lookup returns the day at offset from d in the mapping, or nil, without logging.
+func (d *TGIFDay) lookup(offsetDays int) *TGIFDay {
+	return (*d.parentMapping)[NewDateKey(d.Date.AddDate(0, 0, offsetDays))]
+}
+
+// isNthBusinessDayOfMonth reports whether d is the nth business day of its month, or the
+// |n|th from the end when n is negative. False when the month is not fully in the mapping.
+func (d *TGIFDay) isNthBusinessDayOfMonth(n int) bool {
+	if d == nil || !d.IsBusinessDay {
+		return false
+	}
+	step := -1
+	if n < 0 {
+		step, n = 1, -n
+	}
+	count := 1
+	for offset := step; d.Date.AddDate(0, 0, offset).Month() == d.Date.Month(); offset += step {
+		cur := d.lookup(offset)
+		if cur == nil {
+			return false // month not fully known
+		}
+		if cur.IsBusinessDay {
+			count++
+		}
+	}
+	return count == n
+}
+
+// isWithinBusinessDaysOfHoliday reports whether a holiday is reached from d, before or
+// after, without passing n business days. n=0 only matches holidays themselves.
+func (d *TGIFDay) isWithinBusinessDaysOfHoliday(n int) bool {
+	if d == nil {
+		return false
+	}
+	isHoliday := func(day *TGIFDay) bool { return day.IsHoliday && day.IsNonBusinessDay }
+	if isHoliday(d) {
+		return true
+	}
+	for _, step := range []int{1, -1} {
+		passed := 0
+		for cur := d.lookup(step); cur != nil && passed < n; cur = cur.lookup(step) {
+			if isHoliday(cur) {
+				return true
+			}
+			if cur.IsBusinessDay {
+				passed++
+			}
+		}
+	}
+	return false
+}
+
+type notExpr struct{ x RuleExpr }
+
+func (e notExpr) Eval(d *TGIFDay) bool { return !e.x.Eval(d) }
+func (e notExpr) String() string       { return "not " + e.x.String() }
+
+type binaryExpr struct {
+	op   string // "and" or "or"
+	l, r RuleExpr
+}
+
+func (e binaryExpr) Eval(d *TGIFDay) bool {
+	if e.op == "and" {
+		return e.l.Eval(d) && e.r.Eval(d)
+	}
+	return e.l.Eval(d) || e.r.Eval(d)
+}
+
+func (e binaryExpr) String() string {
+	return "(" + e.l.String() + " " + e.op + " " + e.r.String() + ")"
+}
+
+type callExpr struct {
+	name string
+	fn   ruleFunc
+	args []ruleArg
+}
+
+func (e callExpr) Eval(d *TGIFDay) bool { return e.fn.eval(d, e.args) }
+
+func (e callExpr) String() string {
+	if len(e.args) == 0 {
+		return e.name
+	}
+	parts := make([]string, len(e.args))
+	for i, a := range e.args {
+		parts[i] = a.String()
+	}
+	return e.name + "(" + strings.Join(parts, ", ") + ")"
+}
+
+// ParseRuleExpr parses and type-checks a freeze rule expression.
+func ParseRuleExpr(src string) (RuleExpr, error) {
+	tokens, err := lexRuleExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &ruleParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+	return expr, nil
+}
+
+type ruleTokenKind int
+
+const (
+	tokEOF ruleTokenKind = iota
+	tokIdent
+	tokInt
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type ruleToken struct {
+	kind ruleTokenKind
+	text string
+	pos  int
+}
+
+func lexRuleExpr(src string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, ruleToken{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, ruleToken{tokRParen, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, ruleToken{tokComma, ",", i})
+			i++
+		case r == '-' || r == '+' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			kind := tokInt
+			if i < len(runes) && unicode.IsLetter(runes[i]) { // an anchor offset such as +1bd
+				kind = tokIdent
+				for i < len(runes) && unicode.IsLetter(runes[i]) {
+					i++
+				}
+			}
+			text := string(runes[start:i])
+			if text == "-" || text == "+" {
+				return nil, fmt.Errorf("expected a number after %q at position %d", text, start+1)
+			}
+			tokens = append(tokens, ruleToken{kind, text, start})
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, ruleToken{tokIdent, string(runes[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+		}
+	}
+	return append(tokens, ruleToken{tokEOF, "end of expression", len(runes)}), nil
+}
+
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+}
+
+func (p *ruleParser) peek() ruleToken { return p.tokens[p.pos] }
+
+func (p *ruleParser) next() ruleToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *ruleParser) expect(kind ruleTokenKind, what string) error {
+	if tok := p.next(); tok.kind != kind {
+		return fmt.Errorf("expected %s at position %d, got %q", what, tok.pos+1, tok.text)
+	}
+	return nil
+}
+
+func (p *ruleParser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && tok.text == word
+}
+
+func (p *ruleParser) parseOr() (RuleExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "or", l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (RuleExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: "and", l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseUnary() (RuleExpr, error) {
+	if p.isKeyword("not") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *ruleParser) parsePrimary() (RuleExpr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return x, nil
+	case tokIdent:
+		return p.parseCall(tok)
+	default:
+		return nil, fmt.Errorf("expected a condition at position %d, got %q", tok.pos+1, tok.text)
+	}
+}
+
+func (p *ruleParser) parseCall(name ruleToken) (RuleExpr, error) {
+	fn, ok := ruleFuncs[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d. Supported functions: %s",
+			name.text, name.pos+1, strings.Join(ruleFuncSignatures(), ", "))
+	}
+	var args []ruleArg
+	if p.peek().kind == tokLParen {
+		p.next()
+		for {
+			if len(args) >= len(fn.params) {
+				return nil, fmt.Errorf("%s takes %d argument(s)", name.text, len(fn.params))
+			}
+			arg, err := p.parseArg(name.text, fn.params[len(args)])
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(tokRParen, `")"`); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) != len(fn.params) {
+		return nil, fmt.Errorf("%s takes %d argument(s), got %d", name.text, len(fn.params), len(args))
+	}
+	if fn.check != nil {
+		if err := fn.check(args); err != nil {
+			return nil, fmt.Errorf("%s: %w", name.text, err)
+		}
+	}
+	return callExpr{name: name.text, fn: fn, args: args}, nil
+}
+
+func (p *ruleParser) parseArg(funcName string, typ ruleArgType) (ruleArg, error) {
+	if typ == argBool {
+		x, err := p.parseOr()
+		if err != nil {
+			return ruleArg{}, err
+		}
+		return ruleArg{typ: argBool, expr: x}, nil
+	}
+
+	tok := p.next()
+	mismatch := fmt.Errorf("%s expects an argument of type %s at position %d, got %q", funcName, typ, tok.pos+1, tok.text)
+	switch typ {
+	case argInt:
+		if tok.kind != tokInt {
+			return ruleArg{}, mismatch
+		}
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return ruleArg{}, mismatch
+		}
+		return ruleArg{typ: argInt, n: n}, nil
+	case argWeekday:
+		w, ok := ruleWeekdays[tok.text]
+		if tok.kind != tokIdent || !ok {
+			return ruleArg{}, mismatch
+		}
+		return ruleArg{typ: argWeekday, weekday: w}, nil
+	default: // argDay
+		if tok.kind != tokIdent {
+			return ruleArg{}, mismatch
+		}
+		anchor, err := ParseAnchor(tok.text)
+		if err != nil {
+			return ruleArg{}, fmt.Errorf("%s: %w", funcName, err)
+		}
+		return ruleArg{typ: argDay, day: tok.text, anchor: anchor}, nil
+	}
+}
+
+// ruleFuncSignatures lists the functions available in rule expressions, for error messages.
+func ruleFuncSignatures() []string {
+	names := make([]string, 0, len(ruleFuncs))
+	for name, fn := range ruleFuncs {
+		if len(fn.params) == 0 {
+			names = append(names, name)
+			continue
+		}
+		params := make([]string, len(fn.params))
+		for i, t := range fn.params {
+			params[i] = t.String()
+		}
+		names = append(names, name+"("+strings.Join(params, ", ")+")")
+	}
+	sort.Strings(names)
+	return names
+}
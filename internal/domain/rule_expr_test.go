@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRuleExpr_Errors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", "expected a condition"},
+		{"isFullMoon", `unknown function "isFullMoon"`},
+		{"isNthBusinessDayOfMonth", "takes 1 argument(s), got 0"},
+		{"isNthBusinessDayOfMonth(friday)", "expects an argument of type integer"},
+		{"isNthBusinessDayOfMonth(0)", "n must be 1..23"},
+		{"isNthBusinessDayOfMonth(1, 2)", "takes 1 argument(s)"},
+		{"isLastWeekdayOfQuarter(3)", "expects an argument of type weekday"},
+		{"at(someday, isHoliday)", `unsupported anchor "someday"`},
+		{"at(3, isHoliday)", "expects an argument of type anchor"},
+		{"at(+2w, isHoliday)", `unsupported anchor "+2w"`},
+		{"isHoliday and", "expected a condition"},
+		{"(isHoliday", `expected ")"`},
+		{"isHoliday isWeekend", `unexpected "isWeekend"`},
+		{"isHoliday && isWeekend", "unexpected character '&'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := ParseRuleExpr(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseRuleExpr(%q) error = %v, want containing %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRuleExpr_Precedence(t *testing.T) {
+	expr, err := ParseRuleExpr("not isHoliday or isWeekend and at(tomorrow, isNonBusinessDay)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "(not isHoliday or (isWeekend and at(tomorrow, isNonBusinessDay)))"
+	if got := expr.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRuleExpr_Eval(t *testing.T) {
+	// 2026-09-01 .. 2026-10-31, with a holiday on Mon 2026-09-21.
+	m := newTestMapping(date("2026-09-01"), 61, "2026-09-21")
+
+	tests := []struct {
+		src  string
+		day  string
+		want bool
+	}{
+		// 2026-09-01 is a Tuesday, so the 3rd business day is Thu 09-03.
+		{"isNthBusinessDayOfMonth(3)", "2026-09-03", true},
+		{"isNthBusinessDayOfMonth(3)", "2026-09-04", false},
+		// Last business day of September is Wed 09-30, second to last Tue 09-29.
+		{"isNthBusinessDayOfMonth(-2)", "2026-09-29", true},
+		{"isNthBusinessDayOfMonth(-1)", "2026-09-30", true},
+		// 2026-10-31 is the last mapped day, so the end of October is known.
+		{"isNthBusinessDayOfMonth(-1)", "2026-10-30", true},
+		{"isNthBusinessDayOfMonth(1)", "2026-09-01", true},
+		{"isLastWeekdayOfQuarter(friday)", "2026-09-25", true},
+		{"isLastWeekdayOfQuarter(friday)", "2026-09-18", false},
+		{"isLastWeekdayOfMonth(wednesday)", "2026-09-30", true},
+		{"isWeekday(monday) and not isHoliday", "2026-09-21", false},
+		{"isWeekday(monday) and not isHoliday", "2026-09-28", true},
+		// Fri 09-18 reaches the Monday holiday across the weekend without passing a business day.
+		{"isWithinBusinessDaysOfHoliday(1)", "2026-09-18", true},
+		{"isWithinBusinessDaysOfHoliday(1)", "2026-09-17", false},
+		{"isWithinBusinessDaysOfHoliday(2)", "2026-09-17", true},
+		{"isWithinBusinessDaysOfHoliday(2)", "2026-09-23", true},
+		{"isWithinBusinessDaysOfHoliday(0)", "2026-09-21", true},
+		{"at(tomorrow, isNonBusinessDay)", "2026-09-17", false},
+		{"at(tomorrow, isNonBusinessDay)", "2026-09-18", true},
+		{"at(yesterday, isHoliday)", "2026-09-22", true},
+		// Fri 09-18: the next business day skips the weekend and the Monday holiday.
+		{"at(+1bd, isWeekday(tuesday))", "2026-09-18", true},
+		{"at(-3d, isHoliday)", "2026-09-24", true},
+		// Out of the mapping: unknown days never match.
+		{"at(tomorrow, isWeekend)", "2026-10-31", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src+"@"+tt.day, func(t *testing.T) {
+			expr, err := ParseRuleExpr(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := expr.Eval((*m)[DateKey(tt.day)]); got != tt.want {
+				t.Errorf("Eval(%s) = %v, want %v", tt.day, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreezeRules_Match_ExprGroup(t *testing.T) {
+	m := newTestMapping(date("2026-09-01"), 30)
+	rules, err := NewFreezeRules(TodayIsFreezeDayIf{
+		{RuleExprKey: {"isNthBusinessDayOfMonth(2)", "not isWeekend"}},
+		{ruleToday: {ruleIsNonBusiness}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for day, want := range map[DateKey]bool{
+		"2026-09-02": true,  // 2nd business day
+		"2026-09-05": true,  // Saturday, from the anchor group
+		"2026-09-03": false, // 3rd business day
+	} {
+		if _, got := rules.Match((*m)[day]); got != want {
+			t.Errorf("Match(%s) = %v, want %v", day, got, want)
+		}
+	}
+}
+
+func TestNewFreezeRules_InvalidGroups(t *testing.T) {
+	m := newTestMapping(date("2026-09-01"), 30)
+	rules, err := NewFreezeRules(TodayIsFreezeDayIf{
+		{RuleExprKey: {"isFullMoon"}},
+		{ruleToday: {"isPayday"}},
+		{RuleExprKey: {"isNthBusinessDayOfMonth(2)"}},
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), `unknown function "isFullMoon"`) || !strings.Contains(err.Error(), `unsupported condition "isPayday"`) {
+		t.Errorf("NewFreezeRules error = %v, want the bad expression and condition", err)
+	}
+
+	// Invalid groups never match; the valid one still does, and nothing panics.
+	for day, want := range map[DateKey]string{
+		"2026-09-02": "todayIsFreezeDayIf #3",
+		"2026-09-03": "",
+	} {
+		match, _ := rules.Match((*m)[day])
+		if match.Rule != want {
+			t.Errorf("Match(%s) = %q, want %q", day, match.Rule, want)
+		}
+	}
+}
@@ -1,36 +1,476 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
-// RunSync wipes all managed blocker events in [rangeStart, rangeEnd] and rewrites
-// them based on the freeze-day rules. It is the shared business logic for both
-// manual sync (HTTP handler) and scheduled auto-sync (background worker).
-// Returns a human-readable result message and whether it was an error.
+// BlockerUpdate pairs an existing blocker with the content it should be patched to.
+type BlockerUpdate struct {
+	Existing *Blocker
+	Desired  *Blocker
+}
+
+// SyncPlan is the set of calendar changes that brings the managed blockers in a date
+// range in line with the freeze-day rules. Every slice is ordered by date.
+type SyncPlan struct {
+	Create      []*Blocker
+	Update      []*BlockerUpdate
+	Delete      []*Blocker
+	Keep        []*Blocker
+	DaysChecked int
+	// Drift lists the blockers changed by hand since the last sync, handled by DriftPolicy.
+	Drift       []Drift
+	DriftPolicy DriftPolicy
+	respected   []*Blocker // Ledger entries of drifted blockers left as people left them.
+}
+
+// SyncResult counts the calendar changes made by ApplySyncPlan.
+type SyncResult struct {
+	Created     int
+	Updated     int
+	Deleted     int
+	Unchanged   int
+	DaysChecked int
+	// Failed lists the changes that did not go through, ordered like the plan.
+	Failed []SyncFailure
+	// HeldBack counts the deletes not attempted because they would have left a day whose
+	// new or updated blocker failed without any blocker.
+	HeldBack int
+
+	// Which of the plan's creates, updates and deletes went through, by index.
+	createdOK, updatedOK, deletedOK []bool
+	// Why changes failed and which deletes were held back, by the blocker in PlanChange.
+	errs map[*Blocker]error
+	held map[*Blocker]bool
+}
+
+func (r *SyncResult) String() string {
+	return fmt.Sprintf("Sync complete. Created %d, updated %d, deleted %d, unchanged %d blocker event(s) across %d days checked.",
+		r.Created, r.Updated, r.Deleted, r.Unchanged, r.DaysChecked)
+}
+
+// SyncFailure is a planned change the calendar rejected.
+type SyncFailure struct {
+	Action string // PlanActionAdd, PlanActionUpdate or PlanActionRemove.
+	Date   DateKey
+	Err    error
+}
+
+func (f SyncFailure) Error() string {
+	return fmt.Sprintf("%s %s: %v", f.Action, f.Date, f.Err)
+}
+
+// sortedDays returns the days of the mapping ordered by date.
+func (m *TGIFMapping) sortedDays() []*TGIFDay {
+	days := make([]*TGIFDay, 0, len(*m))
+	for _, day := range *m {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	return days
+}
+
+// PlanSync compares the blockers the rules call for with the existing ones and returns
+// the minimal set of changes. Each desired blocker is tagged with the rule that produced it.
+// Blockers, merged or not, are matched by the day they start on. Existing blockers starting
+// outside the mapping are left alone.
+// When a date has several existing blockers, the one that already matches (or the first)
+// is kept and the rest are deleted.
+func PlanSync(mapping *TGIFMapping, existing []*Blocker, rules *FreezeRules, templates *BlockerTemplates) *SyncPlan {
+	days := mapping.sortedDays()
+	existingByKey := make(map[DateKey][]*Blocker)
+	// A merged blocker that starts before the mapping may still cover its first days. Those
+	// days are left alone, so that moving the range forward does not add a second blocker.
+	var coveredUntil DateKey
+	for _, b := range existing {
+		if _, ok := (*mapping)[b.Key()]; !ok {
+			if last := NewDateKey(b.LastDate()); len(days) > 0 && b.Date.Before(days[0].Date) && last > coveredUntil {
+				coveredUntil = last
+			}
+			continue
+		}
+		existingByKey[b.Key()] = append(existingByKey[b.Key()], b)
+	}
+	desiredByKey := templates.desiredBlockers(days, rules, coveredUntil)
+
+	plan := &SyncPlan{DaysChecked: len(*mapping)}
+	for _, day := range days {
+		if day.Key <= coveredUntil {
+			continue
+		}
+		current := existingByKey[day.Key]
+		desired, ok := desiredByKey[day.Key]
+		if !ok {
+			plan.Delete = append(plan.Delete, current...)
+			continue
+		}
+
+		if len(current) == 0 {
+			plan.Create = append(plan.Create, desired)
+			continue
+		}
+
+		matchIdx := 0
+		for i, b := range current {
+			if b.SameContent(desired) {
+				matchIdx = i
+				break
+			}
+		}
+		match := current[matchIdx]
+		if match.SameContent(desired) {
+			match.Rule = desired.Rule
+			plan.Keep = append(plan.Keep, match)
+		} else {
+			// Attendees were already invited; only tell them again when the guest list, the
+			// summary or the time changes.
+			if slices.Equal(match.Attendees, desired.Attendees) && match.sameForAttendees(desired) {
+				desired.SendUpdates = SendUpdatesNone
+			}
+			plan.Update = append(plan.Update, &BlockerUpdate{Existing: match, Desired: desired})
+		}
+		for i, b := range current {
+			if i != matchIdx {
+				plan.Delete = append(plan.Delete, b)
+			}
+		}
+	}
+	return plan
+}
+
+// desiredBlockers returns the blockers the rules call for, keyed by the day each one starts
+// on. Days up to coveredUntil are skipped. With MergeConsecutiveDays, consecutive freeze days
+// that pick the same template share the blocker of the run's first day.
+func (t *BlockerTemplates) desiredBlockers(days []*TGIFDay, rules *FreezeRules, coveredUntil DateKey) map[DateKey]*Blocker {
+	desired := make(map[DateKey]*Blocker)
+	var run *Blocker // blocker of the current run of freeze days, nil after a non-freeze day
+	var runTemplate string
+	for _, day := range days {
+		freezeMatch, isFreezeDay := rules.Match(day)
+		if day.Key <= coveredUntil || !isFreezeDay {
+			run = nil
+			continue
+		}
+		if t.MergeConsecutiveDays && run != nil && freezeMatch.Template == runTemplate {
+			run.EndDate = day.Date
+			continue
+		}
+		run, runTemplate = t.BlockerFor(day, freezeMatch), freezeMatch.Template
+		desired[day.Key] = run
+	}
+	return desired
+}
+
+// Plan change actions, as shown in sync previews.
+const (
+	PlanActionAdd    = "add"
+	PlanActionUpdate = "update"
+	PlanActionRemove = "remove"
+	PlanActionKeep   = "keep"
+)
+
+// PlanChange is what a sync would do to one blocker on one date.
+type PlanChange struct {
+	Date    DateKey
+	Action  string
+	Blocker *Blocker // The desired blocker for add/update/keep, the existing one for remove.
+	EventID string   // Existing event ID; empty for add.
+}
+
+// Changes flattens the plan into per-date changes ordered by date.
+func (p *SyncPlan) Changes() []PlanChange {
+	changes := make([]PlanChange, 0, len(p.Create)+len(p.Update)+len(p.Delete)+len(p.Keep))
+	for _, b := range p.Create {
+		changes = append(changes, PlanChange{Date: b.Key(), Action: PlanActionAdd, Blocker: b})
+	}
+	for _, u := range p.Update {
+		changes = append(changes, PlanChange{Date: u.Desired.Key(), Action: PlanActionUpdate, Blocker: u.Desired, EventID: u.Existing.EventID})
+	}
+	for _, b := range p.Delete {
+		changes = append(changes, PlanChange{Date: b.Key(), Action: PlanActionRemove, Blocker: b, EventID: b.EventID})
+	}
+	for _, b := range p.Keep {
+		changes = append(changes, PlanChange{Date: b.Key(), Action: PlanActionKeep, Blocker: b, EventID: b.EventID})
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Date < changes[j].Date })
+	return changes
+}
+
+// PreviewSync computes the sync plan for [rangeStart, rangeEnd) from the business calendar,
+// without writing anything. written is what the ledger says the last sync wrote; blockers
+// changed by hand since are handled by the drift policy, see PlanSyncWithDrift.
+func PreviewSync(
+	repo TGIFCalendarRepository,
+	cal *BusinessCalendar,
+	rangeStart, rangeEnd time.Time,
+	rules *FreezeRules,
+	templates *BlockerTemplates,
+	written []*Blocker,
+	policy DriftPolicy,
+) (*SyncPlan, error) {
+	tgifMapping, err := BuildTGIFMapping(rangeStart, rangeEnd, cal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get freeze days: %w", err)
+	}
+	existing, err := repo.ListBlockersInRange(rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing blockers: %w", err)
+	}
+	return PlanSyncWithDrift(tgifMapping, existing, written, rules, templates, policy), nil
+}
+
+// WriteConcurrency is how many calendar writes a sync or wipe has in flight at once. It is
+// kept low because every config's writes count against the same user's Calendar quota.
+const WriteConcurrency = 5
+
+// ForEachConcurrently calls fn for 0..n-1 with at most limit calls running at once and
+// returns each call's error by index.
+func ForEachConcurrently(n, limit int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		})
+	}
+	wg.Wait()
+	return errs
+}
+
+// ApplySyncPlan writes the plan to the calendar, WriteConcurrency changes at a time. A failed
+// change doesn't stop the others: the result counts the changes made and lists the failed
+// ones, and the returned error joins the failures.
+//
+// Creates and updates go first. Deletes run only once they are done, and a delete covering a
+// day whose create or update failed is held back, so a failing sync never leaves a freeze day
+// without a blocker. The next sync plans again from the calendar and picks up what is left.
+func ApplySyncPlan(repo TGIFCalendarRepository, plan *SyncPlan) (*SyncResult, error) {
+	result := &SyncResult{
+		Unchanged:   len(plan.Keep),
+		DaysChecked: plan.DaysChecked,
+		createdOK:   make([]bool, len(plan.Create)),
+		updatedOK:   make([]bool, len(plan.Update)),
+		deletedOK:   make([]bool, len(plan.Delete)),
+		errs:        make(map[*Blocker]error),
+		held:        make(map[*Blocker]bool),
+	}
+	var failures []error
+	unprotected := make(map[DateKey]bool)
+	fail := func(action string, b *Blocker, err error) {
+		f := SyncFailure{Action: action, Date: b.Key(), Err: err}
+		result.Failed = append(result.Failed, f)
+		result.errs[b] = err
+		failures = append(failures, f)
+		if action == PlanActionRemove {
+			return
+		}
+		for d := b.Date; !d.After(b.LastDate()); d = d.AddDate(0, 0, 1) {
+			unprotected[NewDateKey(d)] = true
+		}
+	}
+
+	nc := len(plan.Create)
+	errs := ForEachConcurrently(nc+len(plan.Update), WriteConcurrency, func(i int) error {
+		if i < nc {
+			return repo.WriteBlockerOnDate(plan.Create[i])
+		}
+		u := plan.Update[i-nc]
+		return repo.PatchBlocker(u.Existing.EventID, u.Desired)
+	})
+	for i, err := range errs {
+		switch {
+		case err != nil && i < nc:
+			fail(PlanActionAdd, plan.Create[i], err)
+		case err != nil:
+			fail(PlanActionUpdate, plan.Update[i-nc].Desired, err)
+		case i < nc:
+			result.createdOK[i] = true
+			result.Created++
+		default:
+			result.updatedOK[i-nc] = true
+			result.Updated++
+		}
+	}
+
+	var deletes []int
+	for i, b := range plan.Delete {
+		if coversAny(b, unprotected) {
+			result.HeldBack++
+			result.held[b] = true
+			continue
+		}
+		deletes = append(deletes, i)
+	}
+	errs = ForEachConcurrently(len(deletes), WriteConcurrency, func(i int) error {
+		return repo.DeleteBlocker(plan.Delete[deletes[i]].EventID)
+	})
+	for i, err := range errs {
+		if err != nil {
+			fail(PlanActionRemove, plan.Delete[deletes[i]], err)
+			continue
+		}
+		result.deletedOK[deletes[i]] = true
+		result.Deleted++
+	}
+	return result, errors.Join(failures...)
+}
+
+// What happened to a planned change when the plan was applied.
+const (
+	ChangeApplied   = "applied"
+	ChangeFailed    = "failed"
+	ChangeHeldBack  = "held back"
+	ChangeUnchanged = "unchanged"
+)
+
+// ChangeOutcome is a planned change and what happened to it.
+type ChangeOutcome struct {
+	PlanChange
+	Result string // ChangeApplied, ChangeFailed, ChangeHeldBack or ChangeUnchanged.
+	Err    error  // Why the change failed.
+}
+
+// ChangeOutcomes pairs each of the plan's changes with its result, ordered by date.
+func (p *SyncPlan) ChangeOutcomes(result *SyncResult) []ChangeOutcome {
+	changes := p.Changes()
+	out := make([]ChangeOutcome, len(changes))
+	for i, c := range changes {
+		out[i] = ChangeOutcome{PlanChange: c, Result: ChangeApplied}
+		switch {
+		case c.Action == PlanActionKeep:
+			out[i].Result = ChangeUnchanged
+		case result.errs[c.Blocker] != nil:
+			out[i].Result, out[i].Err = ChangeFailed, result.errs[c.Blocker]
+		case result.held[c.Blocker]:
+			out[i].Result = ChangeHeldBack
+		}
+	}
+	return out
+}
+
+// SyncStatus is the outcome of a sync run.
+type SyncStatus string
+
+const (
+	// SyncStatusSuccess means every planned change was made.
+	SyncStatusSuccess SyncStatus = "success"
+	// SyncStatusPartial means some changes were made and others failed or were held back.
+	SyncStatusPartial SyncStatus = "partial"
+	// SyncStatusFailed means the sync made no change: it could not plan, was stopped, or
+	// every change failed.
+	SyncStatusFailed SyncStatus = "failed"
+)
+
+// SyncOutcome reports how a sync run ended.
+type SyncOutcome struct {
+	Status  SyncStatus
+	Message string      // Human-readable summary, including the failures.
+	Result  *SyncResult // Nil when the sync failed before writing.
+	// The synced date range and each planned change with its result; zero and empty when
+	// the sync failed before planning.
+	RangeStart, RangeEnd time.Time
+	Changes              []ChangeOutcome
+}
+
+// FailedSync returns the outcome of a sync that failed before writing anything.
+func FailedSync(message string) *SyncOutcome {
+	return &SyncOutcome{Status: SyncStatusFailed, Message: message}
+}
+
+// IsErr reports whether the outcome should be shown as an error.
+func (o *SyncOutcome) IsErr() bool {
+	return o.Status != SyncStatusSuccess
+}
+
+// RunSync brings the managed blocker events in [rangeStart, rangeEnd) in line with the
+// freeze-day rules, creating, patching or deleting only the events that differ.
+// It is the shared business logic for both manual sync (HTTP handler) and scheduled
+// auto-sync (background worker). Blockers changed by hand since the sync recorded in the
+// ledger are handled by the drift policy, and the ledger is updated with what was written.
+// A sync that fails halfway reports a partial outcome listing what failed.
 func RunSync(
 	repo TGIFCalendarRepository,
+	cal *BusinessCalendar,
 	rangeStart, rangeEnd time.Time,
-	rules TodayIsFreezeDayIf,
-	summary, description, startTime, endTime string,
-	allDay bool,
-) (string, bool) {
-	tgifMapping, err := repo.GetFreezeDaysInRange(rangeStart, rangeEnd)
+	rules *FreezeRules,
+	templates *BlockerTemplates,
+	ledger BlockerLedger,
+	policy DriftPolicy,
+) *SyncOutcome {
+	written, err := ledger.WrittenBlockers()
 	if err != nil {
-		return "failed to get freeze days: " + err.Error(), true
+		return FailedSync("failed to read the blocker ledger: " + err.Error())
 	}
-	if err := repo.WipeAllBlockersInRange(rangeStart, rangeEnd); err != nil {
-		return "failed to wipe existing blockers: " + err.Error(), true
+	plan, err := PreviewSync(repo, cal, rangeStart, rangeEnd, rules, templates, written, policy)
+	if err != nil {
+		return FailedSync(err.Error())
 	}
-	count := 0
-	for _, day := range *tgifMapping {
-		if day.IsTodayFreezeDay(rules) {
-			if err := repo.WriteBlockerOnDate(day.Date, summary, description, startTime, endTime, allDay); err != nil {
-				return fmt.Sprintf("failed to write blocker on %s: %s", day.Date.Format("2006-01-02"), err.Error()), true
-			}
-			count++
+	if plan.Stopped() {
+		outcome := FailedSync(fmt.Sprintf("Sync stopped: %d manual change(s) to blocker events. Review them under Manual Edits on the config page and choose to overwrite or keep them.",
+			len(plan.Drift)))
+		outcome.RangeStart, outcome.RangeEnd = rangeStart, rangeEnd
+		return outcome
+	}
+	result, applyErr := ApplySyncPlan(repo, plan)
+	outcome := &SyncOutcome{
+		Status:     SyncStatusSuccess,
+		Message:    result.String() + driftNote(plan),
+		Result:     result,
+		RangeStart: rangeStart,
+		RangeEnd:   rangeEnd,
+		Changes:    plan.ChangeOutcomes(result),
+	}
+	if applyErr != nil {
+		outcome.Status = SyncStatusPartial
+		if result.Created+result.Updated+result.Deleted == 0 {
+			outcome.Status = SyncStatusFailed
+		}
+		outcome.Message = incompleteMessage(result)
+	}
+	if err := ledger.RecordWrittenBlockers(plan.WrittenAfter(result, written)); err != nil {
+		if outcome.Status == SyncStatusSuccess {
+			outcome.Status = SyncStatusPartial
 		}
+		outcome.Message += fmt.Sprintf(" But the blocker ledger could not be updated: %v", err)
+	}
+	return outcome
+}
+
+// incompleteMessage describes a sync some of whose changes failed.
+func incompleteMessage(result *SyncResult) string {
+	var held string
+	if result.HeldBack > 0 {
+		held = fmt.Sprintf(", and %d removal(s) were held back so no freeze day is left without a blocker", result.HeldBack)
+	}
+	return fmt.Sprintf("Sync incomplete: %d change(s) failed%s. Created %d, updated %d, deleted %d blocker event(s); the next sync picks up the rest. Failures: %s",
+		len(result.Failed), held, result.Created, result.Updated, result.Deleted, failureList(result.Failed))
+}
+
+// failureList joins the failures for a result message.
+func failureList(failed []SyncFailure) string {
+	msgs := make([]string, len(failed))
+	for i, f := range failed {
+		msgs[i] = f.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// driftNote tells how the sync handled blockers changed by hand; empty when there were none.
+func driftNote(plan *SyncPlan) string {
+	if len(plan.Drift) == 0 {
+		return ""
+	}
+	if plan.DriftPolicy == DriftPolicyRespect {
+		return fmt.Sprintf(" %d manual change(s) to blocker events left as they are.", len(plan.Drift))
 	}
-	return fmt.Sprintf("Sync complete. Created %d blocker event(s) across %d days checked.", count, len(*tgifMapping)), false
+	return fmt.Sprintf(" %d manual change(s) to blocker events overwritten.", len(plan.Drift))
 }
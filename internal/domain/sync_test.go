@@ -0,0 +1,551 @@
+package domain
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	ruleToday          = "today"
+	ruleIsNonBusiness  = "isNonBusinessDay"
+	testBlockerSummary = "Freeze"
+)
+
+// newTestMapping builds a filled mapping for [start, start+days) with the given holidays.
+func newTestMapping(start time.Time, days int, holidays ...string) *TGIFMapping {
+	isHoliday := make(map[string]bool)
+	for _, h := range holidays {
+		isHoliday[h] = true
+	}
+	m := make(TGIFMapping)
+	for d := start; d.Before(start.AddDate(0, 0, days)); d = d.AddDate(0, 0, 1) {
+		key := NewDateKey(d)
+		m[key] = NewTGIFDay(d, &m, isHoliday[string(key)])
+	}
+	m.FillMonthInfo()
+	return &m
+}
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+var testTemplate = BlockerTemplate{
+	Summary:     testBlockerSummary,
+	Description: "No prod ops.",
+	StartTime:   "08:00",
+	EndTime:     "20:00",
+}
+
+var testTemplates = &BlockerTemplates{Default: testTemplate}
+
+// todayNonBusiness freezes every weekend and holiday.
+var todayNonBusiness = &FreezeRules{DayRules: TodayIsFreezeDayIf{{ruleToday: {ruleIsNonBusiness}}}}
+
+func TestPlanSync_CreatesMissingBlockers(t *testing.T) {
+	// 2026-05-04 (Mon) .. 2026-05-10 (Sun): Sat 9 and Sun 10 are freeze days.
+	m := newTestMapping(date("2026-05-04"), 7)
+
+	plan := PlanSync(m, nil, todayNonBusiness, testTemplates)
+
+	if len(plan.Create) != 2 {
+		t.Fatalf("len(Create) = %d, want 2", len(plan.Create))
+	}
+	if plan.Create[0].Key() != "2026-05-09" || plan.Create[1].Key() != "2026-05-10" {
+		t.Errorf("Create dates = %s, %s; want 2026-05-09, 2026-05-10", plan.Create[0].Key(), plan.Create[1].Key())
+	}
+	if plan.DaysChecked != 7 {
+		t.Errorf("DaysChecked = %d, want 7", plan.DaysChecked)
+	}
+}
+
+func TestPlanSync_DiffsAgainstExisting(t *testing.T) {
+	m := newTestMapping(date("2026-05-04"), 7)
+
+	unchanged := testTemplate.BlockerOn(date("2026-05-09"))
+	unchanged.EventID = "keep"
+	renamed := testTemplate.BlockerOn(date("2026-05-10"))
+	renamed.EventID = "patch"
+	renamed.Summary = "old summary"
+	stale := testTemplate.BlockerOn(date("2026-05-06"))
+	stale.EventID = "stale"
+	duplicate := testTemplate.BlockerOn(date("2026-05-09"))
+	duplicate.EventID = "dup"
+	outOfRange := testTemplate.BlockerOn(date("2026-06-01"))
+	outOfRange.EventID = "outside"
+
+	plan := PlanSync(m, []*Blocker{duplicate, unchanged, renamed, stale, outOfRange}, todayNonBusiness, testTemplates)
+
+	if len(plan.Create) != 0 {
+		t.Errorf("len(Create) = %d, want 0", len(plan.Create))
+	}
+	if len(plan.Keep) != 1 || plan.Keep[0].EventID != "dup" {
+		t.Errorf("Keep = %v, want the first matching event on 2026-05-09", plan.Keep)
+	}
+	if len(plan.Update) != 1 || plan.Update[0].Existing.EventID != "patch" || plan.Update[0].Desired.Summary != testBlockerSummary {
+		t.Errorf("Update = %v, want event patch updated to template summary", plan.Update)
+	}
+	deleted := map[string]bool{}
+	for _, b := range plan.Delete {
+		deleted[b.EventID] = true
+	}
+	if len(plan.Delete) != 2 || !deleted["stale"] || !deleted["keep"] {
+		t.Errorf("Delete = %v, want stale and the duplicate on 2026-05-09", deleted)
+	}
+}
+
+func TestPlanSync_NotifiesOnlyWhenAttendeesChange(t *testing.T) {
+	// Sat 2026-05-09 and Sun 2026-05-10 are freeze days.
+	m := newTestMapping(date("2026-05-04"), 7)
+	tmpl := testTemplate
+	tmpl.Attendees = []string{"oncall@example.com"}
+	templates := &BlockerTemplates{Default: tmpl, SendUpdates: SendUpdatesAll}
+
+	redescribed := tmpl.BlockerOn(date("2026-05-09"))
+	redescribed.EventID = "redescribed"
+	redescribed.Description = "old description"
+	reinvited := tmpl.BlockerOn(date("2026-05-10"))
+	reinvited.EventID = "reinvited"
+	reinvited.Attendees = nil
+
+	plan := PlanSync(m, []*Blocker{redescribed, reinvited}, todayNonBusiness, templates)
+
+	if len(plan.Update) != 2 {
+		t.Fatalf("len(Update) = %d, want 2", len(plan.Update))
+	}
+	for _, u := range plan.Update {
+		want := SendUpdatesAll
+		if u.Existing.EventID == "redescribed" {
+			want = SendUpdatesNone // same guests, summary and time: patched silently
+		}
+		if u.Desired.SendUpdates != want {
+			t.Errorf("update of %s SendUpdates = %q, want %q", u.Existing.EventID, u.Desired.SendUpdates, want)
+		}
+	}
+}
+
+func TestPlanSync_NotifiesWhenTimeOrSummaryChanges(t *testing.T) {
+	// Sat 2026-05-09 and Sun 2026-05-10 are freeze days.
+	m := newTestMapping(date("2026-05-04"), 7)
+	tmpl := testTemplate
+	tmpl.Attendees = []string{"oncall@example.com"}
+	templates := &BlockerTemplates{Default: tmpl, SendUpdates: SendUpdatesAll}
+
+	moved := tmpl.BlockerOn(date("2026-05-09"))
+	moved.EventID = "moved"
+	moved.StartTime, moved.EndTime = "10:00", "18:00"
+	retitled := tmpl.BlockerOn(date("2026-05-10"))
+	retitled.EventID = "retitled"
+	retitled.Summary = "old summary"
+
+	plan := PlanSync(m, []*Blocker{moved, retitled}, todayNonBusiness, templates)
+
+	if len(plan.Update) != 2 {
+		t.Fatalf("len(Update) = %d, want 2", len(plan.Update))
+	}
+	for _, u := range plan.Update {
+		if u.Desired.SendUpdates != SendUpdatesAll {
+			t.Errorf("update of %s with the same guests SendUpdates = %q, want %q", u.Existing.EventID, u.Desired.SendUpdates, SendUpdatesAll)
+		}
+	}
+}
+
+func TestBlockerSameContent_AllDayIgnoresTimes(t *testing.T) {
+	tmpl := testTemplate
+	tmpl.AllDay = true
+	a := tmpl.BlockerOn(date("2026-05-09"))
+	b := tmpl.BlockerOn(date("2026-05-09"))
+	b.StartTime = "09:00"
+	if !a.SameContent(b) {
+		t.Error("all-day blockers on the same date with same text should match")
+	}
+	b.Date = date("2026-05-10")
+	if a.SameContent(b) {
+		t.Error("blockers on different dates should not match")
+	}
+}
+
+func TestPlanSync_MergeConsecutiveDays(t *testing.T) {
+	// Mon 2026-04-27 .. Sun 2026-05-10; Wed 04-29 and Mon 05-04..Wed 05-06 are holidays.
+	m := newTestMapping(date("2026-04-27"), 14, "2026-04-29", "2026-05-04", "2026-05-05", "2026-05-06")
+	rules := &FreezeRules{DayRules: TodayIsFreezeDayIf{
+		{ruleToday: {ruleIsNonBusiness}},
+		{ruleToday: {"isTheFirstBusinessDayOfTheMonth"}, RuleTemplateKey: {"monthStart"}},
+	}}
+	templates := &BlockerTemplates{
+		Default:              testTemplate,
+		Named:                map[string]BlockerTemplate{"monthStart": {Summary: "Month start", AllDay: true}},
+		MergeConsecutiveDays: true,
+	}
+
+	plan := PlanSync(m, nil, rules, templates)
+
+	// Fri 05-01 is the first business day of May and picks another template, so it gets
+	// its own blocker although the Sat 05-02 .. Wed 05-06 run follows it.
+	want := []struct{ first, last DateKey }{
+		{"2026-04-29", "2026-04-29"},
+		{"2026-05-01", "2026-05-01"},
+		{"2026-05-02", "2026-05-06"},
+		{"2026-05-09", "2026-05-10"},
+	}
+	if len(plan.Create) != len(want) {
+		t.Fatalf("len(Create) = %d, want %d: %v", len(plan.Create), len(want), plan.Create)
+	}
+	for i, w := range want {
+		b := plan.Create[i]
+		if b.Key() != w.first || NewDateKey(b.LastDate()) != w.last {
+			t.Errorf("Create[%d] = %s..%s, want %s..%s", i, b.Key(), NewDateKey(b.LastDate()), w.first, w.last)
+		}
+	}
+
+	// Switching from one blocker per day: the first day's event is stretched over the run
+	// and the others are deleted.
+	var perDay []*Blocker
+	for _, key := range []string{"2026-05-02", "2026-05-03", "2026-05-04"} {
+		b := testTemplate.BlockerOn(date(key))
+		b.EventID = key
+		perDay = append(perDay, b)
+	}
+	plan = PlanSync(m, perDay, rules, templates)
+	if len(plan.Update) != 1 || plan.Update[0].Existing.EventID != "2026-05-02" || NewDateKey(plan.Update[0].Desired.LastDate()) != "2026-05-06" {
+		t.Errorf("Update = %v, want the 05-02 event stretched to 05-06", plan.Update)
+	}
+	if len(plan.Delete) != 2 {
+		t.Errorf("len(Delete) = %d, want the 05-03 and 05-04 events", len(plan.Delete))
+	}
+
+	// A synced run is kept as it is.
+	merged := *plan.Update[0].Desired
+	merged.EventID = "merged"
+	plan = PlanSync(m, []*Blocker{&merged}, rules, templates)
+	if len(plan.Keep) != 1 || plan.Keep[0].EventID != "merged" {
+		t.Errorf("Keep = %v, want the merged event", plan.Keep)
+	}
+}
+
+func TestPlanSync_MergedBlockerBeforeRange(t *testing.T) {
+	// The range starts on Sun 2026-05-03, inside a run that was written from Sat 05-02.
+	m := newTestMapping(date("2026-05-03"), 7, "2026-05-04")
+	templates := &BlockerTemplates{Default: testTemplate, MergeConsecutiveDays: true}
+	earlier := testTemplate.BlockerOn(date("2026-05-02"))
+	earlier.EndDate = date("2026-05-04")
+	earlier.EventID = "earlier"
+
+	plan := PlanSync(m, []*Blocker{earlier}, todayNonBusiness, templates)
+
+	if len(plan.Delete) != 0 || len(plan.Update) != 0 {
+		t.Errorf("plan = %+v, want the earlier event left alone", plan)
+	}
+	if len(plan.Create) != 1 || plan.Create[0].Key() != "2026-05-09" {
+		t.Errorf("Create = %v, want only the next weekend", plan.Create)
+	}
+}
+
+// fakeRepo records calendar writes and fails on the configured event date or event ID.
+// Writes may come concurrently.
+type fakeRepo struct {
+	failOn   DateKey
+	failOnID string
+	mu       sync.Mutex
+	written  []DateKey
+	patched  []string
+	deleted  []string
+}
+
+func (f *fakeRepo) ListBlockersInRange(_, _ time.Time) ([]*Blocker, error) { return nil, nil }
+func (f *fakeRepo) WipeAllBlockersInRange(_, _ time.Time) error            { return nil }
+
+func (f *fakeRepo) WriteBlockerOnDate(b *Blocker) error {
+	if b.Key() == f.failOn {
+		return errors.New("boom")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.written = append(f.written, b.Key())
+	b.EventID = "new-" + string(b.Key())
+	return nil
+}
+
+func (f *fakeRepo) PatchBlocker(eventID string, _ *Blocker) error {
+	if eventID == f.failOnID {
+		return errors.New("boom")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patched = append(f.patched, eventID)
+	return nil
+}
+
+func (f *fakeRepo) DeleteBlocker(eventID string) error {
+	if eventID == f.failOnID {
+		return errors.New("boom")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, eventID)
+	return nil
+}
+
+func TestApplySyncPlan_Counts(t *testing.T) {
+	plan := &SyncPlan{
+		Create:      []*Blocker{testTemplate.BlockerOn(date("2026-05-09"))},
+		Update:      []*BlockerUpdate{{Existing: &Blocker{EventID: "a"}, Desired: testTemplate.BlockerOn(date("2026-05-10"))}},
+		Delete:      []*Blocker{{EventID: "b", Date: date("2026-05-06")}},
+		Keep:        []*Blocker{{EventID: "c"}},
+		DaysChecked: 7,
+	}
+	repo := &fakeRepo{}
+
+	result, err := ApplySyncPlan(repo, plan)
+	if err != nil {
+		t.Fatalf("ApplySyncPlan() error = %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 || result.Deleted != 1 || result.Unchanged != 1 || result.DaysChecked != 7 || len(result.Failed) != 0 {
+		t.Errorf("result = %+v, want 1 created, updated, deleted and unchanged across 7 days", *result)
+	}
+	if len(repo.patched) != 1 || repo.patched[0] != "a" {
+		t.Errorf("patched = %v, want [a]", repo.patched)
+	}
+}
+
+func TestApplySyncPlan_ContinuesAfterError(t *testing.T) {
+	plan := &SyncPlan{
+		Create: []*Blocker{testTemplate.BlockerOn(date("2026-05-09")), testTemplate.BlockerOn(date("2026-05-10"))},
+		Delete: []*Blocker{{EventID: "b", Date: date("2026-05-06")}},
+	}
+	repo := &fakeRepo{failOn: "2026-05-10"}
+
+	result, err := ApplySyncPlan(repo, plan)
+	if err == nil {
+		t.Fatal("ApplySyncPlan() expected error, got nil")
+	}
+	if result.Created != 1 || result.Deleted != 1 {
+		t.Errorf("Created = %d, Deleted = %d; want the other changes applied", result.Created, result.Deleted)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Action != PlanActionAdd || result.Failed[0].Date != "2026-05-10" {
+		t.Errorf("Failed = %v, want the add on 2026-05-10", result.Failed)
+	}
+}
+
+func TestApplySyncPlan_HoldsBackDeletesOfFailedDays(t *testing.T) {
+	// The merged blocker replacing Sat and Sun fails, so the old Saturday blocker must stay.
+	// The stale Wednesday blocker covers no failed day and is deleted.
+	merged := testTemplate.BlockerOn(date("2026-05-09"))
+	merged.EndDate = date("2026-05-10")
+	plan := &SyncPlan{
+		Create: []*Blocker{merged},
+		Delete: []*Blocker{{EventID: "sat", Date: date("2026-05-09")}, {EventID: "wed", Date: date("2026-05-06")}},
+	}
+	repo := &fakeRepo{failOn: "2026-05-09"}
+
+	result, err := ApplySyncPlan(repo, plan)
+	if err == nil {
+		t.Fatal("ApplySyncPlan() expected error, got nil")
+	}
+	if result.HeldBack != 1 || len(repo.deleted) != 1 || repo.deleted[0] != "wed" {
+		t.Errorf("HeldBack = %d, deleted = %v; want the Saturday delete held back and [wed] deleted", result.HeldBack, repo.deleted)
+	}
+
+	outcomes := plan.ChangeOutcomes(result)
+	want := []string{ChangeApplied, ChangeFailed, ChangeHeldBack} // wed, the merged add, sat
+	if len(outcomes) != len(want) {
+		t.Fatalf("len(ChangeOutcomes) = %d, want %d", len(outcomes), len(want))
+	}
+	for i, w := range want {
+		if outcomes[i].Result != w {
+			t.Errorf("outcome %d (%s %s) = %s, want %s", i, outcomes[i].Action, outcomes[i].Date, outcomes[i].Result, w)
+		}
+	}
+	if outcomes[1].Err == nil {
+		t.Error("failed add has no error")
+	}
+}
+
+func TestRunSync_Outcome(t *testing.T) {
+	// Sat 2026-05-09 and Sun 2026-05-10 are freeze days in a week without holidays.
+	cal := &BusinessCalendar{}
+	tests := []struct {
+		name   string
+		failOn DateKey
+		want   SyncStatus
+	}{
+		{"success", "", SyncStatusSuccess},
+		{"partial", "2026-05-10", SyncStatusPartial},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := &memLedger{}
+			outcome := RunSync(&fakeRepo{failOn: tt.failOn}, cal, date("2026-05-04"), date("2026-05-11"),
+				todayNonBusiness, testTemplates, ledger, DriftPolicyOverwrite)
+			if outcome.Status != tt.want {
+				t.Fatalf("Status = %s (%s), want %s", outcome.Status, outcome.Message, tt.want)
+			}
+			if outcome.Result == nil || outcome.Result.Created+len(outcome.Result.Failed) != 2 {
+				t.Errorf("Result = %+v, want two creates attempted", outcome.Result)
+			}
+			if want := outcome.Result.Created; len(ledger.blockers) != want {
+				t.Errorf("ledger has %d blocker(s), want %d", len(ledger.blockers), want)
+			}
+		})
+	}
+}
+
+// memLedger keeps the blocker ledger in memory.
+type memLedger struct{ blockers []*Blocker }
+
+func (l *memLedger) WrittenBlockers() ([]*Blocker, error) { return l.blockers, nil }
+
+func (l *memLedger) RecordWrittenBlockers(blockers []*Blocker) error {
+	l.blockers = blockers
+	return nil
+}
+
+func TestForEachConcurrently_BoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	errs := ForEachConcurrently(20, 3, func(i int) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if i == 7 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+	for i, err := range errs {
+		if (err != nil) != (i == 7) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+}
+
+func TestBuildTGIFMapping_MergesSources(t *testing.T) {
+	national := &HolidayList{Label: "national", Holidays: []Holiday{
+		{Date: date("2026-05-05"), Name: "Children's Day"},
+		{Date: date("2026-06-01"), Name: "Out of range"},
+	}}
+	company := &HolidayList{Label: "company", Holidays: []Holiday{
+		{Date: date("2026-05-05"), Name: "Company holiday"},
+		{Date: date("2026-05-06"), Name: "Bridge day"},
+	}}
+
+	m, err := BuildTGIFMapping(date("2026-05-04"), date("2026-05-11"), &BusinessCalendar{Sources: []HolidaySource{national, company}})
+	if err != nil {
+		t.Fatalf("BuildTGIFMapping() error = %v", err)
+	}
+	if len(*m) != 7 {
+		t.Fatalf("len(mapping) = %d, want 7", len(*m))
+	}
+	if got := (*m)["2026-05-05"].HolidayNames; len(got) != 2 {
+		t.Errorf("HolidayNames on 2026-05-05 = %v, want names from both sources", got)
+	}
+	if !(*m)["2026-05-06"].IsHoliday || (*m)["2026-05-04"].IsHoliday {
+		t.Error("2026-05-06 should be a holiday and 2026-05-04 should not")
+	}
+}
+
+func TestBuildTGIFMapping_OverridesBeforeMonthInfo(t *testing.T) {
+	// 2026-05-01 (Fri) is a national holiday the company works on; 2026-05-29 (Fri) is a
+	// company day off, so the last business day of May moves to Thu 28.
+	cal := &BusinessCalendar{
+		Sources: []HolidaySource{&HolidayList{Label: "national", Holidays: []Holiday{
+			{Date: date("2026-05-01"), Name: "May Day"},
+		}}},
+		Overrides: []DayOverride{
+			{Date: date("2026-05-01"), BusinessDay: true, Reason: "Working holiday"},
+			{Date: date("2026-05-29"), BusinessDay: false, Reason: "Company day off"},
+			{Date: date("2026-07-01"), BusinessDay: false},
+		},
+	}
+
+	m, err := BuildTGIFMapping(date("2026-04-20"), date("2026-06-10"), cal)
+	if err != nil {
+		t.Fatalf("BuildTGIFMapping() error = %v", err)
+	}
+	mayFirst := (*m)["2026-05-01"]
+	if !mayFirst.IsBusinessDay || !mayFirst.IsHoliday || mayFirst.Override == nil {
+		t.Errorf("2026-05-01 = %+v, want an overridden business day that is still a holiday", mayFirst)
+	}
+	if !mayFirst.FnIsFirstBusinessDayOfMonth() {
+		t.Error("2026-05-01 should be the first business day of May")
+	}
+	if (*m)["2026-05-29"].IsBusinessDay {
+		t.Error("2026-05-29 should be a non-business day")
+	}
+	if !(*m)["2026-05-28"].FnIsLastBusinessDayOfMonth() {
+		t.Error("2026-05-28 should be the last business day of May")
+	}
+}
+
+func TestHolidayIntersection_TracksNamesPerCountry(t *testing.T) {
+	jpn := &HolidayList{Label: "jpn", Holidays: []Holiday{
+		{Date: date("2026-01-01"), Name: "元日", Country: "jpn"},
+		{Date: date("2026-01-12"), Name: "成人の日", Country: "jpn"},
+	}}
+	vnm := &HolidayList{Label: "vnm", Holidays: []Holiday{
+		{Date: date("2026-01-01"), Name: "Tết Dương lịch", Country: "vnm"},
+	}}
+
+	unionMapping, err := BuildTGIFMapping(date("2026-01-01"), date("2026-01-15"), &BusinessCalendar{Sources: []HolidaySource{jpn, vnm}})
+	if err != nil {
+		t.Fatalf("BuildTGIFMapping(any) error = %v", err)
+	}
+	if !(*unionMapping)["2026-01-12"].IsHoliday {
+		t.Error("any: 2026-01-12 is a holiday in jpn and should count")
+	}
+	newYear := (*unionMapping)["2026-01-01"].HolidayNamesByCountry
+	if len(newYear["jpn"]) != 1 || len(newYear["vnm"]) != 1 {
+		t.Errorf("HolidayNamesByCountry = %v, want one name per country", newYear)
+	}
+
+	sharedMapping, err := BuildTGIFMapping(date("2026-01-01"), date("2026-01-15"), &BusinessCalendar{
+		Sources: []HolidaySource{&HolidayIntersection{Label: "shared", Sources: []HolidaySource{jpn, vnm}}},
+	})
+	if err != nil {
+		t.Fatalf("BuildTGIFMapping(all) error = %v", err)
+	}
+	if (*sharedMapping)["2026-01-12"].IsHoliday {
+		t.Error("all: 2026-01-12 is only a holiday in jpn and should not count")
+	}
+	if day := (*sharedMapping)["2026-01-01"]; !day.IsHoliday || len(day.HolidayNamesByCountry) != 2 {
+		t.Errorf("all: 2026-01-01 = %+v, want a holiday named in both countries", day)
+	}
+}
+
+func TestOwnsLegacyBlocker(t *testing.T) {
+	mapping := newTestMapping(date("2026-10-01"), 31)
+	other := &BlockerTemplates{Default: BlockerTemplate{Summary: "Team B freeze"}}
+
+	for _, tt := range []struct {
+		name string
+		b    *Blocker
+		want bool
+	}{
+		{"freeze day with this config's summary", &Blocker{Date: date("2026-10-03"), Summary: testBlockerSummary}, true},
+		{"freeze day with another config's summary", &Blocker{Date: date("2026-10-03"), Summary: "Team B freeze"}, false},
+		{"not a freeze day", &Blocker{Date: date("2026-10-05"), Summary: testBlockerSummary}, false},
+		{"outside the mapping", &Blocker{Date: date("2026-11-07"), Summary: testBlockerSummary}, false},
+	} {
+		if got := OwnsLegacyBlocker(tt.b, mapping, todayNonBusiness, testTemplates); got != tt.want {
+			t.Errorf("%s: OwnsLegacyBlocker = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !OwnsLegacyBlocker(&Blocker{Date: date("2026-10-03"), Summary: "Team B freeze"}, mapping, todayNonBusiness, other) {
+		t.Error("OwnsLegacyBlocker rejected the blocker for the config that renders its summary")
+	}
+}
@@ -0,0 +1,167 @@
+package holidays
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
+	appconfig "github.com/nvat/tgifreezeday/internal/config"
+	"github.com/nvat/tgifreezeday/internal/domain"
+	"github.com/nvat/tgifreezeday/internal/logging"
+)
+
+// Cache keeps the holidays read from Google's public holiday calendars per country and
+// calendar year, so frequent lookups such as the freeze query don't call Google on every
+// request. Public holiday calendars are the same for every user, so entries are shared
+// between configs. Concurrent misses for the same year share one fetch. A failed fetch is
+// remembered for failureTTL, during which the stale entry, if any, is served and no new
+// fetch is made.
+type Cache struct {
+	ttl        time.Duration
+	failureTTL time.Duration
+	now        func() time.Time
+
+	group    singleflight.Group
+	mu       sync.Mutex
+	entries  map[cacheKey]cacheEntry
+	failures map[cacheKey]cacheFailure
+}
+
+// cacheFailureTTL is how long a failed fetch is remembered before Google is asked again.
+const cacheFailureTTL = time.Minute
+
+type cacheKey struct {
+	source string
+	year   int
+}
+
+func (k cacheKey) String() string {
+	return fmt.Sprintf("%s/%d", k.source, k.year)
+}
+
+type cacheEntry struct {
+	holidays  []domain.Holiday
+	fetchedAt time.Time
+}
+
+type cacheFailure struct {
+	err      error
+	failedAt time.Time
+}
+
+// NewCache returns a cache that reads a calendar year again once its entry is older than ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		failureTTL: cacheFailureTTL,
+		now:        time.Now,
+		entries:    make(map[cacheKey]cacheEntry),
+		failures:   make(map[cacheKey]cacheFailure),
+	}
+}
+
+// Source returns a holiday source that reads whole calendar years through the cache. key
+// identifies the underlying source in the cache; open is called to get it on a miss.
+func (c *Cache) Source(key, name string, open func() (domain.HolidaySource, error)) domain.HolidaySource {
+	return &cachedSource{cache: c, key: key, name: name, open: open}
+}
+
+// year returns the holidays of one calendar year, fetching them on a miss or when stale.
+// When the fetch fails, a stale entry is returned instead of the error.
+func (c *Cache) year(s *cachedSource, year int) ([]domain.Holiday, error) {
+	key := cacheKey{source: s.key, year: year}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	failure, failed := c.failures[key]
+	c.mu.Unlock()
+	now := c.now()
+	if ok && now.Sub(entry.fetchedAt) < c.ttl {
+		return entry.holidays, nil
+	}
+	if failed && now.Sub(failure.failedAt) < c.failureTTL {
+		if ok {
+			return entry.holidays, nil
+		}
+		return nil, failure.err
+	}
+
+	v, err, _ := c.group.Do(key.String(), func() (any, error) {
+		holidays, err := fetchYear(s, year)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if err != nil {
+			c.failures[key] = cacheFailure{err: err, failedAt: c.now()}
+			return nil, err
+		}
+		c.entries[key] = cacheEntry{holidays: holidays, fetchedAt: c.now()}
+		delete(c.failures, key)
+		return holidays, nil
+	})
+	if err != nil {
+		if ok {
+			logging.GetLogger().WithError(err).WithField("holidays", key.String()).Warn("serving stale holidays after a failed refresh")
+			return entry.holidays, nil
+		}
+		return nil, err
+	}
+	return v.([]domain.Holiday), nil
+}
+
+func fetchYear(s *cachedSource, year int) ([]domain.Holiday, error) {
+	src, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return src.HolidaysInRange(yearStart, yearStart.AddDate(1, 0, 0))
+}
+
+type cachedSource struct {
+	cache *Cache
+	key   string
+	name  string
+	open  func() (domain.HolidaySource, error)
+}
+
+func (s *cachedSource) Name() string {
+	return s.name
+}
+
+func (s *cachedSource) HolidaysInRange(rangeStart, rangeEnd time.Time) ([]domain.Holiday, error) {
+	var out []domain.Holiday
+	for year := rangeStart.Year(); year <= rangeEnd.Add(-time.Nanosecond).Year(); year++ {
+		holidays, err := s.cache.year(s, year)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range holidays {
+			if !h.Date.Before(rangeStart) && h.Date.Before(rangeEnd) {
+				out = append(out, h)
+			}
+		}
+	}
+	return out, nil
+}
+
+// CachedCalendarFromConfig is CalendarFromConfig with the Google public holidays read
+// through cache. openRepo is called only when a holiday calendar has to be fetched, and
+// at most once.
+func CachedCalendarFromConfig(cfg *appconfig.Config, cache *Cache, openRepo func() (*googlecalendar.Repository, error)) (*domain.BusinessCalendar, error) {
+	var (
+		once    sync.Once
+		repo    *googlecalendar.Repository
+		repoErr error
+	)
+	return calendarFromConfig(cfg, func(cc string) (domain.HolidaySource, error) {
+		return cache.Source("google:"+cc, googlecalendar.HolidayCalendarName(cc), func() (domain.HolidaySource, error) {
+			once.Do(func() { repo, repoErr = openRepo() })
+			if repoErr != nil {
+				return nil, repoErr
+			}
+			return countrySource(repo, cc)
+		}), nil
+	})
+}
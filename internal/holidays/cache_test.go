@@ -0,0 +1,122 @@
+package holidays
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestCache_Source(t *testing.T) {
+	list := &domain.HolidayList{Label: "list", Holidays: []domain.Holiday{
+		{Date: day("2026-12-31"), Name: "New Year's Eve"},
+		{Date: day("2027-01-01"), Name: "New Year's Day"},
+		{Date: day("2027-01-11"), Name: "Coming of Age Day"},
+	}}
+	opens := 0
+	cache := NewCache(time.Hour)
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	src := cache.Source("list", "cached list", func() (domain.HolidaySource, error) {
+		opens++
+		return list, nil
+	})
+
+	got, err := src.HolidaysInRange(day("2026-12-31"), day("2027-01-05"))
+	if err != nil {
+		t.Fatalf("HolidaysInRange error: %v", err)
+	}
+	if len(got) != 2 || opens != 2 {
+		t.Fatalf("range over the year end = %d holidays with %d fetches, want 2 holidays and one fetch per year", len(got), opens)
+	}
+
+	if got, _ := src.HolidaysInRange(day("2027-01-10"), day("2027-01-12")); len(got) != 1 || got[0].Name != "Coming of Age Day" || opens != 2 {
+		t.Errorf("cached range = %+v after %d fetches, want Coming of Age Day without a fetch", got, opens)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := src.HolidaysInRange(day("2027-01-10"), day("2027-01-12")); err != nil || opens != 3 {
+		t.Errorf("stale year fetched %d times in total, want a new fetch after the TTL", opens)
+	}
+}
+
+func TestCache_SharesConcurrentFetches(t *testing.T) {
+	var opens atomic.Int32
+	release := make(chan struct{})
+	cache := NewCache(time.Hour)
+	src := cache.Source("list", "cached list", func() (domain.HolidaySource, error) {
+		opens.Add(1)
+		<-release
+		return &domain.HolidayList{Label: "list"}, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := src.HolidaysInRange(day("2027-01-10"), day("2027-01-12")); err != nil {
+				t.Errorf("HolidaysInRange error: %v", err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // let the lookups queue up behind the first fetch
+	close(release)
+	wg.Wait()
+	if n := opens.Load(); n != 1 {
+		t.Errorf("10 concurrent misses fetched %d times, want 1", n)
+	}
+}
+
+func TestCache_Failures(t *testing.T) {
+	list := &domain.HolidayList{Label: "list", Holidays: []domain.Holiday{{Date: day("2027-01-11"), Name: "Coming of Age Day"}}}
+	opens := 0
+	var openErr error
+	cache := NewCache(time.Hour)
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	src := cache.Source("list", "cached list", func() (domain.HolidaySource, error) {
+		opens++
+		if openErr != nil {
+			return nil, openErr
+		}
+		return list, nil
+	})
+	lookup := func() ([]domain.Holiday, error) {
+		return src.HolidaysInRange(day("2027-01-10"), day("2027-01-12"))
+	}
+
+	openErr = errors.New("token expired")
+	if _, err := lookup(); err == nil {
+		t.Fatal("lookup without a cached entry succeeded despite the failing fetch")
+	}
+	if _, err := lookup(); err == nil || opens != 1 {
+		t.Errorf("lookup right after a failure = %v after %d fetches, want the cached error without a fetch", err, opens)
+	}
+
+	now = now.Add(cache.failureTTL)
+	openErr = nil
+	if got, err := lookup(); err != nil || len(got) != 1 || opens != 2 {
+		t.Fatalf("lookup after the failure expired = %v, %v after %d fetches", got, err, opens)
+	}
+
+	// Once the entry is stale, a failed refresh serves it rather than the error.
+	now = now.Add(time.Hour)
+	openErr = errors.New("Google is down")
+	for range 2 {
+		if got, err := lookup(); err != nil || len(got) != 1 {
+			t.Errorf("lookup with a failing refresh = %v, %v; want the stale entry", got, err)
+		}
+	}
+	if opens != 3 {
+		t.Errorf("fetched %d times, want one failed refresh then the remembered failure", opens)
+	}
+}
@@ -0,0 +1,74 @@
+package holidays
+
+import (
+	"fmt"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
+	"github.com/nvat/tgifreezeday/internal/adapter/ics"
+	appconfig "github.com/nvat/tgifreezeday/internal/config"
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+// CalendarFromConfig builds the business calendar configured under readFrom: the holiday
+// sources plus the per-date overrides.
+// The Google public holiday calendar is read through repo's authorized calendar service;
+// the iCalendar file and the inline holiday list work offline.
+func CalendarFromConfig(cfg *appconfig.Config, repo *googlecalendar.Repository) (*domain.BusinessCalendar, error) {
+	return calendarFromConfig(cfg, func(cc string) (domain.HolidaySource, error) {
+		return countrySource(repo, cc)
+	})
+}
+
+func countrySource(repo *googlecalendar.Repository, countryCode string) (domain.HolidaySource, error) {
+	holidayCal, err := repo.HolidayCalendar(countryCode)
+	if err != nil {
+		return nil, err
+	}
+	return holidayCal, nil
+}
+
+// calendarFromConfig builds the business calendar with the country holiday sources made by
+// country.
+func calendarFromConfig(cfg *appconfig.Config, country func(countryCode string) (domain.HolidaySource, error)) (*domain.BusinessCalendar, error) {
+	var sources []domain.HolidaySource
+
+	g := cfg.ReadFrom.GoogleCalendar
+	var countrySources []domain.HolidaySource
+	for _, cc := range g.Countries() {
+		holidayCal, err := country(cc)
+		if err != nil {
+			return nil, err
+		}
+		countrySources = append(countrySources, holidayCal)
+	}
+	if g.MatchAllCountries() && len(countrySources) > 1 {
+		sources = append(sources, &domain.HolidayIntersection{
+			Label:   fmt.Sprintf("Public holidays shared by %v", g.Countries()),
+			Sources: countrySources,
+			Policy:  cfg.HolidayPolicy(),
+		})
+	} else {
+		sources = append(sources, countrySources...)
+	}
+
+	if f := cfg.ReadFrom.ICSFile; f != nil {
+		fileSrc, err := ics.Open(ics.FilesDir(), f.Path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, fileSrc)
+	}
+
+	if list := cfg.HolidayListSource(); list != nil {
+		sources = append(sources, list)
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no holiday source configured")
+	}
+	return &domain.BusinessCalendar{
+		Sources:   sources,
+		Policy:    cfg.HolidayPolicy(),
+		Overrides: cfg.DayOverrides(),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
+	appconfig "github.com/nvat/tgifreezeday/internal/config"
+	"github.com/nvat/tgifreezeday/internal/domain"
+	"github.com/nvat/tgifreezeday/internal/logging"
+)
+
+// LegacyBlockers are the untagged blockers from before extended properties in a config's
+// date range.
+type LegacyBlockers struct {
+	// Owned are the blockers the config would have written, see domain.OwnsLegacyBlocker,
+	// or all of them when no other config writes to the calendar.
+	Owned []*domain.Blocker
+	// Unmatched are the others: blockers of another config on a shared calendar, or the
+	// config's own from before its rules, templates or holidays changed. Nothing adopts
+	// them on its own; people adopt or delete them on the config's page.
+	Unmatched []*domain.Blocker
+}
+
+// FindLegacyBlockers sorts the legacy blockers in the range into the ones the config owns
+// and the rest. Empty once the config's adoption is done. It writes nothing, so read-only
+// views can show them as not yet adopted.
+func FindLegacyBlockers(repo *googlecalendar.Repository, configs *db.ConfigStore, cfg *db.Config, appCfg *appconfig.Config, cal *domain.BusinessCalendar, rangeStart, rangeEnd time.Time) (*LegacyBlockers, error) {
+	if cfg.LegacyBlockersAdoptedAt != nil {
+		return &LegacyBlockers{}, nil
+	}
+	legacy, err := repo.ListLegacyBlockersInRange(rangeStart, rangeEnd)
+	if err != nil || len(legacy) == 0 {
+		return &LegacyBlockers{}, err
+	}
+	shared, err := sharesCalendar(configs, cfg, appCfg.WriteTo.GoogleCalendar.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !shared {
+		return &LegacyBlockers{Owned: legacy}, nil
+	}
+	mapping, err := domain.BuildTGIFMapping(rangeStart, rangeEnd, cal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get freeze days: %w", err)
+	}
+	rules, templates := appCfg.FreezeRules(), appCfg.BlockerTemplates(cfg.Name)
+	found := &LegacyBlockers{}
+	for _, b := range legacy {
+		if domain.OwnsLegacyBlocker(b, mapping, rules, templates) {
+			found.Owned = append(found.Owned, b)
+		} else {
+			found.Unmatched = append(found.Unmatched, b)
+		}
+	}
+	return found, nil
+}
+
+// sharesCalendar reports whether another config writes blockers to calendarID. Configs
+// whose YAML does not parse are skipped.
+func sharesCalendar(configs *db.ConfigStore, cfg *db.Config, calendarID string) (bool, error) {
+	all, err := configs.ListAllWithAuthor(nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to list configs: %w", err)
+	}
+	for _, other := range all {
+		if other.ID == cfg.ID {
+			continue
+		}
+		parsed, err := appconfig.LoadWithDefaultFromByteArray([]byte(other.ConfigYAML))
+		if err == nil && parsed.WriteTo.GoogleCalendar.ID == calendarID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AdoptLegacyBlockers runs the one-time migration that tags the config's blockers from
+// before extended properties, so that sync and wipe still find them. Only sync, wipe and
+// the legacy blocker actions run it. The migration is recorded as done only once no
+// unmatched legacy blockers are left in the range; until then their number is recorded
+// and the config's page lists them. Returns the unmatched blockers.
+func AdoptLegacyBlockers(repo *googlecalendar.Repository, configs *db.ConfigStore, cfg *db.Config, appCfg *appconfig.Config, cal *domain.BusinessCalendar, rangeStart, rangeEnd time.Time) ([]*domain.Blocker, error) {
+	if cfg.LegacyBlockersAdoptedAt != nil {
+		return nil, nil
+	}
+	found, err := FindLegacyBlockers(repo, configs, cfg, appCfg, cal, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find legacy blockers: %w", err)
+	}
+	adopted, err := repo.AdoptLegacyBlockers(found.Owned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to adopt legacy blockers: %w", err)
+	}
+	logger := logging.GetLogger().WithField("config_id", cfg.ID).WithField("adopted", adopted)
+	if len(found.Unmatched) > 0 {
+		if err := configs.RecordLegacyBlockersUnmatched(cfg.ID, len(found.Unmatched)); err != nil {
+			return nil, fmt.Errorf("failed to record unmatched legacy blockers: %w", err)
+		}
+		cfg.LegacyBlockersUnmatched = len(found.Unmatched)
+		logger.WithField("unmatched", len(found.Unmatched)).Warn("legacy blockers left for review")
+		return found.Unmatched, nil
+	}
+	now := time.Now().UTC()
+	if err := configs.RecordLegacyBlockersAdopted(cfg.ID, now); err != nil {
+		return nil, fmt.Errorf("failed to record legacy blocker adoption: %w", err)
+	}
+	cfg.LegacyBlockersAdoptedAt, cfg.LegacyBlockersUnmatched = &now, 0
+	logger.Info("adopted legacy blockers")
+	return nil, nil
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+)
+
+func TestSharesCalendar(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+	user, err := db.NewUserStore(database).Upsert("google-1", "user1@example.com", "User One")
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	configs := db.NewConfigStore(database)
+	create := func(name, calendarID string) *db.Config {
+		cfg, err := configs.Create(user.ID, name, "v1", "writeTo:\n  googleCalendar:\n    id: \""+calendarID+"\"\n", "none", nil)
+		if err != nil {
+			t.Fatalf("create config: %v", err)
+		}
+		return cfg
+	}
+	ops := create("Ops", "team@group.calendar.google.com")
+	payments := create("Payments", "payments@group.calendar.google.com")
+
+	if shared, err := sharesCalendar(configs, ops, "team@group.calendar.google.com"); err != nil || shared {
+		t.Errorf("sharesCalendar(alone) = %v, %v; want false", shared, err)
+	}
+	create("Ops month-end", "team@group.calendar.google.com")
+	if shared, err := sharesCalendar(configs, ops, "team@group.calendar.google.com"); err != nil || !shared {
+		t.Errorf("sharesCalendar(with a second config) = %v, %v; want true", shared, err)
+	}
+	if shared, err := sharesCalendar(configs, payments, "payments@group.calendar.google.com"); err != nil || shared {
+		t.Errorf("sharesCalendar(payments) = %v, %v; want false", shared, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
 	appconfig "github.com/nvat/tgifreezeday/internal/config"
 	"github.com/nvat/tgifreezeday/internal/domain"
+	"github.com/nvat/tgifreezeday/internal/holidays"
 	"github.com/nvat/tgifreezeday/internal/logging"
 	"golang.org/x/oauth2"
 )
@@ -44,6 +45,8 @@ func NextSyncAt(schedule string, from time.Time) time.Time {
 
 type Scheduler struct {
 	configs       *db.ConfigStore
+	ledgers       *db.LedgerStore
+	runs          *db.SyncRunStore
 	tokens        *db.TokenStore
 	oauthCfg      *oauth2.Config
 	tickerMinutes int
@@ -51,9 +54,11 @@ type Scheduler struct {
 
 // New creates a Scheduler. tickerMinutes controls how often the scheduler polls
 // for due configs; set via SCHED_TICKER_FREQUENCY_MIN (default 15, must be > 0).
-func New(configs *db.ConfigStore, tokens *db.TokenStore, oauthCfg *oauth2.Config, tickerMinutes int) *Scheduler {
+func New(configs *db.ConfigStore, ledgers *db.LedgerStore, runs *db.SyncRunStore, tokens *db.TokenStore, oauthCfg *oauth2.Config, tickerMinutes int) *Scheduler {
 	return &Scheduler{
 		configs:       configs,
+		ledgers:       ledgers,
+		runs:          runs,
 		tokens:        tokens,
 		oauthCfg:      oauthCfg,
 		tickerMinutes: tickerMinutes,
@@ -96,61 +101,66 @@ func (s *Scheduler) tick(ctx context.Context, now time.Time) {
 func (s *Scheduler) syncConfig(ctx context.Context, cfg *db.Config) {
 	log := logging.GetLogger().WithField("config_id", cfg.ID)
 
-	msg, isErr := s.runSync(ctx, cfg)
+	startedAt := time.Now().UTC()
+	outcome := s.runSync(ctx, cfg)
 	// Capture time AFTER runSync so next_sync_at is computed from the actual
 	// completion time, not the start time (avoids re-firing immediately when sync
 	// takes long enough to straddle a schedule boundary).
 	syncedAt := time.Now().UTC()
 
-	prefix := "✅ "
-	if isErr {
-		prefix = "❌ "
-	}
-	resultMsg := prefix + msg
+	resultMsg := statusPrefix[outcome.Status] + outcome.Message
 
 	next := NextSyncAt(cfg.SyncSchedule, syncedAt)
-	if err := s.configs.RecordAutoSync(cfg.ID, syncedAt, resultMsg, next); err != nil {
+	if err := s.configs.RecordAutoSync(cfg.ID, syncedAt, string(outcome.Status), resultMsg, next); err != nil {
 		log.WithError(err).Error("scheduler: failed to record auto-sync result")
 	}
-	log.WithField("result", resultMsg).Info("scheduler: auto-sync completed")
+	if err := s.runs.Record(db.NewSyncRun(cfg, db.SyncTriggerAuto, nil, startedAt, syncedAt, outcome)); err != nil {
+		log.WithError(err).Error("scheduler: failed to record sync run")
+	}
+	log.WithField("status", outcome.Status).WithField("result", resultMsg).Info("scheduler: auto-sync completed")
+}
+
+// statusPrefix marks the recorded auto-sync result with its outcome.
+var statusPrefix = map[domain.SyncStatus]string{
+	domain.SyncStatusSuccess: "✅ ",
+	domain.SyncStatusPartial: "⚠️ ",
+	domain.SyncStatusFailed:  "❌ ",
 }
 
-func (s *Scheduler) runSync(ctx context.Context, cfg *db.Config) (string, bool) {
+func (s *Scheduler) runSync(ctx context.Context, cfg *db.Config) *domain.SyncOutcome {
 	appCfg, err := parseAppConfig(cfg.ConfigYAML)
 	if err != nil {
-		return err.Error(), true
+		return domain.FailedSync(err.Error())
 	}
 	token, err := s.tokens.Get(cfg.UserID)
 	if err != nil {
-		return "failed to get owner token: " + err.Error(), true
+		return domain.FailedSync("failed to get owner token: " + err.Error())
 	}
 	if token == nil {
-		return "no OAuth token for config owner — owner must log in", true
+		return domain.FailedSync("no OAuth token for config owner — owner must log in")
 	}
 	repo, err := googlecalendar.NewRepositoryWithToken(ctx, s.oauthCfg, token, cfg.UserID, s.tokens,
-		appCfg.ReadFrom.GoogleCalendar.CountryCode,
-		appCfg.WriteTo.GoogleCalendar.ID,
+		appCfg.WriteTo.GoogleCalendar.ID, cfg.ID,
 	)
 	if err != nil {
-		return err.Error(), true
+		return domain.FailedSync(err.Error())
 	}
-	d := appCfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default
-	allDay := d.AllDay != nil && *d.AllDay
-	startTime, endTime := "", ""
-	if !allDay {
-		startTime = *d.StartTime
-		endTime = *d.EndTime
+	businessCal, err := holidays.CalendarFromConfig(appCfg, repo)
+	if err != nil {
+		return domain.FailedSync(err.Error())
 	}
 	rangeStart, rangeEnd := syncDateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
+	if _, err := AdoptLegacyBlockers(repo, s.configs, cfg, appCfg, businessCal, rangeStart, rangeEnd); err != nil {
+		return domain.FailedSync(err.Error())
+	}
 	return domain.RunSync(
 		repo,
+		businessCal,
 		rangeStart, rangeEnd,
-		domain.TodayIsFreezeDayIf(appCfg.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf),
-		*d.Summary,
-		*d.Description,
-		startTime,
-		endTime,
-		allDay,
+		appCfg.FreezeRules(),
+		appCfg.BlockerTemplates(cfg.Name),
+		s.ledgers.ForConfig(cfg.ID),
+		appCfg.DriftPolicy(),
 	)
 }
 
@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	appconfig "github.com/nvat/tgifreezeday/internal/config"
+	"github.com/nvat/tgifreezeday/internal/domain"
+	"github.com/nvat/tgifreezeday/internal/perm"
+	"github.com/nvat/tgifreezeday/internal/scheduler"
+)
+
+// API error codes, returned in the "code" field of every error body.
+const (
+	apiErrBadRequest    = "bad_request"
+	apiErrUnauthorized  = "unauthorized"
+	apiErrForbidden     = "forbidden"
+	apiErrNotFound      = "not_found"
+	apiErrConflict      = "conflict"
+	apiErrInvalidConfig = "invalid_config"
+	apiErrUpstream      = "upstream_error"
+	apiErrInternal      = "internal_error"
+)
+
+// apiErrorBody is the body of every API error response.
+type apiErrorBody struct {
+	Error apiErrorDetail `json:"error"`
+}
+
+type apiErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v) //nolint:errcheck,gosec
+}
+
+func apiError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, apiErrorBody{Error: apiErrorDetail{Code: code, Message: msg}})
+}
+
+// apiConfig is a config as the API returns it. YAML is left out of lists.
+type apiConfig struct {
+	ID                 int64      `json:"id"`
+	OwnerID            int64      `json:"ownerId"`
+	Name               string     `json:"name"`
+	SchemaVersion      string     `json:"schemaVersion"`
+	Revision           int        `json:"revision"`
+	Status             string     `json:"status"`
+	StatusMessage      string     `json:"statusMessage,omitempty"`
+	SyncSchedule       string     `json:"syncSchedule"`
+	NextSyncAt         *time.Time `json:"nextSyncAt,omitempty"`
+	LastAutoSyncedAt   *time.Time `json:"lastAutoSyncedAt,omitempty"`
+	LastAutoSyncStatus *string    `json:"lastAutoSyncStatus,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+	YAML               string     `json:"yaml,omitempty"`
+}
+
+func newAPIConfig(cfg *db.Config, withYAML bool) apiConfig {
+	out := apiConfig{
+		ID:                 cfg.ID,
+		OwnerID:            cfg.UserID,
+		Name:               cfg.Name,
+		SchemaVersion:      cfg.SchemaVersion,
+		Revision:           cfg.Revision,
+		Status:             string(cfg.Status),
+		StatusMessage:      cfg.StatusMessage,
+		SyncSchedule:       cfg.SyncSchedule,
+		NextSyncAt:         cfg.NextSyncAt,
+		LastAutoSyncedAt:   cfg.LastAutoSyncedAt,
+		LastAutoSyncStatus: cfg.LastAutoSyncStatus,
+		CreatedAt:          cfg.CreatedAt,
+		UpdatedAt:          cfg.UpdatedAt,
+	}
+	if withYAML {
+		out.YAML = cfg.ConfigYAML
+	}
+	return out
+}
+
+// apiConfigRequest is the body of a create or update. On update, omitted fields keep
+// their current value.
+type apiConfigRequest struct {
+	Name         *string `json:"name"`
+	YAML         *string `json:"yaml"`
+	SyncSchedule *string `json:"syncSchedule"`
+}
+
+// apiSyncRun is a recorded sync run, returned by the sync endpoint.
+type apiSyncRun struct {
+	ID             int64              `json:"id"`
+	ConfigID       int64              `json:"configId"`
+	ConfigRevision int                `json:"configRevision"`
+	Trigger        string             `json:"trigger"`
+	StartedAt      time.Time          `json:"startedAt"`
+	FinishedAt     time.Time          `json:"finishedAt"`
+	RangeStart     string             `json:"rangeStart,omitempty"`
+	RangeEnd       string             `json:"rangeEnd,omitempty"`
+	Status         domain.SyncStatus  `json:"status"`
+	Message        string             `json:"message"`
+	Created        int                `json:"created"`
+	Updated        int                `json:"updated"`
+	Deleted        int                `json:"deleted"`
+	Unchanged      int                `json:"unchanged"`
+	Failed         int                `json:"failed"`
+	HeldBack       int                `json:"heldBack"`
+	Changes        []apiSyncRunChange `json:"changes"`
+}
+
+type apiSyncRunChange struct {
+	Date    string `json:"date"`
+	EndDate string `json:"endDate,omitempty"`
+	Action  string `json:"action"`
+	Summary string `json:"summary"`
+	EventID string `json:"eventId,omitempty"`
+	Result  string `json:"result"`
+	Error   string `json:"error,omitempty"`
+}
+
+func newAPISyncRun(run *db.SyncRun) apiSyncRun {
+	out := apiSyncRun{
+		ID:             run.ID,
+		ConfigID:       run.ConfigID,
+		ConfigRevision: run.ConfigRevision,
+		Trigger:        run.Trigger,
+		StartedAt:      run.StartedAt,
+		FinishedAt:     run.FinishedAt,
+		RangeStart:     run.RangeStart,
+		RangeEnd:       run.RangeEnd,
+		Status:         run.Status,
+		Message:        run.Message,
+		Created:        run.Created,
+		Updated:        run.Updated,
+		Deleted:        run.Deleted,
+		Unchanged:      run.Unchanged,
+		Failed:         run.Failed,
+		HeldBack:       run.HeldBack,
+		Changes:        []apiSyncRunChange{},
+	}
+	for _, c := range run.Changes {
+		out.Changes = append(out.Changes, apiSyncRunChange(*c))
+	}
+	return out
+}
+
+// apiConfigFromPath loads the config named by the path as the current user, writing an
+// error response and returning nil if it is missing.
+func (h *ConfigHandler) apiConfigFromPath(w http.ResponseWriter, r *http.Request) *db.Config {
+	id, ok := idFromPath(r)
+	if !ok {
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, "invalid config id")
+		return nil
+	}
+	cfg, err := h.getConfig(r.Context(), id, userFromContext(r.Context()).ID)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to load config")
+		return nil
+	}
+	if cfg == nil {
+		apiError(w, http.StatusNotFound, apiErrNotFound, "config not found")
+		return nil
+	}
+	return cfg
+}
+
+// decodeAPIConfigRequest reads a create or update body and checks the fields it sets.
+func (h *ConfigHandler) decodeAPIConfigRequest(w http.ResponseWriter, r *http.Request) (*apiConfigRequest, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var req apiConfigRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, "invalid JSON body: "+err.Error())
+		return nil, false
+	}
+	if req.Name != nil && *req.Name == "" {
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, "name must not be empty")
+		return nil, false
+	}
+	if s := req.SyncSchedule; s != nil && parseSyncSchedule(*s) != *s {
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, fmt.Sprintf("syncSchedule must be one of %q, %q or %q",
+			db.SyncScheduleNone, db.SyncScheduleWeekly, db.SyncScheduleMonthly))
+		return nil, false
+	}
+	if req.YAML != nil {
+		if _, err := h.parseAppConfig(*req.YAML); err != nil {
+			apiError(w, http.StatusUnprocessableEntity, apiErrInvalidConfig, err.Error())
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+// APIListConfigs returns the configs the current user can open: all of them for power
+// users, their own for everyone else.
+func (h *ConfigHandler) APIListConfigs(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	var cfgs []*db.Config
+	var err error
+	if roleFromContext(r.Context()) == perm.RolePower {
+		var all []*db.ConfigWithAuthor
+		all, err = h.configs.ListAllWithAuthor(nil)
+		for _, c := range all {
+			cfgs = append(cfgs, &c.Config)
+		}
+	} else {
+		cfgs, err = h.configs.ListByUser(user.ID)
+	}
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to load configs")
+		return
+	}
+	out := make([]apiConfig, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		out = append(out, newAPIConfig(cfg, false))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"configs": out})
+}
+
+// APICreateConfig creates a config from a name, YAML and optional sync schedule.
+func (h *ConfigHandler) APICreateConfig(w http.ResponseWriter, r *http.Request) {
+	if !roleFromContext(r.Context()).CanCreate() {
+		apiError(w, http.StatusForbidden, apiErrForbidden, "you do not have permission to create configs")
+		return
+	}
+	user := userFromContext(r.Context())
+	req, ok := h.decodeAPIConfigRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.Name == nil || req.YAML == nil {
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, "name and yaml are required")
+		return
+	}
+	syncSchedule := db.SyncScheduleNone
+	if req.SyncSchedule != nil {
+		syncSchedule = *req.SyncSchedule
+	}
+	var nextSyncAt *time.Time
+	if syncSchedule != db.SyncScheduleNone {
+		t := scheduler.NextSyncAt(syncSchedule, time.Now())
+		nextSyncAt = &t
+	}
+	cfg, err := h.configs.Create(user.ID, *req.Name, appconfig.CurrentSchemaVersion, *req.YAML, syncSchedule, nextSyncAt)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to create config")
+		return
+	}
+	h.recordAudit(r, cfg, db.AuditConfigCreate, "", "", cfg.ConfigYAML)
+	go h.validateAndUpdateStatus(cfg.ID, user.ID, cfg.ConfigYAML)
+	w.Header().Set("Location", fmt.Sprintf("%s/api/v1/configs/%d", h.basePath, cfg.ID))
+	writeJSON(w, http.StatusCreated, newAPIConfig(cfg, true))
+}
+
+// APIGetConfig returns one config with its YAML.
+func (h *ConfigHandler) APIGetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIConfig(cfg, true))
+}
+
+// APIUpdateConfig changes a config's name, YAML or sync schedule.
+func (h *ConfigHandler) APIUpdateConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil {
+		return
+	}
+	user := userFromContext(r.Context())
+	if !roleFromContext(r.Context()).CanEditConfig(cfg.UserID, user.ID) {
+		apiError(w, http.StatusForbidden, apiErrForbidden, "you do not have permission to edit this config")
+		return
+	}
+	req, ok := h.decodeAPIConfigRequest(w, r)
+	if !ok {
+		return
+	}
+	name, yamlContent, syncSchedule := cfg.Name, cfg.ConfigYAML, cfg.SyncSchedule
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.YAML != nil {
+		yamlContent = *req.YAML
+	}
+	if req.SyncSchedule != nil {
+		syncSchedule = *req.SyncSchedule
+	}
+	nextSyncAt := computeNextSyncAt(cfg.SyncSchedule, cfg.NextSyncAt, syncSchedule)
+	if err := h.configs.Update(cfg.ID, cfg.UserID, user.ID, name, yamlContent, syncSchedule, nextSyncAt, ""); err != nil {
+		apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to update config")
+		return
+	}
+	h.recordUpdate(r, cfg, name, yamlContent, syncSchedule)
+	go h.validateAndUpdateStatus(cfg.ID, user.ID, yamlContent)
+
+	updated, err := h.configs.GetByID(cfg.ID)
+	if err != nil || updated == nil {
+		apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to load config")
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIConfig(updated, true))
+}
+
+// APIDeleteConfig deletes a config.
+func (h *ConfigHandler) APIDeleteConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil {
+		return
+	}
+	user := userFromContext(r.Context())
+	if !roleFromContext(r.Context()).CanEditConfig(cfg.UserID, user.ID) {
+		apiError(w, http.StatusForbidden, apiErrForbidden, "you do not have permission to delete this config")
+		return
+	}
+	if err := h.configs.Delete(cfg.ID, cfg.UserID); err != nil {
+		apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to delete config")
+		return
+	}
+	h.recordAudit(r, cfg, db.AuditConfigDelete, "", cfg.ConfigYAML, "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// APIValidateConfig re-validates a config and returns its new status.
+func (h *ConfigHandler) APIValidateConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil {
+		return
+	}
+	if !roleFromContext(r.Context()).CanSyncConfig(cfg.UserID, userFromContext(r.Context()).ID) {
+		apiError(w, http.StatusForbidden, apiErrForbidden, "you do not have permission to validate this config")
+		return
+	}
+	status, msg := h.revalidate(r, cfg)
+	writeJSON(w, http.StatusOK, map[string]string{"status": string(status), "message": msg})
+}
+
+// apiManualOperation checks that the current user may sync or wipe cfg by hand, writing an
+// error response and returning false if not.
+func (h *ConfigHandler) apiManualOperation(w http.ResponseWriter, r *http.Request, cfg *db.Config, action string) bool {
+	if !roleFromContext(r.Context()).CanSyncConfig(cfg.UserID, userFromContext(r.Context()).ID) {
+		apiError(w, http.StatusForbidden, apiErrForbidden, "you do not have permission to "+action+" this config")
+		return false
+	}
+	if cfg.SyncSchedule != db.SyncScheduleNone {
+		apiError(w, http.StatusConflict, apiErrConflict, "manual operations are disabled while Auto-Sync is on")
+		return false
+	}
+	return true
+}
+
+// APISyncConfig syncs a config and returns the recorded run. An optional JSON body
+// {"onManualEdit": "overwrite"|"respect"} overrides the config's manual-edit policy once.
+func (h *ConfigHandler) APISyncConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil || !h.apiManualOperation(w, r, cfg, "sync") {
+		return
+	}
+	var req struct {
+		OnManualEdit domain.DriftPolicy `json:"onManualEdit"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	switch req.OnManualEdit {
+	case "", domain.DriftPolicyOverwrite, domain.DriftPolicyRespect:
+	default:
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, `onManualEdit must be "overwrite" or "respect"`)
+		return
+	}
+	_, run := h.syncAndRecord(r, cfg, req.OnManualEdit, db.SyncTriggerAPI)
+	writeJSON(w, http.StatusOK, newAPISyncRun(run))
+}
+
+// APIWipeConfig deletes the config's blockers in its date range.
+func (h *ConfigHandler) APIWipeConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil || !h.apiManualOperation(w, r, cfg, "wipe") {
+		return
+	}
+	msg, isErr := h.runWipe(r.Context(), userFromContext(r.Context()).ID, cfg)
+	h.recordAudit(r, cfg, db.AuditWipe, msg, "", "")
+	if isErr {
+		apiError(w, http.StatusBadGateway, apiErrUpstream, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": msg})
+}
+
+// APIListBlockers returns the config's blocker events in its date range.
+func (h *ConfigHandler) APIListBlockers(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil {
+		return
+	}
+	items, rangeStart, rangeEnd, err := h.fetchBlockers(r.Context(), userFromContext(r.Context()).ID, cfg)
+	if err != nil {
+		apiError(w, http.StatusBadGateway, apiErrUpstream, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"rangeStart": rangeStart.Format(time.DateOnly),
+		"rangeEnd":   rangeEnd.Format(time.DateOnly),
+		"blockers":   items,
+	})
+}
+
+// APIPreviewConfig returns what a sync would change, without writing anything.
+func (h *ConfigHandler) APIPreviewConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.apiConfigFromPath(w, r)
+	if cfg == nil {
+		return
+	}
+	preview, err := h.runPreview(r.Context(), userFromContext(r.Context()).ID, cfg)
+	if err != nil {
+		apiError(w, http.StatusBadGateway, apiErrUpstream, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, preview)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/perm"
+	"gopkg.in/yaml.v3"
+)
+
+const apiTestYAML = `
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    countryCode: "jpn"
+    todayIsFreezeDayIf:
+      - tomorrow:
+        - isNonBusinessDay
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+`
+
+type apiTestEnv struct {
+	h     *ConfigHandler
+	users *db.UserStore
+}
+
+func newAPITestEnv(t *testing.T) *apiTestEnv {
+	t.Helper()
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() }) //nolint:errcheck,gosec
+	h := NewConfigHandler(db.NewConfigStore(database), db.NewLedgerStore(database), db.NewSyncRunStore(database),
+		db.NewAuditStore(database), db.NewTokenStore(database), nil, "")
+	return &apiTestEnv{h: h, users: db.NewUserStore(database)}
+}
+
+func (e *apiTestEnv) user(t *testing.T, email string) *db.User {
+	t.Helper()
+	u, err := e.users.Upsert("google-"+email, email, email)
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	return u
+}
+
+// call runs fn as user with role and returns the response.
+func call(fn http.HandlerFunc, user *db.User, role perm.Role, method, path, body string, pathValues ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(pathValues); i += 2 {
+		r.SetPathValue(pathValues[i], pathValues[i+1])
+	}
+	ctx := context.WithValue(r.Context(), userCtxKey, user)
+	ctx = context.WithValue(ctx, roleCtxKey, role)
+	w := httptest.NewRecorder()
+	fn(w, r.WithContext(ctx))
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body apiErrorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error body %q is not JSON: %v", w.Body.String(), err)
+	}
+	return body.Error.Code
+}
+
+func TestAPIConfigLifecycle(t *testing.T) {
+	env := newAPITestEnv(t)
+	owner, other := env.user(t, "owner@example.com"), env.user(t, "other@example.com")
+	createBody, _ := json.Marshal(map[string]string{"name": "Team", "yaml": apiTestYAML})
+
+	if w := call(env.h.APICreateConfig, owner, perm.RoleReadOnly, "POST", "/api/v1/configs", string(createBody)); w.Code != http.StatusForbidden || errorCode(t, w) != apiErrForbidden {
+		t.Errorf("create as read-only = %d %s, want 403 forbidden", w.Code, w.Body)
+	}
+	if w := call(env.h.APICreateConfig, owner, perm.RoleWrite, "POST", "/api/v1/configs", `{"name":"Team","yaml":"shared: ["}`); w.Code != http.StatusUnprocessableEntity || errorCode(t, w) != apiErrInvalidConfig {
+		t.Errorf("create with broken YAML = %d %s, want 422 invalid_config", w.Code, w.Body)
+	}
+	if w := call(env.h.APICreateConfig, owner, perm.RoleWrite, "POST", "/api/v1/configs", `{"name":"Team","colour":"red"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("create with an unknown field = %d, want 400", w.Code)
+	}
+
+	w := call(env.h.APICreateConfig, owner, perm.RoleWrite, "POST", "/api/v1/configs", string(createBody))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s, want 201", w.Code, w.Body)
+	}
+	var created apiConfig
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.Revision != 1 || created.YAML != apiTestYAML {
+		t.Fatalf("created config = %+v, %v; want revision 1 with the YAML", created, err)
+	}
+	id := []string{"id", strconv.FormatInt(created.ID, 10)}
+
+	if w := call(env.h.APIGetConfig, other, perm.RoleWrite, "GET", "/api/v1/configs/x", "", id...); w.Code != http.StatusNotFound || errorCode(t, w) != apiErrNotFound {
+		t.Errorf("get as another write user = %d %s, want 404 not_found", w.Code, w.Body)
+	}
+	if w := call(env.h.APIUpdateConfig, other, perm.RolePower, "PATCH", "/api/v1/configs/x", `{"syncSchedule":"weekly"}`, id...); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"syncSchedule": "weekly"`) {
+		t.Errorf("update as power user = %d %s, want 200 with the weekly schedule", w.Code, w.Body)
+	}
+	if w := call(env.h.APISyncConfig, owner, perm.RoleWrite, "POST", "/api/v1/configs/x/sync", "", id...); w.Code != http.StatusConflict || errorCode(t, w) != apiErrConflict {
+		t.Errorf("sync with Auto-Sync on = %d %s, want 409 conflict", w.Code, w.Body)
+	}
+	if w := call(env.h.APIDeleteConfig, owner, perm.RoleReadOnly, "DELETE", "/api/v1/configs/x", "", id...); w.Code != http.StatusForbidden {
+		t.Errorf("delete as read-only = %d, want 403", w.Code)
+	}
+	if w := call(env.h.APIDeleteConfig, owner, perm.RoleWrite, "DELETE", "/api/v1/configs/x", "", id...); w.Code != http.StatusNoContent {
+		t.Errorf("delete as owner = %d %s, want 204", w.Code, w.Body)
+	}
+	if w := call(env.h.APIListConfigs, owner, perm.RoleWrite, "GET", "/api/v1/configs", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"configs": []`) {
+		t.Errorf("list after delete = %d %s, want an empty list", w.Code, w.Body)
+	}
+}
+
+func TestRequireAPIAuth(t *testing.T) {
+	database, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer database.Close() //nolint:errcheck
+	users, apiTokens := db.NewUserStore(database), db.NewAPITokenStore(database)
+	user, err := users.Upsert("google-1", "ci@example.com", "CI")
+	if err != nil {
+		t.Fatalf("upsert user: %v", err)
+	}
+	token, _, err := apiTokens.Create(user.ID, "ci")
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	var gotUser *db.User
+	var gotRole perm.Role
+	h := RequireAPIAuth(users, apiTokens, []byte("secret"), perm.New("", "ci@example.com"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotRole = userFromContext(r.Context()), roleFromContext(r.Context())
+	}))
+
+	for _, auth := range []string{"", "Basic abc", "Bearer " + token + "x"} {
+		r := httptest.NewRequest("GET", "/api/v1/configs", nil)
+		if auth != "" {
+			r.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusUnauthorized || errorCode(t, w) != apiErrUnauthorized {
+			t.Errorf("Authorization %q = %d %s, want 401 unauthorized", auth, w.Code, w.Body)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/api/v1/configs", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if gotUser == nil || gotUser.ID != user.ID || gotRole != perm.RoleWrite {
+		t.Errorf("valid token reached the handler as %+v with role %q, want the token's write user", gotUser, gotRole)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	var doc struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(openAPIYAML), &doc); err != nil {
+		t.Fatalf("openapi.yaml does not parse: %v", err)
+	}
+	// Every route registered under /api/v1 in cmd/server/main.go.
+	for _, route := range []string{
+		"get /configs", "post /configs",
+		"get /configs/{id}", "patch /configs/{id}", "delete /configs/{id}",
+		"post /configs/{id}/validate", "post /configs/{id}/sync", "post /configs/{id}/wipe",
+		"get /configs/{id}/blockers", "get /configs/{id}/preview", "get /configs/{id}/freeze",
+	} {
+		method, path, _ := strings.Cut(route, " ")
+		if _, ok := doc.Paths[path][method]; !ok {
+			t.Errorf("openapi.yaml does not document %s", route)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	NewSchemaHandler("/app").HandleOpenAPI(w, httptest.NewRequest("GET", "/app/api/v1/openapi.yaml", nil))
+	if !strings.Contains(w.Body.String(), "- url: /app/api/v1") {
+		t.Error("HandleOpenAPI does not prefix the server URL with the base path")
+	}
+}
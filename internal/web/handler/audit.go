@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+)
+
+// auditPageSize is the number of entries per page of the audit log.
+const auditPageSize = 20
+
+// recordAudit appends an entry for an action the current user took on cfg. A failure is
+// logged rather than failing the action, which has already happened.
+func (h *ConfigHandler) recordAudit(r *http.Request, cfg *db.Config, action, detail, oldYAML, newYAML string) {
+	e := &db.AuditEntry{
+		ConfigID:   cfg.ID,
+		ConfigName: cfg.Name,
+		Action:     action,
+		Detail:     detail,
+		OldYAML:    oldYAML,
+		NewYAML:    newYAML,
+	}
+	if user := userFromContext(r.Context()); user != nil {
+		e.UserID, e.UserEmail = &user.ID, user.Email
+	}
+	if err := h.audit.Append(e); err != nil {
+		log.WithError(err).WithField("config_id", cfg.ID).WithField("action", action).Error("failed to record audit entry")
+	}
+}
+
+// recordUpdate appends the entry for saving cfg with a new name, YAML and sync schedule.
+func (h *ConfigHandler) recordUpdate(r *http.Request, cfg *db.Config, name, yamlContent, syncSchedule string) {
+	var changes []string
+	if name != cfg.Name {
+		changes = append(changes, fmt.Sprintf("renamed %q → %q", cfg.Name, name))
+	}
+	if syncSchedule != cfg.SyncSchedule {
+		changes = append(changes, fmt.Sprintf("Auto-Sync %s → %s", cfg.SyncSchedule, syncSchedule))
+	}
+	saved := *cfg
+	saved.Name = name
+	h.recordAudit(r, &saved, db.AuditConfigUpdate, strings.Join(changes, "; "), cfg.ConfigYAML, yamlContent)
+}
+
+// HandleAudit returns a page of the config's audit log, newest first (HTMX).
+// The page number comes from ?page=, starting at 1.
+func (h *ConfigHandler) HandleAudit(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	entries, total, err := h.audit.ListForConfig(cfg.ID, auditPageSize, (page-1)*auditPageSize)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Audit Log", "failed to load audit log: "+err.Error(), true)) //nolint:errcheck
+		return
+	}
+	fmt.Fprint(w, auditLogHTML(h.basePath, cfg.ID, entries, total, page)) //nolint:errcheck,gosec
+}
+
+// HandleAuditEntry returns one audit entry with a side-by-side diff of the YAML it
+// changed (HTMX).
+func (h *ConfigHandler) HandleAuditEntry(w http.ResponseWriter, r *http.Request) {
+	cfg, entry, ok := h.auditEntryFromPath(w, r)
+	if !ok {
+		return
+	}
+	user := userFromContext(r.Context())
+	canRestore := roleFromContext(r.Context()).CanEditConfig(cfg.UserID, user.ID) &&
+		entry.NewYAML != "" && entry.NewYAML != cfg.ConfigYAML
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, auditEntryHTML(h.basePath, entry, canRestore)) //nolint:errcheck,gosec
+}
+
+// HandleAuditRestore replaces the config's YAML with the version an audit entry saved.
+// Returns HX-Redirect to the detail page.
+func (h *ConfigHandler) HandleAuditRestore(w http.ResponseWriter, r *http.Request) {
+	cfg, entry, ok := h.auditEntryFromPath(w, r)
+	if !ok {
+		return
+	}
+	user := userFromContext(r.Context())
+	if role := roleFromContext(r.Context()); !role.CanEditConfig(cfg.UserID, user.ID) {
+		httpError(w, http.StatusForbidden, "you do not have permission to edit this config")
+		return
+	}
+	if entry.NewYAML == "" {
+		httpError(w, http.StatusBadRequest, "this entry did not save a config version")
+		return
+	}
+	// Versions saved before a schema change may no longer be valid.
+	if _, err := h.parseAppConfig(entry.NewYAML); err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, actionResultHTML("Restore", "This version can no longer be restored: "+err.Error(), true)) //nolint:errcheck
+		return
+	}
+	if err := h.configs.Update(cfg.ID, cfg.UserID, user.ID, cfg.Name, entry.NewYAML, cfg.SyncSchedule, cfg.NextSyncAt,
+		fmt.Sprintf("restored from audit entry #%d", entry.ID)); err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to restore config")
+		return
+	}
+	h.recordAudit(r, cfg, db.AuditConfigRestore,
+		fmt.Sprintf("restored the version saved by entry #%d (%s)", entry.ID, entry.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+		cfg.ConfigYAML, entry.NewYAML)
+	go h.validateAndUpdateStatus(cfg.ID, user.ID, entry.NewYAML)
+	w.Header().Set("HX-Redirect", fmt.Sprintf(h.basePath+"/configs/%d", cfg.ID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// auditEntryFromPath loads the config and audit entry named by the path, writing an error
+// response and returning false if either is missing.
+func (h *ConfigHandler) auditEntryFromPath(w http.ResponseWriter, r *http.Request) (*db.Config, *db.AuditEntry, bool) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return nil, nil, false
+	}
+	entryID, err := strconv.ParseInt(r.PathValue("entryID"), 10, 64)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "invalid audit entry id")
+		return nil, nil, false
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return nil, nil, false
+	}
+	entry, err := h.audit.Get(cfg.ID, entryID)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to load audit entry")
+		return nil, nil, false
+	}
+	if entry == nil {
+		httpError(w, http.StatusNotFound, "audit entry not found")
+		return nil, nil, false
+	}
+	return cfg, entry, true
+}
+
+// auditActionLabel names each audited action in the audit log.
+var auditActionLabel = map[string]string{
+	db.AuditConfigCreate:   "Created",
+	db.AuditConfigUpdate:   "Edited",
+	db.AuditConfigDelete:   "Deleted",
+	db.AuditConfigRestore:  "Restored",
+	db.AuditConfigRollback: "Rolled back",
+	db.AuditAutoSync:       "Auto-Sync changed",
+	db.AuditValidate:       "Validated",
+	db.AuditSync:           "Synced",
+	db.AuditWipe:           "Wiped",
+	db.AuditLegacyBlocker:  "Legacy blocker resolved",
+}
+
+func auditActionText(action string) string {
+	if label, ok := auditActionLabel[action]; ok {
+		return label
+	}
+	return action
+}
+
+func auditBy(e *db.AuditEntry) string {
+	if e.UserEmail != "" {
+		return e.UserEmail
+	}
+	return "unknown user"
+}
+
+func auditLogHTML(basePath string, configID int64, entries []*db.AuditEntry, total, page int) string {
+	header := fmt.Sprintf(`
+  <div style="font-size:0.88rem;color:var(--pico-muted-color);margin-bottom:0.5rem">
+    Audit Log &nbsp;·&nbsp; <strong style="color:var(--pico-color)">%d entries</strong>
+  </div>`, total)
+	if total == 0 {
+		return `<div>` + header + `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>Nothing has been recorded for this config yet.</em></p></div>`
+	}
+
+	auditURL := fmt.Sprintf("%s/configs/%d/audit", basePath, configID)
+	var rows strings.Builder
+	for _, e := range entries {
+		view := ""
+		if e.OldYAML != "" || e.NewYAML != "" {
+			view = historyButton(fmt.Sprintf("%s/%d", auditURL, e.ID), "View YAML", "outline")
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+			html.EscapeString(e.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+			html.EscapeString(auditBy(e)),
+			html.EscapeString(auditActionText(e.Action)),
+			html.EscapeString(e.Detail),
+			view)
+	}
+
+	lastPage := (total + auditPageSize - 1) / auditPageSize
+	var pager []string
+	if page > 1 {
+		pager = append(pager, historyButton(fmt.Sprintf("%s?page=%d", auditURL, page-1), "← Newer", "outline secondary"))
+	}
+	pager = append(pager, fmt.Sprintf(`<span style="font-size:0.8rem;color:var(--pico-muted-color)">Page %d of %d</span>`, page, lastPage))
+	if page < lastPage {
+		pager = append(pager, historyButton(fmt.Sprintf("%s?page=%d", auditURL, page+1), "Older →", "outline secondary"))
+	}
+	return fmt.Sprintf(`
+<div>%s
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>When</th><th>By</th><th>Action</th><th>Detail</th><th></th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+  <div style="display:flex;gap:0.75rem;align-items:center;justify-content:center">%s</div>
+</div>`, header, rows.String(), strings.Join(pager, ""))
+}
+
+func auditEntryHTML(basePath string, e *db.AuditEntry, canRestore bool) string {
+	restore := ""
+	if canRestore {
+		restore = fmt.Sprintf(`<button class="secondary" style="margin:0;padding:0.2rem 0.7rem;font-size:0.8rem"
+      hx-post="%s/configs/%d/audit/%d/restore" hx-target="#action-result" hx-swap="innerHTML"
+      hx-confirm="Replace the current config with the version after this change?">↩ Restore this version</button>`,
+			basePath, e.ConfigID, e.ID)
+	}
+	detail := ""
+	if e.Detail != "" {
+		detail = fmt.Sprintf(`<p style="font-size:0.85rem">%s</p>`, html.EscapeString(e.Detail))
+	}
+	return fmt.Sprintf(`
+<div>
+  <div style="display:flex;justify-content:space-between;align-items:center;gap:0.5rem;margin-bottom:0.5rem">
+    <div style="font-size:0.88rem;color:var(--pico-muted-color)">
+      Audit Entry #%d &nbsp;·&nbsp; %s by %s &nbsp;·&nbsp; %s
+    </div>
+    <div style="display:flex;gap:0.5rem">%s%s</div>
+  </div>%s
+  %s
+</div>`,
+		e.ID, html.EscapeString(auditActionText(e.Action)), html.EscapeString(auditBy(e)),
+		html.EscapeString(e.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04:05 JST")),
+		restore, historyButton(fmt.Sprintf("%s/configs/%d/audit", basePath, e.ConfigID), "← Audit Log", "outline secondary"),
+		detail, yamlDiffHTML(e.OldYAML, e.NewYAML))
+}
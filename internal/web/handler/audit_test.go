@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+)
+
+func TestDiffLines(t *testing.T) {
+	old := "a: 1\nb: 2\nc: 3\nd: 4\n"
+	new := "a: 1\nb: 20\nc: 3\nd: 4\ne: 5\n"
+
+	got := diffLines(old, new)
+	want := []diffRow{
+		{OldNo: 1, NewNo: 1, Old: "a: 1", New: "a: 1"},
+		{OldNo: 2, NewNo: 2, Old: "b: 2", New: "b: 20", Changed: true},
+		{OldNo: 3, NewNo: 3, Old: "c: 3", New: "c: 3"},
+		{OldNo: 4, NewNo: 4, Old: "d: 4", New: "d: 4"},
+		{NewNo: 5, New: "e: 5", Changed: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("diffLines() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A created config has no old side.
+	for i, row := range diffLines("", "a: 1\nb: 2\n") {
+		if row.OldNo != 0 || row.NewNo != i+1 || !row.Changed {
+			t.Errorf("creation row %d = %+v, want only a new line", i, row)
+		}
+	}
+}
+
+func TestAuditEntryHTML(t *testing.T) {
+	userID := int64(3)
+	e := &db.AuditEntry{
+		ID: 9, ConfigID: 7, UserID: &userID, UserEmail: "a@example.com", Action: db.AuditConfigUpdate,
+		Detail: `renamed "Old" → "New"`, OldYAML: "name: <old>\n", NewYAML: "name: <new>\n",
+		CreatedAt: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	got := auditEntryHTML("/app", e, true)
+	for _, want := range []string{"Audit Entry #9", "Edited by a@example.com", "2026-10-05 09:00:00 JST", "&lt;old&gt;", "&lt;new&gt;",
+		`hx-post="/app/configs/7/audit/9/restore"`, `hx-get="/app/configs/7/audit"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("auditEntryHTML missing %q", want)
+		}
+	}
+	if got := auditEntryHTML("/app", e, false); strings.Contains(got, "/restore") {
+		t.Error("auditEntryHTML offers a restore without permission")
+	}
+
+	list := auditLogHTML("/app", 7, []*db.AuditEntry{e, {ID: 8, ConfigID: 7, Action: db.AuditSync, Detail: "success"}}, 2, 1)
+	if n := strings.Count(list, "View YAML"); n != 1 {
+		t.Errorf("auditLogHTML has %d View YAML buttons, want 1 (only the edit saved YAML)", n)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"html"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,13 +16,16 @@ import (
 	"github.com/nvat/tgifreezeday/internal/adapter/db"
 	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
 	appconfig "github.com/nvat/tgifreezeday/internal/config"
+	"github.com/nvat/tgifreezeday/internal/consts"
 	"github.com/nvat/tgifreezeday/internal/domain"
 	"github.com/nvat/tgifreezeday/internal/helpers"
+	"github.com/nvat/tgifreezeday/internal/holidays"
 	"github.com/nvat/tgifreezeday/internal/logging"
 	"github.com/nvat/tgifreezeday/internal/perm"
 	"github.com/nvat/tgifreezeday/internal/scheduler"
 	"golang.org/x/oauth2"
 	googleapi "google.golang.org/api/googleapi"
+	"gopkg.in/yaml.v3"
 )
 
 var log = logging.GetLogger()
@@ -30,7 +35,6 @@ var jstDisplay = scheduler.JST
 
 const (
 	countryCodeJPN = "jpn"
-	countryCodeVNM = "vnm"
 
 	ruleAnchorToday    = "today"
 	ruleAnchorTomorrow = "tomorrow"
@@ -38,19 +42,29 @@ const (
 
 type ConfigHandler struct {
 	configs     *db.ConfigStore
+	ledgers     *db.LedgerStore
+	runs        *db.SyncRunStore
+	audit       *db.AuditStore
 	tokens      *db.TokenStore
 	oauthCfg    *oauth2.Config
 	validateSem chan struct{}
 	basePath    string
+	// holidayCache serves the freeze query's public holidays without calling Google each time.
+	holidayCache *holidays.Cache
 }
 
-func NewConfigHandler(configs *db.ConfigStore, tokens *db.TokenStore, oauthCfg *oauth2.Config, basePath string) *ConfigHandler {
+func NewConfigHandler(configs *db.ConfigStore, ledgers *db.LedgerStore, runs *db.SyncRunStore, audit *db.AuditStore, tokens *db.TokenStore, oauthCfg *oauth2.Config, basePath string) *ConfigHandler {
 	return &ConfigHandler{
 		configs:     configs,
+		ledgers:     ledgers,
+		runs:        runs,
+		audit:       audit,
 		tokens:      tokens,
 		oauthCfg:    oauthCfg,
 		validateSem: make(chan struct{}, 5),
 		basePath:    basePath,
+
+		holidayCache: holidays.NewCache(holidayCacheTTL),
 	}
 }
 
@@ -136,6 +150,7 @@ func (h *ConfigHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		httpError(w, http.StatusInternalServerError, "failed to create config")
 		return
 	}
+	h.recordAudit(r, cfg, db.AuditConfigCreate, "", "", yamlContent)
 
 	go h.validateAndUpdateStatus(cfg.ID, user.ID, yamlContent)
 	redirectTo(w, r, fmt.Sprintf(h.basePath+"/configs/%d", cfg.ID))
@@ -268,10 +283,11 @@ func (h *ConfigHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 
 	nextSyncAt := computeNextSyncAt(cfg.SyncSchedule, cfg.NextSyncAt, syncSchedule)
 
-	if err := h.configs.Update(id, cfg.UserID, name, yamlContent, syncSchedule, nextSyncAt); err != nil {
+	if err := h.configs.Update(id, cfg.UserID, user.ID, name, yamlContent, syncSchedule, nextSyncAt, ""); err != nil {
 		httpError(w, http.StatusInternalServerError, "failed to update config")
 		return
 	}
+	h.recordUpdate(r, cfg, name, yamlContent, syncSchedule)
 	go h.validateAndUpdateStatus(id, user.ID, yamlContent)
 	redirectTo(w, r, fmt.Sprintf(h.basePath+"/configs/%d", id))
 }
@@ -301,6 +317,7 @@ func (h *ConfigHandler) doDelete(w http.ResponseWriter, r *http.Request, id, use
 		httpError(w, http.StatusInternalServerError, "failed to delete config")
 		return
 	}
+	h.recordAudit(r, cfg, db.AuditConfigDelete, "", cfg.ConfigYAML, "")
 	redirectTo(w, r, h.basePath+"/dashboard")
 }
 
@@ -324,12 +341,21 @@ func (h *ConfigHandler) HandleValidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	oldStatus := cfg.Status
+	newStatus, msg := h.revalidate(r, cfg)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, validateResultHTML(oldStatus, newStatus, msg)) //nolint:errcheck
+}
+
+// revalidate validates cfg for the current user, stores the new status and records the
+// validation in the audit log.
+func (h *ConfigHandler) revalidate(r *http.Request, cfg *db.Config) (db.ConfigStatus, string) {
+	user := userFromContext(r.Context())
 	newStatus, msg := h.validateConfig(r.Context(), user.ID, cfg.ConfigYAML)
-	if err := h.configs.UpdateStatus(id, newStatus, msg); err != nil {
+	if err := h.configs.UpdateStatus(cfg.ID, newStatus, msg); err != nil {
 		log.WithError(err).Error("failed to update config status after validate")
 	}
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	fmt.Fprint(w, validateResultHTML(oldStatus, newStatus, msg)) //nolint:errcheck
+	h.recordAudit(r, cfg, db.AuditValidate, fmt.Sprintf("%s → %s", cfg.Status, newStatus), "", "")
+	return newStatus, msg
 }
 
 // HandleSync runs sync and returns result HTML (HTMX).
@@ -354,9 +380,37 @@ func (h *ConfigHandler) HandleSync(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, actionResultHTML("Sync", "Manual operations are disabled while Auto-Sync is on. Disable Auto-Sync first if you want to sync or wipe manually.", true)) //nolint:errcheck
 		return
 	}
-	msg, isErr := h.runSync(r.Context(), user.ID, cfg)
+	// The Manual Edits panel resolves drift by syncing once with an explicit policy.
+	var policy domain.DriftPolicy
+	switch p := domain.DriftPolicy(r.FormValue("on_manual_edit")); p {
+	case "":
+	case domain.DriftPolicyOverwrite, domain.DriftPolicyRespect:
+		policy = p
+	default:
+		httpError(w, http.StatusBadRequest, "invalid manual edit policy")
+		return
+	}
+	outcome, _ := h.syncAndRecord(r, cfg, policy, db.SyncTriggerManual)
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	fmt.Fprint(w, actionResultHTML("Sync", msg, isErr)) //nolint:errcheck
+	fmt.Fprint(w, syncOutcomeHTML(outcome)) //nolint:errcheck,gosec
+}
+
+// syncAndRecord syncs cfg for the current user and records the run in the sync history
+// and the audit log.
+func (h *ConfigHandler) syncAndRecord(r *http.Request, cfg *db.Config, policy domain.DriftPolicy, trigger string) (*domain.SyncOutcome, *db.SyncRun) {
+	user := userFromContext(r.Context())
+	startedAt := time.Now().UTC()
+	outcome := h.runSync(r.Context(), user.ID, cfg, policy)
+	run := db.NewSyncRun(cfg, trigger, &user.ID, startedAt, time.Now().UTC(), outcome)
+	if err := h.runs.Record(run); err != nil {
+		log.WithError(err).WithField("config_id", cfg.ID).Error("failed to record sync run")
+	}
+	detail := string(outcome.Status)
+	if policy != "" {
+		detail += ", on_manual_edit=" + string(policy)
+	}
+	h.recordAudit(r, cfg, db.AuditSync, detail, "", "")
+	return outcome, run
 }
 
 // HandleWipe wipes blockers and returns result HTML (HTMX).
@@ -382,6 +436,7 @@ func (h *ConfigHandler) HandleWipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	msg, isErr := h.runWipe(r.Context(), user.ID, cfg)
+	h.recordAudit(r, cfg, db.AuditWipe, msg, "", "")
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	fmt.Fprint(w, actionResultHTML("Wipe", msg, isErr)) //nolint:errcheck
 }
@@ -414,6 +469,7 @@ func (h *ConfigHandler) HandleUpdateAutoSync(w http.ResponseWriter, r *http.Requ
 		httpError(w, http.StatusInternalServerError, "failed to update auto-sync")
 		return
 	}
+	h.recordAudit(r, cfg, db.AuditAutoSync, fmt.Sprintf("%s → %s", cfg.SyncSchedule, newSchedule), "", "")
 	w.Header().Set("HX-Redirect", fmt.Sprintf(h.basePath+"/configs/%d", id))
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -436,6 +492,85 @@ func (h *ConfigHandler) HandleListBlockers(w http.ResponseWriter, r *http.Reques
 	fmt.Fprint(w, partial) //nolint:errcheck,gosec
 }
 
+// HandlePreview computes the changes a sync would make without writing anything.
+// Returns a per-date table partial (HTMX), or the plan as JSON when ?format=json is set.
+func (h *ConfigHandler) HandlePreview(w http.ResponseWriter, r *http.Request) {
+	asJSON := r.URL.Query().Get("format") == "json"
+	// Scripts reading the JSON plan get JSON errors too, in the shape of the API's.
+	fail := func(status int, code, msg string) {
+		if asJSON {
+			apiError(w, status, code, msg)
+			return
+		}
+		httpError(w, status, msg)
+	}
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		fail(http.StatusBadRequest, apiErrBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		fail(http.StatusNotFound, apiErrNotFound, "config not found")
+		return
+	}
+	preview, err := h.runPreview(r.Context(), user.ID, cfg)
+	if asJSON {
+		if err != nil {
+			fail(http.StatusBadGateway, apiErrUpstream, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, preview)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Preview", err.Error(), true)) //nolint:errcheck
+		return
+	}
+	fmt.Fprint(w, previewHTML(preview)) //nolint:errcheck,gosec
+}
+
+// HandleDrift lists the blocker events edited by hand since the last sync, with buttons to
+// overwrite or keep the edits. Returns an HTML partial (HTMX).
+func (h *ConfigHandler) HandleDrift(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	canSync := roleFromContext(r.Context()).CanSyncConfig(cfg.UserID, user.ID)
+	partial := h.listDrift(r.Context(), user.ID, cfg, canSync)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, partial) //nolint:errcheck,gosec
+}
+
+// HandleHolidays lists the holidays the config's sources report in the date range, with
+// whether the holiday policy counts or ignores each one and why. Returns an HTML partial (HTMX).
+func (h *ConfigHandler) HandleHolidays(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	partial := h.listHolidays(r.Context(), user.ID, cfg)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, partial) //nolint:errcheck,gosec
+}
+
 // --- internal helpers ---
 
 func (h *ConfigHandler) getConfig(ctx context.Context, id, userID int64) (*db.Config, error) {
@@ -476,9 +611,8 @@ func (h *ConfigHandler) validateConfig(ctx context.Context, userID int64, yamlCo
 	if err != nil {
 		return db.ConfigStatusInvalid, err.Error()
 	}
-	_, err = googlecalendar.NewRepositoryWithToken(ctx, h.oauthCfg, token, userID, h.tokens,
-		appCfg.ReadFrom.GoogleCalendar.CountryCode,
-		appCfg.WriteTo.GoogleCalendar.ID,
+	repo, err := googlecalendar.NewRepositoryWithToken(ctx, h.oauthCfg, token, userID, h.tokens,
+		appCfg.WriteTo.GoogleCalendar.ID, 0,
 	)
 	if err != nil {
 		var gapiErr *googleapi.Error
@@ -487,6 +621,10 @@ func (h *ConfigHandler) validateConfig(ctx context.Context, userID int64, yamlCo
 		}
 		return db.ConfigStatusInvalid, err.Error()
 	}
+	// Opening the holiday sources surfaces a missing or malformed iCalendar file on save.
+	if _, err := holidays.CalendarFromConfig(appCfg, repo); err != nil {
+		return db.ConfigStatusInvalid, err.Error()
+	}
 	return db.ConfigStatusValid, ""
 }
 
@@ -504,43 +642,46 @@ func (h *ConfigHandler) validateAndUpdateStatus(configID, userID int64, yamlCont
 	}
 }
 
-func (h *ConfigHandler) buildRepo(ctx context.Context, userID int64, cfg *appconfig.Config) (*googlecalendar.Repository, error) {
+func (h *ConfigHandler) buildRepo(ctx context.Context, userID, configID int64, cfg *appconfig.Config) (*googlecalendar.Repository, error) {
 	token, err := h.getToken(userID)
 	if err != nil {
 		return nil, err
 	}
 	return googlecalendar.NewRepositoryWithToken(ctx, h.oauthCfg, token, userID, h.tokens,
-		cfg.ReadFrom.GoogleCalendar.CountryCode,
-		cfg.WriteTo.GoogleCalendar.ID,
+		cfg.WriteTo.GoogleCalendar.ID, configID,
 	)
 }
 
-func (h *ConfigHandler) runSync(ctx context.Context, userID int64, cfg *db.Config) (string, bool) {
+// runSync syncs the config. A non-empty policy overrides the config's onManualEdit policy
+// for this run.
+func (h *ConfigHandler) runSync(ctx context.Context, userID int64, cfg *db.Config, policy domain.DriftPolicy) *domain.SyncOutcome {
 	appCfg, err := h.parseAppConfig(cfg.ConfigYAML)
 	if err != nil {
-		return err.Error(), true
+		return domain.FailedSync(err.Error())
 	}
-	repo, err := h.buildRepo(ctx, userID, appCfg)
+	repo, err := h.buildRepo(ctx, userID, cfg.ID, appCfg)
 	if err != nil {
-		return err.Error(), true
+		return domain.FailedSync(err.Error())
 	}
-	d := appCfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default
-	allDay := d.AllDay != nil && *d.AllDay
-	startTime, endTime := "", ""
-	if !allDay {
-		startTime = *d.StartTime
-		endTime = *d.EndTime
+	businessCal, err := holidays.CalendarFromConfig(appCfg, repo)
+	if err != nil {
+		return domain.FailedSync(err.Error())
 	}
 	rangeStart, rangeEnd := dateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
+	if _, err := scheduler.AdoptLegacyBlockers(repo, h.configs, cfg, appCfg, businessCal, rangeStart, rangeEnd); err != nil {
+		return domain.FailedSync(err.Error())
+	}
+	if policy == "" {
+		policy = appCfg.DriftPolicy()
+	}
 	return domain.RunSync(
 		repo,
+		businessCal,
 		rangeStart, rangeEnd,
-		domain.TodayIsFreezeDayIf(appCfg.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf),
-		*d.Summary,
-		*d.Description,
-		startTime,
-		endTime,
-		allDay,
+		appCfg.FreezeRules(),
+		appCfg.BlockerTemplates(cfg.Name),
+		h.ledgers.ForConfig(cfg.ID),
+		policy,
 	)
 }
 
@@ -549,21 +690,172 @@ func (h *ConfigHandler) runWipe(ctx context.Context, userID int64, cfg *db.Confi
 	if err != nil {
 		return err.Error(), true
 	}
-	repo, err := h.buildRepo(ctx, userID, appCfg)
+	repo, err := h.buildRepo(ctx, userID, cfg.ID, appCfg)
+	if err != nil {
+		return err.Error(), true
+	}
+	businessCal, err := holidays.CalendarFromConfig(appCfg, repo)
 	if err != nil {
 		return err.Error(), true
 	}
 	rangeStart, rangeEnd := dateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
-	if err := repo.WipeAllBlockersInRange(rangeStart, rangeEnd); err != nil {
-		return "failed to wipe blockers: " + err.Error(), true
+	unmatched, err := scheduler.AdoptLegacyBlockers(repo, h.configs, cfg, appCfg, businessCal, rangeStart, rangeEnd)
+	if err != nil {
+		return err.Error(), true
+	}
+	wipeErr := repo.WipeAllBlockersInRange(rangeStart, rangeEnd)
+	// Wiped blockers were removed on purpose, not by hand; forget them so they aren't reported
+	// as drift. Blockers a partial wipe left behind are simply found again by the next sync.
+	if err := h.ledgers.Replace(cfg.ID, nil); err != nil {
+		log.WithError(err).WithField("config_id", cfg.ID).Error("failed to clear blocker ledger after wipe")
+	}
+	if wipeErr != nil {
+		return "failed to wipe blockers: " + wipeErr.Error(), true
+	}
+	if len(unmatched) > 0 {
+		return fmt.Sprintf("Wipe complete. All managed blockers removed in the date range; %d blocker(s) from an earlier version that this config may not own were left for review on the config page.", len(unmatched)), false
 	}
 	return "Wipe complete. All managed blockers removed in the date range.", false
 }
 
+// syncPreview is the JSON shape of a sync plan preview.
+type syncPreview struct {
+	RangeStart  string              `json:"rangeStart"`
+	RangeEnd    string              `json:"rangeEnd"`
+	DaysChecked int                 `json:"daysChecked"`
+	Counts      map[string]int      `json:"counts"`
+	Changes     []syncPreviewChange `json:"changes"`
+	// Drift lists blockers edited by hand since the last sync, handled per DriftPolicy.
+	Drift       []syncPreviewDrift `json:"drift"`
+	DriftPolicy string             `json:"driftPolicy"`
+	// Stopped reports that a sync would stop without writing because of Drift.
+	Stopped bool `json:"stopped"`
+}
+
+type syncPreviewDrift struct {
+	Date     string `json:"date"`
+	Kind     string `json:"kind"`
+	EventID  string `json:"eventId"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+type syncPreviewChange struct {
+	Date      string `json:"date"`
+	EndDate   string `json:"endDate,omitempty"` // Last day of a merged blocker.
+	Action    string `json:"action"`
+	Summary   string `json:"summary"`
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+	AllDay    bool   `json:"allDay"`
+	EventID   string `json:"eventId,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+}
+
+func (h *ConfigHandler) runPreview(ctx context.Context, userID int64, cfg *db.Config) (*syncPreview, error) {
+	appCfg, err := h.parseAppConfig(cfg.ConfigYAML)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := h.buildRepo(ctx, userID, cfg.ID, appCfg)
+	if err != nil {
+		return nil, err
+	}
+	businessCal, err := holidays.CalendarFromConfig(appCfg, repo)
+	if err != nil {
+		return nil, err
+	}
+	rangeStart, rangeEnd := dateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
+	// Preview writes nothing: legacy blockers the sync would adopt are planned as if adopted.
+	legacy, err := scheduler.FindLegacyBlockers(repo, h.configs, cfg, appCfg, businessCal, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find legacy blockers: %w", err)
+	}
+	written, err := h.ledgers.List(cfg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the blocker ledger: %w", err)
+	}
+	plan, err := domain.PreviewSync(
+		withLegacyBlockers{repo, legacy.Owned},
+		businessCal,
+		rangeStart, rangeEnd,
+		appCfg.FreezeRules(),
+		appCfg.BlockerTemplates(cfg.Name),
+		written,
+		appCfg.DriftPolicy(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return newSyncPreview(plan, rangeStart, rangeEnd), nil
+}
+
+func newSyncPreview(plan *domain.SyncPlan, rangeStart, rangeEnd time.Time) *syncPreview {
+	preview := &syncPreview{
+		RangeStart:  rangeStart.Format("2006-01-02"),
+		RangeEnd:    rangeEnd.Format("2006-01-02"),
+		DaysChecked: plan.DaysChecked,
+		Counts: map[string]int{
+			domain.PlanActionAdd:    len(plan.Create),
+			domain.PlanActionUpdate: len(plan.Update),
+			domain.PlanActionRemove: len(plan.Delete),
+			domain.PlanActionKeep:   len(plan.Keep),
+		},
+		Changes:     []syncPreviewChange{},
+		Drift:       []syncPreviewDrift{},
+		DriftPolicy: string(plan.DriftPolicy),
+		Stopped:     plan.Stopped(),
+	}
+	for _, d := range plan.Drift {
+		preview.Drift = append(preview.Drift, newSyncPreviewDrift(d))
+	}
+	for _, c := range plan.Changes() {
+		var endDate string
+		if last := domain.NewDateKey(c.Blocker.LastDate()); last != c.Date {
+			endDate = string(last)
+		}
+		preview.Changes = append(preview.Changes, syncPreviewChange{
+			Date:      string(c.Date),
+			EndDate:   endDate,
+			Action:    c.Action,
+			Summary:   c.Blocker.Summary,
+			StartTime: c.Blocker.StartTime,
+			EndTime:   c.Blocker.EndTime,
+			AllDay:    c.Blocker.AllDay,
+			EventID:   c.EventID,
+			Rule:      c.Blocker.Rule,
+		})
+	}
+	return preview
+}
+
+func newSyncPreviewDrift(d domain.Drift) syncPreviewDrift {
+	return syncPreviewDrift{Date: string(d.Date), Kind: d.Kind, EventID: d.EventID, Expected: d.Expected, Actual: d.Actual}
+}
+
 type blockerItem struct {
 	Date    string `json:"date"`
+	EndDate string `json:"endDate,omitempty"`
 	Summary string `json:"summary"`
 	ID      string `json:"id"`
+	// NotYetAdopted marks a blocker from before extended properties that the next sync or
+	// wipe will adopt, see scheduler.AdoptLegacyBlockers.
+	NotYetAdopted bool `json:"notYetAdopted,omitempty"`
+}
+
+// withLegacyBlockers lists the legacy blockers a sync would adopt together with the
+// repository's blockers, so that previews plan as if they were already adopted.
+type withLegacyBlockers struct {
+	*googlecalendar.Repository
+	legacy []*domain.Blocker
+}
+
+func (r withLegacyBlockers) ListBlockersInRange(startDate, endDate time.Time) ([]*domain.Blocker, error) {
+	blockers, err := r.Repository.ListBlockersInRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return append(blockers, r.legacy...), nil
 }
 
 // configFormData holds the structured fields for the config create/edit form.
@@ -571,15 +863,47 @@ type configFormData struct {
 	Name          string
 	LookbackDays  int
 	LookaheadDays int
-	CountryCode   string
-	CalendarID    string
-	Summary       string
-	Description   string
-	StartTime     string
-	EndTime       string
-	AllDay        bool
-	SyncSchedule  string
-	// Rules is the todayIsFreezeDayIf slice — each map has exactly one key (anchor) → conditions.
+	CountryCodes  []string
+	// CountryCombination is "any" or "all".
+	CountryCombination string
+	ICSPath            string
+	// HolidayList is the company holiday list, one "YYYY-MM-DD Name" per line.
+	HolidayList string
+	// Overrides is the business-day override list, one "YYYY-MM-DD businessDay|nonBusinessDay Reason" per line.
+	Overrides string
+	// Holiday policy; HolidayInclude and HolidayExclude hold one holiday name per line.
+	CountObservances      bool
+	CountRegionalHolidays bool
+	HolidayInclude        string
+	HolidayExclude        string
+	// FreezeWindows is the freeze window list, one window per line, see parseFreezeWindowsText.
+	FreezeWindows string
+
+	CalendarID  string
+	Summary     string
+	Description string
+	StartTime   string
+	EndTime     string
+	AllDay      bool
+	// MergeConsecutiveDays writes one event per run of consecutive freeze days.
+	MergeConsecutiveDays bool
+	// Event settings; empty ColorID and Visibility use the calendar's own.
+	ColorID      string
+	Visibility   string
+	Transparency string
+	// Reminders is the reminder list, see parseRemindersText.
+	Reminders               string
+	GuestsCanSeeOtherGuests bool
+	// Attendees holds one invited email address per line.
+	Attendees   string
+	SendUpdates string
+	// OnManualEdit is the drift policy, see domain.DriftPolicy.
+	OnManualEdit string
+	SyncSchedule string
+	// Templates is the named blocker templates as YAML, see parseTemplatesYAML.
+	Templates string
+	// Rules is the todayIsFreezeDayIf slice — each map has one anchor key → conditions, plus
+	// an optional template key.
 	Rules []map[string][]string
 }
 
@@ -587,19 +911,32 @@ type configFormData struct {
 type formRule struct {
 	Anchor     string   `json:"anchor"`
 	Conditions []string `json:"conditions"`
+	Template   string   `json:"template,omitempty"`
+}
+
+// ruleGroup builds the todayIsFreezeDayIf group for a rule from the JS rules builder.
+func (fr formRule) ruleGroup() map[string][]string {
+	group := map[string][]string{fr.Anchor: fr.Conditions}
+	if t := strings.TrimSpace(fr.Template); t != "" {
+		group[domain.RuleTemplateKey] = []string{t}
+	}
+	return group
 }
 
 func defaultFormData() configFormData {
 	return configFormData{
 		LookbackDays:  20,
 		LookaheadDays: 60,
-		CountryCode:   countryCodeJPN,
+		CountryCodes:  []string{countryCodeJPN},
 		Summary:       "🚫 PRODUCTION FREEZE - No Deployments",
 		Description:   "Production operations restricted today.",
 		StartTime:     "08:00",
 		EndTime:       "20:00",
 		AllDay:        false,
 		SyncSchedule:  db.SyncScheduleNone,
+
+		Transparency:            appconfig.TransparencyBusy,
+		GuestsCanSeeOtherGuests: true,
 		Rules: []map[string][]string{
 			{ruleAnchorToday: {"isTheFirstBusinessDayOfTheMonth"}},
 			{ruleAnchorToday: {"isTheLastBusinessDayOfTheMonth"}},
@@ -611,13 +948,25 @@ func defaultFormData() configFormData {
 // configToFormData converts a stored config + its parsed appconfig into form data.
 func configToFormData(cfg *db.Config, appCfg *appconfig.Config) configFormData {
 	data := configFormData{
-		Name:          cfg.Name,
-		SyncSchedule:  cfg.SyncSchedule,
-		LookbackDays:  appCfg.Shared.LookbackDays,
-		LookaheadDays: appCfg.Shared.LookaheadDays,
-		CountryCode:   appCfg.ReadFrom.GoogleCalendar.CountryCode,
-		CalendarID:    appCfg.WriteTo.GoogleCalendar.ID,
-		Rules:         appCfg.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf,
+		Name:                  cfg.Name,
+		SyncSchedule:          cfg.SyncSchedule,
+		LookbackDays:          appCfg.Shared.LookbackDays,
+		LookaheadDays:         appCfg.Shared.LookaheadDays,
+		CountryCodes:          appCfg.ReadFrom.GoogleCalendar.Countries(),
+		CountryCombination:    appCfg.ReadFrom.GoogleCalendar.CountryCombination,
+		HolidayList:           holidayListToText(appCfg.ReadFrom.HolidayList),
+		Overrides:             overridesToText(appCfg.ReadFrom.Overrides),
+		FreezeWindows:         freezeWindowsToText(appCfg.ReadFrom.FreezeWindows),
+		CountObservances:      appCfg.ReadFrom.HolidayPolicy.CountObservances,
+		CountRegionalHolidays: appCfg.ReadFrom.HolidayPolicy.CountRegionalHolidays,
+		HolidayInclude:        strings.Join(appCfg.ReadFrom.HolidayPolicy.Include, "\n"),
+		HolidayExclude:        strings.Join(appCfg.ReadFrom.HolidayPolicy.Exclude, "\n"),
+		CalendarID:            appCfg.WriteTo.GoogleCalendar.ID,
+		Templates:             templatesToYAML(appCfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates),
+		Rules:                 appCfg.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf,
+	}
+	if appCfg.ReadFrom.ICSFile != nil {
+		data.ICSPath = appCfg.ReadFrom.ICSFile.Path
 	}
 	d := appCfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default
 	if d.Summary != nil {
@@ -635,6 +984,24 @@ func configToFormData(cfg *db.Config, appCfg *appconfig.Config) configFormData {
 	if d.AllDay != nil {
 		data.AllDay = *d.AllDay
 	}
+	data.MergeConsecutiveDays = appCfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.MergeConsecutiveDays
+	if d.ColorID != nil {
+		data.ColorID = *d.ColorID
+	}
+	if d.Visibility != nil {
+		data.Visibility = *d.Visibility
+	}
+	data.Transparency = appconfig.TransparencyBusy
+	if d.Transparency != nil {
+		data.Transparency = *d.Transparency
+	}
+	data.Reminders = remindersToText(d.Reminders)
+	data.GuestsCanSeeOtherGuests = d.GuestsCanSeeOtherGuests == nil || *d.GuestsCanSeeOtherGuests
+	if d.Attendees != nil {
+		data.Attendees = strings.Join(*d.Attendees, "\n")
+	}
+	data.SendUpdates = appCfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.SendUpdates
+	data.OnManualEdit = string(appCfg.DriftPolicy())
 	return data
 }
 
@@ -665,13 +1032,50 @@ func formToAppConfig(r *http.Request) (*appconfig.Config, error) {
 		if len(jr.Conditions) == 0 {
 			return nil, fmt.Errorf("each rule group must have at least one condition")
 		}
-		rules = append(rules, map[string][]string{jr.Anchor: jr.Conditions})
+		if jr.Anchor == domain.RuleExprKey {
+			for i, c := range jr.Conditions {
+				jr.Conditions[i] = strings.TrimSpace(c)
+			}
+		}
+		rules = append(rules, jr.ruleGroup())
 	}
 
 	summary := r.FormValue("event_summary")
 	description := r.FormValue("event_description")
 	calendarID := r.FormValue("write_calendar_id")
-	countryCode := r.FormValue("country_code")
+	countryCodes := r.Form["country_codes"]
+	countryCombination := r.FormValue("country_combination")
+	if countryCombination == appconfig.CountryCombinationAny || len(countryCodes) < 2 {
+		countryCombination = "" // "any" is the default; keep the YAML minimal
+	}
+	var icsFile *appconfig.ICSFileReadConfig
+	if p := strings.TrimSpace(r.FormValue("ics_path")); p != "" {
+		icsFile = &appconfig.ICSFileReadConfig{Path: p}
+	}
+	holidayList, err := parseHolidayListText(r.FormValue("holiday_list"))
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := parseOverridesText(r.FormValue("overrides"))
+	if err != nil {
+		return nil, err
+	}
+	freezeWindows, err := parseFreezeWindowsText(r.FormValue("freeze_windows"))
+	if err != nil {
+		return nil, err
+	}
+	templates, err := parseTemplatesYAML(r.FormValue("templates"))
+	if err != nil {
+		return nil, err
+	}
+	reminders, err := parseRemindersText(r.FormValue("reminders"))
+	if err != nil {
+		return nil, err
+	}
+	var attendees *[]string
+	if emails := splitAttendees(r.FormValue("attendees")); len(emails) > 0 {
+		attendees = &emails
+	}
 	allDay := r.FormValue("all_day") == "on"
 
 	var allDayPtr *bool
@@ -690,13 +1094,25 @@ func formToAppConfig(r *http.Request) (*appconfig.Config, error) {
 		},
 		ReadFrom: appconfig.ReadFromConfig{
 			GoogleCalendar: appconfig.GoogleCalendarReadConfig{
-				CountryCode:        countryCode,
+				CountryCodes:       countryCodes,
+				CountryCombination: countryCombination,
 				TodayIsFreezeDayIf: rules,
 			},
+			ICSFile:     icsFile,
+			HolidayList: holidayList,
+			Overrides:   overrides,
+			HolidayPolicy: appconfig.HolidayPolicyConfig{
+				CountObservances:      r.FormValue("count_observances") == "on",
+				CountRegionalHolidays: r.FormValue("count_regional_holidays") == "on",
+				Include:               splitLines(r.FormValue("holiday_include")),
+				Exclude:               splitLines(r.FormValue("holiday_exclude")),
+			},
+			FreezeWindows: freezeWindows,
 		},
 		WriteTo: appconfig.WriteToConfig{
 			GoogleCalendar: appconfig.GoogleCalendarWriteConfig{
-				ID: calendarID,
+				ID:           calendarID,
+				OnManualEdit: nonDefaultValue(r.FormValue("on_manual_edit"), string(domain.DriftPolicyOverwrite)),
 				IfTodayIsFreezeDay: appconfig.IfTodayIsFreezeDayConfig{
 					Default: appconfig.DefaultConfig{
 						Summary:     helpers.StringPtr(summary),
@@ -704,7 +1120,17 @@ func formToAppConfig(r *http.Request) (*appconfig.Config, error) {
 						StartTime:   startTimePtr,
 						EndTime:     endTimePtr,
 						AllDay:      allDayPtr,
+						// Only settings that differ from Google's defaults are written, to keep the YAML minimal.
+						ColorID:                 nonEmptyPtr(r.FormValue("color_id")),
+						Visibility:              nonDefaultPtr(r.FormValue("visibility"), appconfig.VisibilityDefault),
+						Transparency:            nonDefaultPtr(r.FormValue("transparency"), appconfig.TransparencyBusy),
+						Reminders:               reminders,
+						GuestsCanSeeOtherGuests: guestsCanSeeOtherGuestsPtr(r.FormValue("guests_can_see_other_guests") == "on"),
+						Attendees:               attendees,
 					},
+					Templates:            templates,
+					MergeConsecutiveDays: r.FormValue("merge_consecutive_days") == "on",
+					SendUpdates:          nonDefaultValue(r.FormValue("send_updates"), domain.SendUpdatesNone),
 				},
 			},
 		},
@@ -716,12 +1142,283 @@ func formToAppConfig(r *http.Request) (*appconfig.Config, error) {
 	return cfg, nil
 }
 
+// parseHolidayListText parses the company holiday textarea: one "YYYY-MM-DD Name" per line.
+// Blank lines are ignored; date validity is checked by Config.Validate.
+func parseHolidayListText(text string) ([]appconfig.HolidayListEntry, error) {
+	var entries []appconfig.HolidayListEntry
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		date, name, _ := strings.Cut(line, " ")
+		if date == "" {
+			return nil, fmt.Errorf("company holidays line %d: expected \"YYYY-MM-DD Name\"", i+1)
+		}
+		entries = append(entries, appconfig.HolidayListEntry{Date: date, Name: strings.TrimSpace(name)})
+	}
+	return entries, nil
+}
+
+// holidayListToText renders readFrom.holidayList for the company holiday textarea.
+func holidayListToText(entries []appconfig.HolidayListEntry) string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, strings.TrimSpace(e.Date+" "+e.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseOverridesText parses the override textarea: one "YYYY-MM-DD businessDay|nonBusinessDay Reason"
+// per line. Blank lines are ignored; dates and kinds are checked by Config.Validate.
+func parseOverridesText(text string) ([]appconfig.OverrideEntry, error) {
+	var entries []appconfig.OverrideEntry
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("overrides line %d: expected \"YYYY-MM-DD businessDay|nonBusinessDay Reason\"", i+1)
+		}
+		entries = append(entries, appconfig.OverrideEntry{
+			Date:   fields[0],
+			Is:     fields[1],
+			Reason: strings.Join(fields[2:], " "),
+		})
+	}
+	return entries, nil
+}
+
+// Line prefixes of the freeze window textarea.
+const (
+	freezeWindowYearlyPrefix   = "yearly"
+	freezeWindowQuarterPrefix  = "lastBusinessDaysOfQuarter"
+	freezeWindowFiscalPrefix   = "fiscalYearStartMonth="
+	freezeWindowTemplatePrefix = "template="
+)
+
+// parseFreezeWindowsText parses the freeze window textarea, one window per line:
+//
+//	2026-11-10..2026-11-11 Product launch
+//	yearly 12-24..01-03 Year-end
+//	lastBusinessDaysOfQuarter 5 [fiscalYearStartMonth=4] Quarter close
+//
+// Any kind may be followed by template=NAME before the name to pick a blocker template.
+// Dates and template names are checked by config validation.
+func parseFreezeWindowsText(text string) ([]appconfig.FreezeWindowEntry, error) {
+	var entries []appconfig.FreezeWindowEntry
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var e appconfig.FreezeWindowEntry
+		switch fields[0] {
+		case freezeWindowQuarterPrefix:
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("freeze windows line %d: expected \"%s N Name\"", i+1, freezeWindowQuarterPrefix)
+			}
+			n, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("freeze windows line %d: %q is not a number of business days", i+1, fields[1])
+			}
+			e.LastBusinessDaysOfQuarter = n
+			fields = fields[2:]
+			if len(fields) > 0 && strings.HasPrefix(fields[0], freezeWindowFiscalPrefix) {
+				month, err := strconv.Atoi(strings.TrimPrefix(fields[0], freezeWindowFiscalPrefix))
+				if err != nil {
+					return nil, fmt.Errorf("freeze windows line %d: %q is not a month number", i+1, fields[0])
+				}
+				e.FiscalYearStartMonth = month
+				fields = fields[1:]
+			}
+		default:
+			if fields[0] == freezeWindowYearlyPrefix {
+				e.Yearly = true
+				fields = fields[1:]
+			}
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("freeze windows line %d: expected \"FROM..TO Name\"", i+1)
+			}
+			from, to, ok := strings.Cut(fields[0], "..")
+			if !ok {
+				return nil, fmt.Errorf("freeze windows line %d: expected a range like 2026-12-24..2027-01-03, got %q", i+1, fields[0])
+			}
+			e.From, e.To = from, to
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && strings.HasPrefix(fields[0], freezeWindowTemplatePrefix) {
+			e.Template = strings.TrimPrefix(fields[0], freezeWindowTemplatePrefix)
+			fields = fields[1:]
+		}
+		e.Name = strings.Join(fields, " ")
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// freezeWindowsToText renders readFrom.freezeWindows for the freeze window textarea.
+func freezeWindowsToText(entries []appconfig.FreezeWindowEntry) string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		var spec string
+		switch {
+		case e.LastBusinessDaysOfQuarter != 0:
+			spec = freezeWindowQuarterPrefix + " " + strconv.Itoa(e.LastBusinessDaysOfQuarter)
+			if e.FiscalYearStartMonth != 0 {
+				spec += " " + freezeWindowFiscalPrefix + strconv.Itoa(e.FiscalYearStartMonth)
+			}
+		case e.Yearly:
+			spec = freezeWindowYearlyPrefix + " " + e.From + ".." + e.To
+		default:
+			spec = e.From + ".." + e.To
+		}
+		if e.Template != "" {
+			spec += " " + freezeWindowTemplatePrefix + e.Template
+		}
+		lines = append(lines, strings.TrimSpace(spec+" "+e.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nonEmptyPtr returns a pointer to s, or nil when s is empty.
+func nonEmptyPtr(s string) *string {
+	return nonDefaultPtr(s, "")
+}
+
+// nonDefaultPtr returns a pointer to s, or nil when s is empty or the default value.
+func nonDefaultPtr(s, def string) *string {
+	if s == "" || s == def {
+		return nil
+	}
+	return helpers.StringPtr(s)
+}
+
+// nonDefaultValue returns s, or "" when s is the default value.
+func nonDefaultValue(s, def string) string {
+	if s == def {
+		return ""
+	}
+	return s
+}
+
+// splitAttendees splits the attendees textarea on newlines and commas. Addresses are
+// checked by config validation.
+func splitAttendees(text string) []string {
+	return splitLines(strings.ReplaceAll(text, ",", "\n"))
+}
+
+// guestsCanSeeOtherGuestsPtr returns the guestsCanSeeOtherGuests setting, nil for Google's default (true).
+func guestsCanSeeOtherGuestsPtr(canSee bool) *bool {
+	if canSee {
+		return nil
+	}
+	return helpers.BoolPtr(false)
+}
+
+// remindersNone is the reminders field value that turns reminders off.
+const remindersNone = "none"
+
+// parseRemindersText parses the reminders field: comma-separated "method minutes" pairs,
+// e.g. "popup 10, email 1440". Empty keeps the calendar's default reminders and "none"
+// turns them off. Methods and limits are checked by config validation.
+func parseRemindersText(text string) (*[]appconfig.ReminderConfig, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	reminders := []appconfig.ReminderConfig{}
+	if text == remindersNone {
+		return &reminders, nil
+	}
+	for _, part := range strings.Split(text, ",") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid reminder %q: expected a method and minutes, e.g. \"popup 10\"", strings.TrimSpace(part))
+		}
+		minutes, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder %q: %q is not a number of minutes", strings.TrimSpace(part), fields[1])
+		}
+		reminders = append(reminders, appconfig.ReminderConfig{Method: fields[0], Minutes: minutes})
+	}
+	return &reminders, nil
+}
+
+// remindersToText renders reminders for the reminders field.
+func remindersToText(reminders *[]appconfig.ReminderConfig) string {
+	if reminders == nil {
+		return ""
+	}
+	if len(*reminders) == 0 {
+		return remindersNone
+	}
+	parts := make([]string, 0, len(*reminders))
+	for _, r := range *reminders {
+		parts = append(parts, fmt.Sprintf("%s %d", r.Method, r.Minutes))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseTemplatesYAML parses the named templates textarea: a YAML mapping of template name to
+// blocker fields, the same shape as ifTodayIsFreezeDay.templates. Unknown fields are errors
+// so that a typo does not silently fall back to the default.
+func parseTemplatesYAML(text string) (map[string]appconfig.DefaultConfig, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	dec := yaml.NewDecoder(strings.NewReader(text))
+	dec.KnownFields(true)
+	var templates map[string]appconfig.DefaultConfig
+	if err := dec.Decode(&templates); err != nil {
+		return nil, fmt.Errorf("invalid blocker templates: %w", err)
+	}
+	return templates, nil
+}
+
+// templatesToYAML renders ifTodayIsFreezeDay.templates for the named templates textarea.
+func templatesToYAML(templates map[string]appconfig.DefaultConfig) string {
+	if len(templates) == 0 {
+		return ""
+	}
+	b, err := yaml.Marshal(templates)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// splitLines returns the non-blank, trimmed lines of a textarea value.
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// overridesToText renders readFrom.overrides for the override textarea.
+func overridesToText(entries []appconfig.OverrideEntry) string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, strings.TrimSpace(e.Date+" "+e.Is+" "+e.Reason))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // rulesToJSON converts the todayIsFreezeDayIf slice to the JSON used by the JS rules builder.
 func rulesToJSON(rules []map[string][]string) string {
 	jsRules := make([]formRule, 0, len(rules))
 	for _, r := range rules {
+		template := domain.RuleGroupTemplate(r)
 		for anchor, conditions := range r {
-			jsRules = append(jsRules, formRule{Anchor: anchor, Conditions: conditions})
+			if anchor == domain.RuleTemplateKey {
+				continue
+			}
+			jsRules = append(jsRules, formRule{Anchor: anchor, Conditions: conditions, Template: template})
 		}
 	}
 	b, err := json.Marshal(jsRules)
@@ -739,52 +1436,157 @@ func formDataFromRequest(r *http.Request) configFormData {
 	var jsRules []formRule
 	if err := json.Unmarshal([]byte(r.FormValue("rules_json")), &jsRules); err == nil {
 		for _, jr := range jsRules {
-			rules = append(rules, map[string][]string{jr.Anchor: jr.Conditions})
+			rules = append(rules, jr.ruleGroup())
 		}
 	}
 	if len(rules) == 0 {
 		rules = defaultFormData().Rules
 	}
 	return configFormData{
-		LookbackDays:  lookback,
-		LookaheadDays: lookahead,
-		CountryCode:   r.FormValue("country_code"),
-		CalendarID:    r.FormValue("write_calendar_id"),
-		Summary:       r.FormValue("event_summary"),
-		Description:   r.FormValue("event_description"),
-		StartTime:     r.FormValue("start_time"),
-		EndTime:       r.FormValue("end_time"),
-		AllDay:        r.FormValue("all_day") == "on",
-		Rules:         rules,
+		LookbackDays:          lookback,
+		LookaheadDays:         lookahead,
+		CountryCodes:          r.Form["country_codes"],
+		CountryCombination:    r.FormValue("country_combination"),
+		ICSPath:               r.FormValue("ics_path"),
+		HolidayList:           r.FormValue("holiday_list"),
+		Overrides:             r.FormValue("overrides"),
+		FreezeWindows:         r.FormValue("freeze_windows"),
+		CountObservances:      r.FormValue("count_observances") == "on",
+		CountRegionalHolidays: r.FormValue("count_regional_holidays") == "on",
+		HolidayInclude:        r.FormValue("holiday_include"),
+		HolidayExclude:        r.FormValue("holiday_exclude"),
+		CalendarID:            r.FormValue("write_calendar_id"),
+		Summary:               r.FormValue("event_summary"),
+		Description:           r.FormValue("event_description"),
+		StartTime:             r.FormValue("start_time"),
+		EndTime:               r.FormValue("end_time"),
+		AllDay:                r.FormValue("all_day") == "on",
+		Templates:             r.FormValue("templates"),
+		MergeConsecutiveDays:  r.FormValue("merge_consecutive_days") == "on",
+
+		ColorID:                 r.FormValue("color_id"),
+		Visibility:              r.FormValue("visibility"),
+		Transparency:            r.FormValue("transparency"),
+		Reminders:               r.FormValue("reminders"),
+		GuestsCanSeeOtherGuests: r.FormValue("guests_can_see_other_guests") == "on",
+		Attendees:               r.FormValue("attendees"),
+		SendUpdates:             r.FormValue("send_updates"),
+		OnManualEdit:            r.FormValue("on_manual_edit"),
+		Rules:                   rules,
 	}
 }
 
-func (h *ConfigHandler) listBlockers(ctx context.Context, userID int64, cfg *db.Config) string {
+func (h *ConfigHandler) listHolidays(ctx context.Context, userID int64, cfg *db.Config) string {
 	appCfg, err := h.parseAppConfig(cfg.ConfigYAML)
 	if err != nil {
-		return actionResultHTML("List Blockers", err.Error(), true)
+		return actionResultHTML("Holidays", err.Error(), true)
 	}
-	repo, err := h.buildRepo(ctx, userID, appCfg)
+	repo, err := h.buildRepo(ctx, userID, cfg.ID, appCfg)
 	if err != nil {
-		return actionResultHTML("List Blockers", err.Error(), true)
+		return actionResultHTML("Holidays", err.Error(), true)
+	}
+	businessCal, err := holidays.CalendarFromConfig(appCfg, repo)
+	if err != nil {
+		return actionResultHTML("Holidays", err.Error(), true)
 	}
 	rangeStart, rangeEnd := dateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
-	blockers, err := repo.ListAllBlockersInRange(rangeStart, rangeEnd)
+	classified, err := businessCal.ClassifyHolidaysInRange(rangeStart, rangeEnd)
 	if err != nil {
-		return actionResultHTML("List Blockers", "failed to list blockers: "+err.Error(), true)
+		return actionResultHTML("Holidays", "failed to read holidays: "+err.Error(), true)
 	}
-	if len(blockers) == 0 {
-		return `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>No blocker events found in the date range.</em></p>`
+	return holidaysHTML(classified, rangeStart, rangeEnd)
+}
+
+// holidaysHTML renders the classified holidays as a table, counted ones marked green.
+func holidaysHTML(classified []domain.ClassifiedHoliday, rangeStart, rangeEnd time.Time) string {
+	counted := 0
+	for _, c := range classified {
+		if c.Counted {
+			counted++
+		}
 	}
+	header := fmt.Sprintf(`
+  <div style="font-size:0.88rem;color:var(--pico-muted-color);margin-bottom:0.5rem">
+    Holidays &nbsp;·&nbsp; <strong style="color:var(--pico-color)">%d counted, %d ignored</strong> &nbsp;·&nbsp; range %s → %s
+  </div>`, counted, len(classified)-counted, rangeStart.Format("2006-01-02"), rangeEnd.Format("2006-01-02"))
+
+	if len(classified) == 0 {
+		return `<div>` + header + `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>No holidays reported in the date range.</em></p></div>`
+	}
+
+	var rows strings.Builder
+	for _, c := range classified {
+		status := `<span style="padding:0.1rem 0.5rem;border-radius:999px;font-size:0.75rem;font-weight:600;background:#14532d;color:#86efac">counted</span>`
+		if !c.Counted {
+			status = `<span style="padding:0.1rem 0.5rem;border-radius:999px;font-size:0.75rem;font-weight:600;background:#374151;color:#d1d5db">ignored</span>`
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td>%s</td><td>%s</td><td>%s</td><td style="font-size:0.8rem;color:var(--pico-muted-color)">%s</td></tr>`,
+			html.EscapeString(c.Date.Format("2006-01-02")), html.EscapeString(c.Name), status,
+			html.EscapeString(c.Reason), html.EscapeString(c.Source))
+	}
+	return fmt.Sprintf(`
+<div>%s
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>Date</th><th>Holiday</th><th>Status</th><th>Reason</th><th>Source</th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+</div>`, header, rows.String())
+}
 
-	items := make([]blockerItem, 0, len(blockers))
-	for _, b := range blockers {
+// fetchBlockers reads the config's blocker events in its date range.
+func (h *ConfigHandler) fetchBlockers(ctx context.Context, userID int64, cfg *db.Config) (items []blockerItem, rangeStart, rangeEnd time.Time, err error) {
+	appCfg, err := h.parseAppConfig(cfg.ConfigYAML)
+	if err != nil {
+		return nil, rangeStart, rangeEnd, err
+	}
+	repo, err := h.buildRepo(ctx, userID, cfg.ID, appCfg)
+	if err != nil {
+		return nil, rangeStart, rangeEnd, err
+	}
+	rangeStart, rangeEnd = dateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
+	blockers, err := repo.ListBlockersInRange(rangeStart, rangeEnd)
+	if err != nil {
+		return nil, rangeStart, rangeEnd, fmt.Errorf("failed to list blockers: %w", err)
+	}
+	var legacy []*domain.Blocker
+	if cfg.LegacyBlockersAdoptedAt == nil {
+		businessCal, err := holidays.CalendarFromConfig(appCfg, repo)
+		if err != nil {
+			return nil, rangeStart, rangeEnd, err
+		}
+		found, err := scheduler.FindLegacyBlockers(repo, h.configs, cfg, appCfg, businessCal, rangeStart, rangeEnd)
+		if err != nil {
+			return nil, rangeStart, rangeEnd, fmt.Errorf("failed to find legacy blockers: %w", err)
+		}
+		legacy = found.Owned
+	}
+	items = make([]blockerItem, 0, len(blockers)+len(legacy))
+	for i, b := range append(blockers, legacy...) {
+		var endDate string
+		if !b.EndDate.IsZero() {
+			endDate = string(domain.NewDateKey(b.EndDate))
+		}
 		items = append(items, blockerItem{
-			Date:    b.Start.Format("2006-01-02"),
-			Summary: b.Summary,
-			ID:      b.ID,
+			Date:          string(b.Key()),
+			EndDate:       endDate,
+			Summary:       b.Summary,
+			ID:            b.EventID,
+			NotYetAdopted: i >= len(blockers),
 		})
 	}
+	slices.SortStableFunc(items, func(a, b blockerItem) int { return strings.Compare(a.Date, b.Date) })
+	return items, rangeStart, rangeEnd, nil
+}
+
+func (h *ConfigHandler) listBlockers(ctx context.Context, userID int64, cfg *db.Config) string {
+	items, rangeStart, rangeEnd, err := h.fetchBlockers(ctx, userID, cfg)
+	if err != nil {
+		return actionResultHTML("List Blockers", err.Error(), true)
+	}
+	if len(items) == 0 {
+		return `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>No blocker events found in the date range.</em></p>`
+	}
+
 	jsonBytes, err := json.MarshalIndent(items, "", "  ")
 	if err != nil {
 		return actionResultHTML("List Blockers", "failed to format result: "+err.Error(), true)
@@ -803,6 +1605,135 @@ func (h *ConfigHandler) listBlockers(ctx context.Context, userID int64, cfg *db.
 </div>`, len(items), html.EscapeString(rangeLabel), html.EscapeString(string(jsonBytes)))
 }
 
+func (h *ConfigHandler) listDrift(ctx context.Context, userID int64, cfg *db.Config, canSync bool) string {
+	appCfg, err := h.parseAppConfig(cfg.ConfigYAML)
+	if err != nil {
+		return actionResultHTML("Manual Edits", err.Error(), true)
+	}
+	repo, err := h.buildRepo(ctx, userID, cfg.ID, appCfg)
+	if err != nil {
+		return actionResultHTML("Manual Edits", err.Error(), true)
+	}
+	rangeStart, rangeEnd := dateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
+	written, err := h.ledgers.List(cfg.ID)
+	if err != nil {
+		return actionResultHTML("Manual Edits", "failed to read the blocker ledger: "+err.Error(), true)
+	}
+	existing, err := repo.ListBlockersInRange(rangeStart, rangeEnd)
+	if err != nil {
+		return actionResultHTML("Manual Edits", "failed to list blockers: "+err.Error(), true)
+	}
+	drift := domain.DetectDrift(written, existing, rangeStart, rangeEnd)
+	return driftHTML(h.basePath, cfg.ID, drift, appCfg.DriftPolicy(), canSync && cfg.SyncSchedule == db.SyncScheduleNone)
+}
+
+// driftPolicyLabel describes each drift policy on the Manual Edits panel.
+var driftPolicyLabel = map[domain.DriftPolicy]string{
+	domain.DriftPolicyOverwrite: "Sync overwrites manual edits.",
+	domain.DriftPolicyRespect:   "Sync keeps manual edits and does not recreate deleted blockers.",
+	domain.DriftPolicyStop:      "Sync stops without writing while there are manual edits.",
+}
+
+// driftKindLabel names each kind of manual change.
+var driftKindLabel = map[string]string{
+	domain.DriftSummary: "renamed",
+	domain.DriftTime:    "moved",
+	domain.DriftDeleted: "deleted",
+}
+
+// driftHTML renders the Manual Edits panel. With canResolve, it offers a one-off sync that
+// overwrites or keeps the edits whatever the config's policy.
+func driftHTML(basePath string, configID int64, drift []domain.Drift, policy domain.DriftPolicy, canResolve bool) string {
+	header := fmt.Sprintf(`
+  <div style="font-size:0.88rem;color:var(--pico-muted-color);margin-bottom:0.5rem">
+    Manual Edits &nbsp;·&nbsp; <strong style="color:var(--pico-color)">%d found</strong> &nbsp;·&nbsp; %s
+  </div>`, len(drift), html.EscapeString(driftPolicyLabel[policy]))
+	if len(drift) == 0 {
+		return `<div>` + header + `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>No blocker events were changed by hand since the last sync.</em></p></div>`
+	}
+
+	var rows strings.Builder
+	for _, d := range drift {
+		actual := d.Actual
+		if d.Kind == domain.DriftDeleted {
+			actual = "—"
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+			html.EscapeString(string(d.Date)), html.EscapeString(driftKindLabel[d.Kind]),
+			html.EscapeString(d.Expected), html.EscapeString(actual))
+	}
+	actions := ""
+	if canResolve {
+		syncURL := fmt.Sprintf("%s/configs/%d/sync", basePath, configID)
+		actions = fmt.Sprintf(`
+  <div style="display:flex;gap:0.5rem;flex-wrap:wrap">
+    <button class="outline" style="margin:0" hx-post="%s" hx-vals='{"on_manual_edit":"overwrite"}' hx-target="#action-result" hx-confirm="Put the edited blockers back as the rules say?">Overwrite edits</button>
+    <button class="outline secondary" style="margin:0" hx-post="%s" hx-vals='{"on_manual_edit":"respect"}' hx-target="#action-result">Keep edits</button>
+  </div>`, html.EscapeString(syncURL), html.EscapeString(syncURL))
+	}
+	return fmt.Sprintf(`
+<div>%s
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>Date</th><th>Change</th><th>Written by sync</th><th>Now</th></tr></thead>
+    <tbody>%s</tbody>
+  </table>%s
+</div>`, header, rows.String(), actions)
+}
+
+// previewActionStyle maps a plan action to its badge label and colours.
+var previewActionStyle = map[string]struct{ label, style string }{
+	domain.PlanActionAdd:    {"+ add", "background:#1a4731;color:#4ade80;border:1px solid #166534"},
+	domain.PlanActionUpdate: {"~ update", "background:#1e3a5f;color:#60a5fa;border:1px solid #1d4ed8"},
+	domain.PlanActionRemove: {"− remove", "background:#4a1122;color:#f87171;border:1px solid #7f1d1d"},
+	domain.PlanActionKeep:   {"= keep", "background:#1f2937;color:#9ca3af;border:1px solid #374151"},
+}
+
+func previewHTML(p *syncPreview) string {
+	rangeLabel := fmt.Sprintf("%s → %s", p.RangeStart, p.RangeEnd)
+	header := fmt.Sprintf(`
+  <div style="font-size:0.88rem;color:var(--pico-muted-color);margin-bottom:0.5rem">
+    Sync Preview (nothing written) &nbsp;·&nbsp; <strong style="color:var(--pico-color)">%d add, %d update, %d remove, %d keep</strong> &nbsp;·&nbsp; %d days checked &nbsp;·&nbsp; range %s
+  </div>`,
+		p.Counts[domain.PlanActionAdd], p.Counts[domain.PlanActionUpdate],
+		p.Counts[domain.PlanActionRemove], p.Counts[domain.PlanActionKeep],
+		p.DaysChecked, html.EscapeString(rangeLabel))
+	if len(p.Drift) > 0 {
+		note := fmt.Sprintf("%d blocker(s) edited by hand since the last sync; the plan follows the <code>%s</code> policy.", len(p.Drift), html.EscapeString(p.DriftPolicy))
+		if p.Stopped {
+			note = fmt.Sprintf("%d blocker(s) edited by hand since the last sync. Sync will stop without writing until the edits are overwritten or kept under Manual Edits.", len(p.Drift))
+		}
+		header += `
+  <div style="font-size:0.85rem;color:#fbbf24;margin-bottom:0.5rem">✋ ` + note + `</div>`
+	}
+
+	if len(p.Changes) == 0 {
+		return `<div>` + header + `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>No freeze days and no existing blockers in the date range.</em></p></div>`
+	}
+
+	var rows strings.Builder
+	for _, c := range p.Changes {
+		st := previewActionStyle[c.Action]
+		timing := "all day"
+		if !c.AllDay {
+			timing = c.StartTime + "–" + c.EndTime
+		}
+		dates := c.Date
+		if c.EndDate != "" {
+			dates += " → " + c.EndDate
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td><span style="padding:0.1rem 0.5rem;border-radius:999px;font-size:0.75rem;font-weight:600;white-space:nowrap;%s">%s</span></td><td>%s</td><td style="white-space:nowrap">%s</td><td style="font-size:0.78rem;color:var(--pico-muted-color)">%s</td></tr>`,
+			html.EscapeString(dates), st.style, html.EscapeString(st.label),
+			html.EscapeString(c.Summary), html.EscapeString(timing), html.EscapeString(c.Rule))
+	}
+	return fmt.Sprintf(`
+<div>%s
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>Date</th><th>Action</th><th>Summary</th><th>Time</th><th>Rule</th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+</div>`, header, rows.String())
+}
+
 // --- schedule helpers ---
 
 // parseSyncSchedule normalises the form value. Any unrecognised value (including
@@ -880,6 +1811,26 @@ func actionResultHTML(action, msg string, isErr bool) string {
 </div>`, bg, border, color, html.EscapeString(action), html.EscapeString(msg))
 }
 
+// syncOutcomeHTML renders a sync result. A partial sync is shown as a warning listing each
+// failed change.
+func syncOutcomeHTML(o *domain.SyncOutcome) string {
+	if o.Status != domain.SyncStatusPartial || o.Result == nil || len(o.Result.Failed) == 0 {
+		return actionResultHTML("Sync", o.Message, o.IsErr())
+	}
+	var failures strings.Builder
+	for _, f := range o.Result.Failed {
+		fmt.Fprintf(&failures, `<li>%s</li>`, html.EscapeString(f.Error()))
+	}
+	held := ""
+	if o.Result.HeldBack > 0 {
+		held = fmt.Sprintf(" %d removal(s) were held back so no freeze day is left without a blocker.", o.Result.HeldBack)
+	}
+	return fmt.Sprintf(`<div style="background:#3b2f0b;border:1px solid #854d0e;color:#fbbf24;padding:0.75rem 1rem;border-radius:0.5rem;margin-top:0.75rem;font-size:0.9rem">
+  <strong>Sync partially applied:</strong> created %d, updated %d, deleted %d blocker event(s); %d change(s) failed.%s The next sync picks up the rest.
+  <ul style="margin:0.5rem 0 0;font-size:0.85rem">%s</ul>
+</div>`, o.Result.Created, o.Result.Updated, o.Result.Deleted, len(o.Result.Failed), html.EscapeString(held), failures.String())
+}
+
 // calendarPickerHTML renders an optional dropdown that fills the calendar ID field when selected.
 func calendarPickerHTML(cals []*googlecalendar.CalendarItem, selectedID string) string {
 	if len(cals) == 0 {
@@ -1119,13 +2070,22 @@ func configDetailHTML(basePath string, cfg *db.Config, currentUserID int64, role
     %s
   </div>
   <div style="font-size:0.85rem;color:var(--pico-muted-color);margin-bottom:1rem">
-    schema: %s &nbsp;·&nbsp; Status: <span id="status-badge">%s</span>%s
+    schema: %s &nbsp;·&nbsp; revision %d &nbsp;·&nbsp; Status: <span id="status-badge">%s</span>%s
   </div>
 
   %s
 
   <div class="action-bar">
     %s
+    <button
+      hx-get="`+basePath+`/configs/%d/preview"
+      hx-target="#blockers-panel"
+      hx-swap="innerHTML"
+      hx-on::before-request="document.getElementById('blockers-panel').innerHTML='<p class=ack>⏳ Computing preview&#8230;</p>'"
+      class="outline"
+      title="Show which blockers a sync would add, update, remove or keep, without writing anything">
+      👁 Preview
+    </button>
     <button
       hx-get="`+basePath+`/configs/%d/blockers"
       hx-target="#blockers-panel"
@@ -1135,6 +2095,51 @@ func configDetailHTML(basePath string, cfg *db.Config, currentUserID int64, role
       title="List all currently managed blocker events in the date range">
       📋 List Blockers
     </button>
+    <button
+      hx-get="`+basePath+`/configs/%d/drift"
+      hx-target="#blockers-panel"
+      hx-swap="innerHTML"
+      hx-on::before-request="document.getElementById('blockers-panel').innerHTML='<p class=ack>⏳ Checking for manual edits&#8230;</p>'"
+      class="outline"
+      title="Show blocker events moved, renamed or deleted by hand since the last sync">
+      ✋ Manual Edits
+    </button>
+    <button
+      hx-get="`+basePath+`/configs/%d/holidays"
+      hx-target="#blockers-panel"
+      hx-swap="innerHTML"
+      hx-on::before-request="document.getElementById('blockers-panel').innerHTML='<p class=ack>⏳ Loading holidays&#8230;</p>'"
+      class="outline"
+      title="Show which holidays in the date range are counted as non-business days and which are ignored, and why">
+      🎌 Holidays
+    </button>
+    <button
+      hx-get="`+basePath+`/configs/%d/runs"
+      hx-target="#blockers-panel"
+      hx-swap="innerHTML"
+      hx-on::before-request="document.getElementById('blockers-panel').innerHTML='<p class=ack>⏳ Loading sync history&#8230;</p>'"
+      class="outline"
+      title="Show past manual and automatic syncs and what each changed">
+      🕘 History
+    </button>
+    <button
+      hx-get="`+basePath+`/configs/%d/audit"
+      hx-target="#blockers-panel"
+      hx-swap="innerHTML"
+      hx-on::before-request="document.getElementById('blockers-panel').innerHTML='<p class=ack>⏳ Loading audit log&#8230;</p>'"
+      class="outline"
+      title="Show who changed, synced or wiped this config and when, with the YAML before and after each edit">
+      🧾 Audit Log
+    </button>
+    <button
+      hx-get="`+basePath+`/configs/%d/revisions"
+      hx-target="#blockers-panel"
+      hx-swap="innerHTML"
+      hx-on::before-request="document.getElementById('blockers-panel').innerHTML='<p class=ack>⏳ Loading revisions&#8230;</p>'"
+      class="outline"
+      title="Show every saved version of this config, compare two of them, or roll back to one">
+      📚 Revisions
+    </button>
   </div>
 
   <div id="action-result"></div>
@@ -1151,9 +2156,9 @@ func configDetailHTML(basePath string, cfg *db.Config, currentUserID int64, role
 		escapedName, logoutForm(basePath),
 		escapedName,
 		escapedName, editBtnHTML,
-		escapedSchema, badge, autoSyncTrigger,
-		autoSyncInfoHTML(cfg),
-		syncActionsHTML, cfg.ID,
+		escapedSchema, cfg.Revision, badge, autoSyncTrigger,
+		autoSyncInfoHTML(cfg)+legacyBlockersNoticeHTML(basePath, cfg),
+		syncActionsHTML, cfg.ID, cfg.ID, cfg.ID, cfg.ID, cfg.ID, cfg.ID, cfg.ID,
 		configCardsHTML,
 		autoSyncModalHTML(basePath, cfg, canEdit),
 	)
@@ -1184,16 +2189,48 @@ func conditionLabel(c string) string {
 	}
 }
 
+// anchorLabel returns a human-readable label for a rule anchor, e.g. "+1bd (next business day)".
+func anchorLabel(anchor string) string {
+	a, err := domain.ParseAnchor(anchor)
+	if err != nil || !strings.ContainsAny(anchor[:1], "+-") {
+		return anchor
+	}
+	return anchor + " (" + a.String() + ")"
+}
+
 // countryLabel returns a human-readable label for a country code.
 func countryLabel(code string) string {
-	switch code {
-	case countryCodeJPN:
-		return "Japan (jpn)"
-	case countryCodeVNM:
-		return "Vietnam (vnm)"
-	default:
-		return code
+	if c, ok := consts.LookupCountry(code); ok {
+		return c.Label()
+	}
+	return code
+}
+
+// holidayPolicyHTML summarizes which kinds of holidays count and the name lists.
+func holidayPolicyHTML(p appconfig.HolidayPolicyConfig) string {
+	kinds := []string{"public holidays"}
+	if p.CountRegionalHolidays {
+		kinds = append(kinds, "regional holidays")
+	}
+	if p.CountObservances {
+		kinds = append(kinds, "observances")
+	}
+	out := "Counts " + html.EscapeString(strings.Join(kinds, ", "))
+	if len(p.Include) > 0 {
+		out += `<div>Always counted: ` + html.EscapeString(strings.Join(p.Include, ", ")) + `</div>`
+	}
+	if len(p.Exclude) > 0 {
+		out += `<div>Never counted: ` + html.EscapeString(strings.Join(p.Exclude, ", ")) + `</div>`
+	}
+	return out
+}
+
+// countryCombinationLabel describes how the holidays of several countries combine.
+func countryCombinationLabel(mode string) string {
+	if mode == appconfig.CountryCombinationAll {
+		return "Holiday only when it is a holiday in all countries"
 	}
+	return "Holiday when it is a holiday in any country"
 }
 
 // configDetailCardsHTML renders human-readable detail cards from the parsed config.
@@ -1223,11 +2260,62 @@ func configDetailCardsHTML(appCfg *appconfig.Config, calendarName string, rawYAM
 </div>`, appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
 
 	// Holiday source card
+	var extraSourcesSB strings.Builder
+	if f := appCfg.ReadFrom.ICSFile; f != nil {
+		fmt.Fprintf(&extraSourcesSB, `
+  <div class="detail-field" style="margin-top:0.5rem"><label>iCalendar File</label><div class="val" style="word-break:break-all">%s</div></div>`,
+			html.EscapeString(f.Path))
+	}
+	if len(appCfg.ReadFrom.HolidayList) > 0 {
+		var items strings.Builder
+		for _, e := range appCfg.ReadFrom.HolidayList {
+			fmt.Fprintf(&items, `<li>%s %s</li>`, html.EscapeString(e.Date), html.EscapeString(e.Name))
+		}
+		fmt.Fprintf(&extraSourcesSB, `
+  <div class="detail-field" style="margin-top:0.5rem"><label>Company Holidays</label><ul class="val" style="font-size:0.85rem;margin:0">%s</ul></div>`,
+			items.String())
+	}
+	countries := appCfg.ReadFrom.GoogleCalendar.Countries()
+	countryLabels := make([]string, 0, len(countries))
+	for _, cc := range countries {
+		countryLabels = append(countryLabels, countryLabel(cc))
+	}
+	countriesHTML := "None"
+	if len(countryLabels) > 0 {
+		countriesHTML = html.EscapeString(strings.Join(countryLabels, ", "))
+	}
+	if len(countries) > 1 {
+		countriesHTML += `<div style="font-size:0.8rem;color:var(--pico-muted-color);margin-top:0.2rem">` +
+			html.EscapeString(countryCombinationLabel(appCfg.ReadFrom.GoogleCalendar.CountryCombination)) + `</div>`
+	}
 	holidayCard := fmt.Sprintf(`
 <div class="detail-card">
-  <h4>Holiday Source <span title="The public holiday calendar to read from, used to identify non-business days." style="cursor:help;font-weight:normal;font-size:0.8rem;opacity:0.5">(?)</span></h4>
-  <div class="detail-field"><label>Country</label><div class="val">%s</div></div>
-</div>`, html.EscapeString(countryLabel(appCfg.ReadFrom.GoogleCalendar.CountryCode)))
+  <h4>Holiday Sources <span title="Where non-business days come from. A day is a holiday if any source lists it." style="cursor:help;font-weight:normal;font-size:0.8rem;opacity:0.5">(?)</span></h4>
+  <div class="detail-field"><label>Countries</label><div class="val">%s</div></div>%s
+  <div class="detail-field" style="margin-top:0.5rem"><label>Holiday Policy</label><div class="val" style="font-size:0.85rem">%s</div></div>
+</div>`, countriesHTML, extraSourcesSB.String(), holidayPolicyHTML(appCfg.ReadFrom.HolidayPolicy))
+
+	// Overrides card
+	overridesCard := ""
+	if len(appCfg.ReadFrom.Overrides) > 0 {
+		var items strings.Builder
+		for _, o := range appCfg.ReadFrom.Overrides {
+			label := "business day"
+			if o.Is == appconfig.OverrideNonBusinessDay {
+				label = "non-business day"
+			}
+			reason := ""
+			if o.Reason != "" {
+				reason = ` <span style="color:var(--pico-muted-color)">— ` + html.EscapeString(o.Reason) + `</span>`
+			}
+			fmt.Fprintf(&items, `<li>%s is a <strong>%s</strong>%s</li>`, html.EscapeString(o.Date), label, reason)
+		}
+		overridesCard = fmt.Sprintf(`
+<div class="detail-card">
+  <h4>Business-Day Overrides <span title="Dates forced to be business or non-business days, regardless of weekends and holidays." style="cursor:help;font-weight:normal;font-size:0.8rem;opacity:0.5">(?)</span></h4>
+  <ul style="font-size:0.85rem;margin:0">%s</ul>
+</div>`, items.String())
+	}
 
 	// Freeze rules card
 	var rulesSB strings.Builder
@@ -1235,14 +2323,29 @@ func configDetailCardsHTML(appCfg *appconfig.Config, calendarName string, rawYAM
 		if i > 0 {
 			rulesSB.WriteString(`<div style="font-size:0.78rem;font-weight:700;color:#60a5fa;text-align:center;margin:0.3rem 0;letter-spacing:0.05em">OR</div>`)
 		}
+		groupNo := fmt.Sprintf(`<span style="color:var(--pico-muted-color);font-size:0.75rem" title="Blockers produced by this group are tagged %s">#%d</span> `,
+			html.EscapeString(domain.DayRuleLabel(i)), i+1)
+		templateHTML := templateTagHTML(domain.RuleGroupTemplate(rule))
 		for anchor, conditions := range rule {
+			if anchor == domain.RuleTemplateKey {
+				continue
+			}
 			var condParts []string
 			for _, c := range conditions {
+				if anchor == domain.RuleExprKey {
+					condParts = append(condParts, `<code>`+html.EscapeString(c)+`</code>`)
+					continue
+				}
 				condParts = append(condParts, html.EscapeString(conditionLabel(c)))
 			}
+			if anchor == domain.RuleExprKey {
+				fmt.Fprintf(&rulesSB, `<div class="rule-group">%s<strong>expression</strong> %s%s</div>`, groupNo,
+					strings.Join(condParts, ` <span style="font-size:0.75rem;font-weight:700;color:#a78bfa;margin:0 0.2rem">AND</span> `), templateHTML)
+				continue
+			}
 			condHTML := strings.Join(condParts, ` <span style="font-size:0.75rem;font-weight:700;color:#a78bfa;margin:0 0.2rem">AND</span> `)
-			fmt.Fprintf(&rulesSB, `<div class="rule-group"><strong>%s</strong> <span style="color:var(--pico-muted-color);font-size:0.82rem">is:</span> %s</div>`,
-				html.EscapeString(anchor), condHTML)
+			fmt.Fprintf(&rulesSB, `<div class="rule-group">%s<strong>%s</strong> <span style="color:var(--pico-muted-color);font-size:0.82rem">is:</span> %s%s</div>`,
+				groupNo, html.EscapeString(anchorLabel(anchor)), condHTML, templateHTML)
 		}
 	}
 	freezeCard := fmt.Sprintf(`
@@ -1252,6 +2355,21 @@ func configDetailCardsHTML(appCfg *appconfig.Config, calendarName string, rawYAM
   %s
 </div>`, rulesSB.String())
 
+	// Freeze windows card
+	windowsCard := ""
+	if windows := appCfg.FreezeRules().Windows; len(windows) > 0 {
+		var items strings.Builder
+		for _, w := range windows {
+			fmt.Fprintf(&items, `<li><strong>%s</strong> <span style="color:var(--pico-muted-color)">— %s</span>%s</li>`,
+				html.EscapeString(w.Name), html.EscapeString(w.String()), templateTagHTML(w.Template))
+		}
+		windowsCard = fmt.Sprintf(`
+<div class="detail-card">
+  <h4>Freeze Windows <span title="Named spans of freeze days, in addition to the freeze rules. A day in a window is tagged with the window's name." style="cursor:help;font-weight:normal;font-size:0.8rem;opacity:0.5">(?)</span></h4>
+  <ul style="font-size:0.85rem;margin:0">%s</ul>
+</div>`, items.String())
+	}
+
 	// Calendar card — show friendly name when available.
 	calID := appCfg.WriteTo.GoogleCalendar.ID
 	var calDisplay string
@@ -1296,6 +2414,13 @@ func configDetailCardsHTML(appCfg *appconfig.Config, calendarName string, rawYAM
     <div class="detail-field"><label>End</label><div class="val">%s</div></div>
   </div>`, html.EscapeString(startTime), html.EscapeString(endTime))
 	}
+	timingHTML += fmt.Sprintf(`<div class="detail-field" style="margin-top:0.5rem"><label>Event settings</label><div class="val" style="font-size:0.85rem">%s</div></div>`,
+		html.EscapeString(eventSettingsLabel(appCfg.ResolvedTemplate(""))))
+	if appCfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.MergeConsecutiveDays {
+		timingHTML += `<div class="detail-field" style="margin-top:0.5rem"><label>Consecutive days</label><div class="val">Merged into one event per run</div></div>`
+	}
+	timingHTML += fmt.Sprintf(`<div class="detail-field" style="margin-top:0.5rem"><label>Manual edits</label><div class="val" style="font-size:0.85rem">%s</div></div>`,
+		html.EscapeString(driftPolicyLabel[appCfg.DriftPolicy()]))
 	eventCard := fmt.Sprintf(`
 <div class="detail-card">
   <h4>Blocker Event <span title="The calendar event created on each freeze day to signal no deployments allowed." style="cursor:help;font-weight:normal;font-size:0.8rem;opacity:0.5">(?)</span></h4>
@@ -1307,7 +2432,132 @@ func configDetailCardsHTML(appCfg *appconfig.Config, calendarName string, rawYAM
 		html.EscapeString(evDescription),
 		timingHTML)
 
-	return dateRangeCard + holidayCard + freezeCard + calendarCard + eventCard
+	// Named blocker templates card
+	templatesCard := ""
+	if named := appCfg.BlockerTemplates("").Named; len(named) > 0 {
+		var rows strings.Builder
+		for _, name := range slices.Sorted(maps.Keys(named)) {
+			t := named[name]
+			timing := "All-day"
+			if !t.AllDay {
+				timing = t.StartTime + "–" + t.EndTime
+			}
+			fmt.Fprintf(&rows, `<tr><td><code>%s</code></td><td>%s</td><td>%s</td><td style="color:var(--pico-muted-color)">%s</td></tr>`,
+				html.EscapeString(name), html.EscapeString(t.Summary), html.EscapeString(timing),
+				html.EscapeString(eventSettingsLabel(appCfg.ResolvedTemplate(name))))
+		}
+		templatesCard = fmt.Sprintf(`
+<div class="detail-card">
+  <h4>Blocker Templates <span title="Named blocker events picked by rule groups and freeze windows. Unset fields come from the blocker event above." style="cursor:help;font-weight:normal;font-size:0.8rem;opacity:0.5">(?)</span></h4>
+  <table style="font-size:0.85rem;margin:0"><thead><tr><th>Name</th><th>Summary</th><th>Timing</th><th></th></tr></thead><tbody>%s</tbody></table>
+</div>`, rows.String())
+	}
+
+	return dateRangeCard + holidayCard + overridesCard + freezeCard + windowsCard + calendarCard + eventCard + templatesCard
+}
+
+// eventSettingsLabel summarises a blocker event's colour, visibility, free/busy, reminders,
+// attendees and guest settings, e.g. "Tomato · private · free · reminders: popup 10".
+func eventSettingsLabel(d appconfig.DefaultConfig) string {
+	colour := "calendar colour"
+	if d.ColorID != nil {
+		colour = eventColorName(*d.ColorID)
+	}
+	visibility := appconfig.VisibilityDefault + " visibility"
+	if d.Visibility != nil && *d.Visibility != appconfig.VisibilityDefault {
+		visibility = *d.Visibility
+	}
+	showAs := appconfig.TransparencyBusy
+	if d.Transparency != nil {
+		showAs = *d.Transparency
+	}
+	reminders := "default reminders"
+	if d.Reminders != nil {
+		reminders = "reminders: " + remindersToText(d.Reminders)
+	}
+	parts := []string{colour, visibility, showAs, reminders}
+	if d.Attendees != nil && len(*d.Attendees) > 0 {
+		parts = append(parts, "invites "+strings.Join(*d.Attendees, ", "))
+	}
+	if d.GuestsCanSeeOtherGuests != nil && !*d.GuestsCanSeeOtherGuests {
+		parts = append(parts, "guest list hidden")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// templateTagHTML labels a rule group or freeze window with the blocker template it picks.
+func templateTagHTML(name string) string {
+	if name == "" {
+		return ""
+	}
+	return ` <span style="color:var(--pico-muted-color);font-size:0.8rem" title="Blocker template">→ <code>` + html.EscapeString(name) + `</code></span>`
+}
+
+// checkedAttr returns the checked attribute for a checkbox input.
+func checkedAttr(checked bool) string {
+	if checked {
+		return " checked"
+	}
+	return ""
+}
+
+// selectOption is one option of a select input.
+type selectOption struct {
+	value, label string
+}
+
+// eventColors are the Google Calendar event colours, by colorId.
+var eventColors = []selectOption{
+	{"", "Calendar colour"},
+	{"1", "Lavender"}, {"2", "Sage"}, {"3", "Grape"}, {"4", "Flamingo"}, {"5", "Banana"}, {"6", "Tangerine"},
+	{"7", "Peacock"}, {"8", "Graphite"}, {"9", "Blueberry"}, {"10", "Basil"}, {"11", "Tomato"},
+}
+
+var visibilityOptions = []selectOption{
+	{appconfig.VisibilityDefault, "Calendar default"},
+	{appconfig.VisibilityPublic, "Public"},
+	{appconfig.VisibilityPrivate, "Private"},
+}
+
+var sendUpdatesOptions = []selectOption{
+	{domain.SendUpdatesNone, "Nobody (add silently)"},
+	{domain.SendUpdatesExternalOnly, "Guests outside the organisation"},
+	{domain.SendUpdatesAll, "All guests"},
+}
+
+var manualEditOptions = []selectOption{
+	{string(domain.DriftPolicyOverwrite), "Overwrite the edit"},
+	{string(domain.DriftPolicyRespect), "Keep the edit"},
+	{string(domain.DriftPolicyStop), "Stop the sync and report it"},
+}
+
+var transparencyOptions = []selectOption{
+	{appconfig.TransparencyBusy, "Busy (blocks free/busy)"},
+	{appconfig.TransparencyFree, "Free (informational)"},
+}
+
+// optionsHTML renders select options, marking the selected value.
+func optionsHTML(options []selectOption, selected string) string {
+	var sb strings.Builder
+	for _, o := range options {
+		sel := ""
+		if o.value == selected {
+			sel = " selected"
+		}
+		fmt.Fprintf(&sb, `<option value="%s"%s>%s</option>`,
+			html.EscapeString(o.value), sel, html.EscapeString(o.label))
+	}
+	return sb.String()
+}
+
+// eventColorName returns the colour name for a colorId, or the ID itself when unknown.
+func eventColorName(id string) string {
+	for _, c := range eventColors {
+		if c.value == id {
+			return c.label
+		}
+	}
+	return id
 }
 
 func syncScheduleOptions(selected string) string {
@@ -1374,17 +2624,23 @@ func configFormHTML(title, action, backURL string, data configFormData, formErr
   <small style="color:var(--pico-muted-color)">Weekly fires every Monday 09:00 JST. Monthly fires on the 1st at 09:00 JST. When enabled, manual Sync and Wipe are disabled.</small>
 </label>`, syncScheduleOptions(data.SyncSchedule))
 
-	// Country select
+	// Country multi-select
 	countryOptions := ""
-	for _, cc := range []struct{ val, label string }{
-		{countryCodeJPN, "Japan (jpn)"},
-		{countryCodeVNM, "Vietnam (vnm)"},
-	} {
+	for _, c := range consts.Countries {
 		sel := ""
-		if cc.val == data.CountryCode {
+		if slices.Contains(data.CountryCodes, c.Code) {
 			sel = " selected"
 		}
-		countryOptions += fmt.Sprintf(`<option value="%s"%s>%s</option>`, cc.val, sel, cc.label)
+		countryOptions += fmt.Sprintf(`<option value="%s"%s>%s</option>`,
+			html.EscapeString(c.Code), sel, html.EscapeString(c.Label()))
+	}
+	combinationOptions := ""
+	for _, mode := range []string{appconfig.CountryCombinationAny, appconfig.CountryCombinationAll} {
+		sel := ""
+		if mode == data.CountryCombination || (data.CountryCombination == "" && mode == appconfig.CountryCombinationAny) {
+			sel = " selected"
+		}
+		combinationOptions += fmt.Sprintf(`<option value="%s"%s>%s</option>`, mode, sel, html.EscapeString(countryCombinationLabel(mode)))
 	}
 
 	initialRulesJSON := rulesToJSON(data.Rules)
@@ -1450,10 +2706,47 @@ func configFormHTML(title, action, backURL string, data configFormData, formErr
       </label>
     </div>
 
-    `+sectionHeaderHTML("Holiday Source", "The public holiday calendar to read from. Used to identify non-business days (weekends + public holidays) in the target country.")+`
-    <label for="country_code">Country
-      <select id="country_code" name="country_code">%s</select>
+    `+sectionHeaderHTML("Holiday Sources", "Where non-business days (weekends + holidays) come from. A day is a holiday if any source lists it. At least one source is required.")+`
+    <label for="country_codes">Countries
+      <select id="country_codes" name="country_codes" multiple size="6">%s</select>
+      <small style="color:var(--pico-muted-color)">Google public holiday calendars. Ctrl/Cmd-click to select several, or none to use only the sources below.</small>
+    </label>
+    <label for="country_combination">With several countries
+      <select id="country_combination" name="country_combination">%s</select>
+    </label>
+    <label for="ics_path">iCalendar file (optional)
+      <input type="text" id="ics_path" name="ics_path" value="%s" placeholder="company/holidays.ics">
+      <small style="color:var(--pico-muted-color)">Path of a .ics file relative to the server's holiday files directory. Every event in it counts as a holiday.</small>
     </label>
+    <label for="holiday_list">Company holidays (optional)
+      <textarea id="holiday_list" name="holiday_list" rows="3" placeholder="2026-12-30 Year-end shutdown">%s</textarea>
+      <small style="color:var(--pico-muted-color)">One per line: YYYY-MM-DD followed by a name.</small>
+    </label>
+    <label for="overrides">Business-day overrides (optional)
+      <textarea id="overrides" name="overrides" rows="3" placeholder="2026-05-04 businessDay Working holiday&#10;2026-08-14 nonBusinessDay Summer shutdown">%s</textarea>
+      <small style="color:var(--pico-muted-color)">One per line: YYYY-MM-DD, then <code>businessDay</code> or <code>nonBusinessDay</code>, then an optional reason. Overrides win over weekends and every holiday source, and are applied before first/last business days are computed.</small>
+    </label>
+    <div style="margin-bottom:1rem">
+      <label style="display:flex;align-items:center;gap:0.6rem;cursor:pointer;font-weight:500">
+        <input type="checkbox" name="count_regional_holidays" style="width:1.1rem;height:1.1rem;cursor:pointer"%s>
+        Count regional holidays
+      </label>
+      <label style="display:flex;align-items:center;gap:0.6rem;cursor:pointer;font-weight:500">
+        <input type="checkbox" name="count_observances" style="width:1.1rem;height:1.1rem;cursor:pointer"%s>
+        Count observances
+      </label>
+      <small style="color:var(--pico-muted-color);display:block;margin-top:0.25rem">By default only nationwide public holidays from the country calendars count. Descriptions are recognized in the calendar's language.</small>
+    </div>
+    <div class="two-col">
+      <label for="holiday_include">Always count (optional)
+        <textarea id="holiday_include" name="holiday_include" rows="2" placeholder="Christmas Eve">%s</textarea>
+        <small style="color:var(--pico-muted-color)">Holiday names, one per line</small>
+      </label>
+      <label for="holiday_exclude">Never count (optional)
+        <textarea id="holiday_exclude" name="holiday_exclude" rows="2" placeholder="Vernal Equinox Day">%s</textarea>
+        <small style="color:var(--pico-muted-color)">Holiday names, one per line</small>
+      </label>
+    </div>
 
     `+sectionHeaderHTML("Freeze Rules", "Defines when today counts as a freeze day. Groups are OR'd — if any group matches, today is a freeze day. Within a group, all conditions must match (AND).")+`
     <p style="font-size:0.85rem;color:var(--pico-muted-color);margin-bottom:0.75rem">
@@ -1462,6 +2755,10 @@ func configFormHTML(title, action, backURL string, data configFormData, formErr
     <div id="rules-container"></div>
     <button type="button" class="outline btn-small" onclick="addRuleGroup()" style="margin-bottom:1rem">+ OR group</button>
     <input type="hidden" id="rules_json" name="rules_json">
+    <label for="freeze_windows">Freeze windows (optional)
+      <textarea id="freeze_windows" name="freeze_windows" rows="3" placeholder="yearly 12-24..01-03 Year-end&#10;2026-11-10..2026-11-11 Product launch&#10;lastBusinessDaysOfQuarter 5 Quarter close">%s</textarea>
+      <small style="color:var(--pico-muted-color)">One per line, each with a name: <code>YYYY-MM-DD..YYYY-MM-DD</code>, <code>yearly MM-DD..MM-DD</code> (may wrap over the year end), or <code>lastBusinessDaysOfQuarter N</code> with an optional <code>fiscalYearStartMonth=M</code>. Add <code>template=NAME</code> before the name to use a named blocker template. Days in a window are freeze days in addition to the rules above.</small>
+    </label>
 
     `+sectionHeaderHTML("Target Calendar", "The Google Calendar where blocker events will be written on freeze days. Must be a calendar you have write access to.")+`
     %s
@@ -1472,11 +2769,11 @@ func configFormHTML(title, action, backURL string, data configFormData, formErr
     `+sectionHeaderHTML("Blocker Event", "The calendar event created on each freeze day. Title and description appear in Google Calendar to signal no deployments allowed.")+`
     <label for="event_summary">Summary
       <input type="text" id="event_summary" name="event_summary" value="%s" maxlength="250" placeholder="🚫 PRODUCTION FREEZE - No Deployments" required>
-      <small style="color:var(--pico-muted-color)">Max 250 characters</small>
+      <small style="color:var(--pico-muted-color)">Max 250 characters. May use template fields, e.g. <code>{{.NextHolidayName}}</code>, <code>{{.Rule}}</code>, <code>{{.Date.Format "Jan 2"}}</code>.</small>
     </label>
     <label for="event_description">Description
       <textarea id="event_description" name="event_description" rows="4" maxlength="8000" placeholder="Production operations restricted today.">%s</textarea>
-      <small style="color:var(--pico-muted-color)">HTML supported: &lt;br&gt;, &lt;ul&gt;&lt;li&gt;, &lt;a href=""&gt;, &lt;strong&gt;, &lt;em&gt;. Max 8000 characters.</small>
+      <small style="color:var(--pico-muted-color)">HTML supported: &lt;br&gt;, &lt;ul&gt;&lt;li&gt;, &lt;a href=""&gt;, &lt;strong&gt;, &lt;em&gt;. Max 8000 characters. Template fields work here too and are HTML-escaped.</small>
     </label>
     <div style="margin-bottom:1rem">
       <label style="display:flex;align-items:center;gap:0.6rem;cursor:pointer;font-weight:500">
@@ -1485,6 +2782,13 @@ func configFormHTML(title, action, backURL string, data configFormData, formErr
       </label>
       <small style="color:var(--pico-muted-color);display:block;margin-top:0.25rem">Creates a full-day calendar event instead of a timed one</small>
     </div>
+    <div style="margin-bottom:1rem">
+      <label style="display:flex;align-items:center;gap:0.6rem;cursor:pointer;font-weight:500">
+        <input type="checkbox" name="merge_consecutive_days" style="width:1.1rem;height:1.1rem;cursor:pointer"%s>
+        Merge consecutive freeze days
+      </label>
+      <small style="color:var(--pico-muted-color);display:block;margin-top:0.25rem">Writes one multi-day event for each run of consecutive freeze days instead of one per day. A timed event runs from the start time on the first day to the end time on the last.</small>
+    </div>
     <div id="time-fields" %s>
       <div style="display:grid;grid-template-columns:1fr 1fr;gap:1rem">
         <label for="start_time">Start time
@@ -1495,6 +2799,47 @@ func configFormHTML(title, action, backURL string, data configFormData, formErr
         </label>
       </div>
     </div>
+    <div class="two-col">
+      <label for="color_id">Colour
+        <select id="color_id" name="color_id">%s</select>
+      </label>
+      <label for="visibility">Visibility
+        <select id="visibility" name="visibility">%s</select>
+      </label>
+    </div>
+    <div class="two-col">
+      <label for="transparency">Show as
+        <select id="transparency" name="transparency">%s</select>
+      </label>
+      <label for="reminders">Reminders (optional)
+        <input type="text" id="reminders" name="reminders" value="%s" placeholder="popup 10, email 1440">
+        <small style="color:var(--pico-muted-color)">Empty for the calendar's defaults, <code>none</code> for no reminders</small>
+      </label>
+    </div>
+    <div class="two-col">
+      <label for="attendees">Attendees (optional)
+        <textarea id="attendees" name="attendees" rows="2" placeholder="oncall@example.com&#10;release-managers@example.com">%s</textarea>
+        <small style="color:var(--pico-muted-color)">People or Google groups to invite, one email per line</small>
+      </label>
+      <label for="send_updates">Send invitations to
+        <select id="send_updates" name="send_updates">%s</select>
+        <small style="color:var(--pico-muted-color)">Emails go out when a blocker is created, moved, renamed or its guest list changes</small>
+      </label>
+    </div>
+    <div style="margin-bottom:1rem">
+      <label style="display:flex;align-items:center;gap:0.6rem;cursor:pointer;font-weight:500">
+        <input type="checkbox" name="guests_can_see_other_guests" style="width:1.1rem;height:1.1rem;cursor:pointer"%s>
+        Guests can see other guests
+      </label>
+    </div>
+    <label for="templates">Named templates (optional)
+      <textarea id="templates" name="templates" rows="5" style="font-family:monospace" placeholder="monthEnd:&#10;  summary: Month-end freeze&#10;  allDay: true&#10;  colorId: &quot;11&quot;">%s</textarea>
+      <small style="color:var(--pico-muted-color)">YAML: template name → <code>summary</code>, <code>description</code>, <code>startTime</code>, <code>endTime</code>, <code>allDay</code>, <code>colorId</code> (1–11), <code>visibility</code> (default, public, private), <code>transparency</code> (busy, free), <code>reminders</code>, <code>guestsCanSeeOtherGuests</code>. Unset fields come from the event above. Pick one per rule group or freeze window above.</small>
+    </label>
+    <label for="on_manual_edit">When a blocker was edited by hand
+      <select id="on_manual_edit" name="on_manual_edit">%s</select>
+      <small style="color:var(--pico-muted-color)">What Sync does when someone moved, renamed or deleted a blocker it wrote</small>
+    </label>
 
     `+sectionHeaderHTML("Auto-Sync", "Runs Sync automatically on a schedule so you don't have to click manually. When enabled, manual Sync and Wipe are disabled to prevent conflicts.")+`
     %s
@@ -1508,10 +2853,14 @@ func configFormHTML(title, action, backURL string, data configFormData, formErr
 </div>
 <script>
 var ANCHORS = [
+  {value:'yesterday', label:'yesterday'},
   {value:'today', label:'today'},
   {value:'tomorrow', label:'tomorrow'},
-  {value:'nextDay', label:'next day'}
+  {value:'nextDay', label:'next day'},
+  {value:'expr', label:'expression'}
 ];
+var DEFAULT_EXPR = 'isNthBusinessDayOfMonth(1)';
+var OFFSET_ANCHOR = '__offset';
 var CONDITIONS = [
   {value:'isTheFirstBusinessDayOfTheMonth', label:'1st business day of month'},
   {value:'isTheLastBusinessDayOfTheMonth', label:'last business day of month'},
@@ -1524,14 +2873,26 @@ if (!Array.isArray(rules) || rules.length === 0) {
 }
 
 function anchorSelect(groupIdx, val) {
-  var s = '<select onchange="rules['+groupIdx+'].anchor=this.value">';
+  var isOffset = !ANCHORS.some(function(a) { return a.value === val; });
+  var s = '<select onchange="setAnchor('+groupIdx+', this.value)">';
   ANCHORS.forEach(function(a) {
     s += '<option value="'+a.value+'"'+(a.value===val?' selected':'')+'>'+a.label+'</option>';
   });
-  return s+'</select>';
+  s += '<option value="'+OFFSET_ANCHOR+'"'+(isOffset?' selected':'')+'>offset…</option></select>';
+  if (isOffset) {
+    s += '<input type="text" value="'+escAttr(val)+'" placeholder="+1bd" title="Calendar days (-3d, +2d) or business days (+1bd) from the synced day" style="margin-bottom:0;width:6rem;font-family:monospace" oninput="rules['+groupIdx+'].anchor=this.value.trim()">';
+  }
+  return s;
+}
+
+function escAttr(v) {
+  return String(v).replace(/&/g,'&amp;').replace(/"/g,'&quot;').replace(/</g,'&lt;');
 }
 
 function condSelect(groupIdx, condIdx, val) {
+  if (rules[groupIdx].anchor === 'expr') {
+    return '<input type="text" value="'+escAttr(val)+'" placeholder="'+DEFAULT_EXPR+' and not isHoliday" title="Rule expression, e.g. isLastWeekdayOfQuarter(friday) or isWithinBusinessDaysOfHoliday(2)" style="margin-bottom:0;font-family:monospace" oninput="rules['+groupIdx+'].conditions['+condIdx+']=this.value">';
+  }
   var s = '<select onchange="rules['+groupIdx+'].conditions['+condIdx+']=this.value">';
   CONDITIONS.forEach(function(c) {
     s += '<option value="'+c.value+'"'+(c.value===val?' selected':'')+'>'+c.label+'</option>';
@@ -1547,8 +2908,9 @@ function renderRules() {
     html += '<div style="display:flex;align-items:center;gap:0.5rem;margin-bottom:0.5rem">';
     html += anchorSelect(gi, group.anchor);
     html += '<span style="font-size:0.85rem;color:var(--pico-muted-color)">is:</span>';
+    html += '<input type="text" value="'+escAttr(group.template || '')+'" placeholder="default template" title="Named blocker template for days this group matches; empty for the default event" style="margin:0 0 0 auto;width:10rem;font-size:0.85rem" oninput="rules['+gi+'].template=this.value.trim()">';
     if (rules.length > 1) {
-      html += '<button type="button" class="outline contrast btn-small" style="margin-left:auto" onclick="removeGroup('+gi+')" title="Remove this OR group">✕ group</button>';
+      html += '<button type="button" class="outline contrast btn-small" onclick="removeGroup('+gi+')" title="Remove this OR group">✕ group</button>';
     }
     html += '</div>';
     group.conditions.forEach(function(cond, ci) {
@@ -1585,7 +2947,17 @@ function removeGroup(gi) {
 }
 
 function addCond(gi) {
-  rules[gi].conditions.push('isTheFirstBusinessDayOfTheMonth');
+  rules[gi].conditions.push(rules[gi].anchor === 'expr' ? DEFAULT_EXPR : 'isTheFirstBusinessDayOfTheMonth');
+  renderRules();
+}
+
+// Switching between a day anchor and an expression changes the kind of conditions.
+function setAnchor(gi, anchor) {
+  var wasExpr = rules[gi].anchor === 'expr';
+  rules[gi].anchor = anchor === OFFSET_ANCHOR ? '+1bd' : anchor;
+  if (wasExpr !== (anchor === 'expr')) {
+    rules[gi].conditions = [anchor === 'expr' ? DEFAULT_EXPR : 'isTheFirstBusinessDayOfTheMonth'];
+  }
   renderRules();
 }
 
@@ -1640,11 +3012,21 @@ renderRules();
 		data.LookbackDays,
 		data.LookaheadDays,
 		countryOptions,
+		combinationOptions,
+		html.EscapeString(data.ICSPath),
+		html.EscapeString(data.HolidayList),
+		html.EscapeString(data.Overrides),
+		checkedAttr(data.CountRegionalHolidays),
+		checkedAttr(data.CountObservances),
+		html.EscapeString(data.HolidayInclude),
+		html.EscapeString(data.HolidayExclude),
+		html.EscapeString(data.FreezeWindows),
 		calPicker,
 		html.EscapeString(data.CalendarID),
 		html.EscapeString(data.Summary),
 		html.EscapeString(data.Description),
 		allDayChecked,
+		checkedAttr(data.MergeConsecutiveDays),
 		timeFieldsStyle,
 		html.EscapeString(data.StartTime),
 		timeDisabled,
@@ -1652,6 +3034,15 @@ renderRules();
 		html.EscapeString(data.EndTime),
 		timeDisabled,
 		timeRequired,
+		optionsHTML(eventColors, data.ColorID),
+		optionsHTML(visibilityOptions, data.Visibility),
+		optionsHTML(transparencyOptions, data.Transparency),
+		html.EscapeString(data.Reminders),
+		html.EscapeString(data.Attendees),
+		optionsHTML(sendUpdatesOptions, data.SendUpdates),
+		checkedAttr(data.GuestsCanSeeOtherGuests),
+		html.EscapeString(data.Templates),
+		optionsHTML(manualEditOptions, data.OnManualEdit),
 		autoSyncPicker,
 		deleteBtn,
 		html.EscapeString(backURL),
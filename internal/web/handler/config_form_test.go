@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	appconfig "github.com/nvat/tgifreezeday/internal/config"
+	"github.com/nvat/tgifreezeday/internal/domain"
 )
 
 const (
@@ -41,7 +42,7 @@ func TestFormToAppConfig_ValidInput(t *testing.T) {
 	form := url.Values{
 		formKeyLookback:     {"20"},
 		formKeyLookahead:    {"60"},
-		"country_code":      {countryCodeJPN},
+		"country_codes":     {countryCodeJPN},
 		"write_calendar_id": {"team-cal@group.calendar.google.com"},
 		"event_summary":     {"🚫 PRODUCTION FREEZE"},
 		"event_description": {"No prod ops today."},
@@ -62,8 +63,8 @@ func TestFormToAppConfig_ValidInput(t *testing.T) {
 	if cfg.Shared.LookaheadDays != 60 {
 		t.Errorf("LookaheadDays = %d, want 60", cfg.Shared.LookaheadDays)
 	}
-	if cfg.ReadFrom.GoogleCalendar.CountryCode != countryCodeJPN {
-		t.Errorf("CountryCode = %q, want %s", cfg.ReadFrom.GoogleCalendar.CountryCode, countryCodeJPN)
+	if got := cfg.ReadFrom.GoogleCalendar.CountryCodes; len(got) != 1 || got[0] != countryCodeJPN {
+		t.Errorf("CountryCodes = %v, want [%s]", got, countryCodeJPN)
 	}
 	if cfg.WriteTo.GoogleCalendar.ID != "team-cal@group.calendar.google.com" {
 		t.Errorf("CalendarID = %q", cfg.WriteTo.GoogleCalendar.ID)
@@ -84,7 +85,7 @@ func TestFormToAppConfig_RoundTrip(t *testing.T) {
 	form := url.Values{
 		formKeyLookback:     {"30"},
 		formKeyLookahead:    {"45"},
-		"country_code":      {"vnm"},
+		"country_codes":     {"vnm"},
 		"write_calendar_id": {"myteam@group.calendar.google.com"},
 		"event_summary":     {"Freeze"},
 		"event_description": {"No deployments."},
@@ -124,7 +125,7 @@ func TestFormToAppConfig_MissingRules(t *testing.T) {
 	form := url.Values{
 		formKeyLookback:     {"20"},
 		formKeyLookahead:    {"60"},
-		"country_code":      {countryCodeJPN},
+		"country_codes":     {countryCodeJPN},
 		"write_calendar_id": {"cal@group.calendar.google.com"},
 		"event_summary":     {"Freeze"},
 		"event_description": {"No ops"},
@@ -146,7 +147,7 @@ func TestFormToAppConfig_AllDay(t *testing.T) {
 	form := url.Values{
 		formKeyLookback:     {"20"},
 		formKeyLookahead:    {"60"},
-		"country_code":      {countryCodeJPN},
+		"country_codes":     {countryCodeJPN},
 		"write_calendar_id": {"team-cal@group.calendar.google.com"},
 		"event_summary":     {"🚫 Freeze"},
 		"event_description": {"No prod."},
@@ -191,3 +192,329 @@ func TestFormToAppConfig_InvalidLookback(t *testing.T) {
 		t.Error("formToAppConfig() expected error for invalid lookback_days, got nil")
 	}
 }
+
+func TestFormToAppConfig_OfflineHolidaySources(t *testing.T) {
+	rules := []formRule{
+		{Anchor: ruleAnchorToday, Conditions: []string{"isNonBusinessDay"}},
+	}
+	form := url.Values{
+		formKeyLookback:     {"20"},
+		formKeyLookahead:    {"60"},
+		"ics_path":          {"company/holidays.ics"},
+		"holiday_list":      {"2026-12-30 Year-end shutdown\n\n2026-12-31\n"},
+		"write_calendar_id": {"team-cal@group.calendar.google.com"},
+		"event_summary":     {"Freeze"},
+		"event_description": {"No deployments."},
+		"all_day":           {"on"},
+		formKeyRulesJSON:    {rulesJSON(t, rules)},
+	}
+
+	cfg, err := formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	if cfg.ReadFrom.ICSFile == nil || cfg.ReadFrom.ICSFile.Path != "company/holidays.ics" {
+		t.Errorf("ICSFile = %+v, want company/holidays.ics", cfg.ReadFrom.ICSFile)
+	}
+	want := []appconfig.HolidayListEntry{
+		{Date: "2026-12-30", Name: "Year-end shutdown"},
+		{Date: "2026-12-31"},
+	}
+	if len(cfg.ReadFrom.HolidayList) != len(want) {
+		t.Fatalf("HolidayList = %+v, want %+v", cfg.ReadFrom.HolidayList, want)
+	}
+	for i := range want {
+		if cfg.ReadFrom.HolidayList[i] != want[i] {
+			t.Errorf("HolidayList[%d] = %+v, want %+v", i, cfg.ReadFrom.HolidayList[i], want[i])
+		}
+	}
+	if got := holidayListToText(cfg.ReadFrom.HolidayList); got != "2026-12-30 Year-end shutdown\n2026-12-31" {
+		t.Errorf("holidayListToText() = %q", got)
+	}
+}
+
+func TestFormToAppConfig_NoHolidaySource(t *testing.T) {
+	form := url.Values{
+		formKeyLookback:     {"20"},
+		formKeyLookahead:    {"60"},
+		"write_calendar_id": {"team-cal@group.calendar.google.com"},
+		"event_summary":     {"Freeze"},
+		"all_day":           {"on"},
+		formKeyRulesJSON:    {rulesJSON(t, []formRule{{Anchor: ruleAnchorToday, Conditions: []string{"isNonBusinessDay"}}})},
+	}
+	if _, err := formToAppConfig(makeFormRequest(form)); err == nil {
+		t.Fatal("formToAppConfig() expected error when no holiday source is configured")
+	}
+}
+
+func TestParseOverridesText(t *testing.T) {
+	text := "2026-05-04 businessDay Working holiday\n\n  2026-08-14   nonBusinessDay  \n"
+	got, err := parseOverridesText(text)
+	if err != nil {
+		t.Fatalf("parseOverridesText() error = %v", err)
+	}
+	want := []appconfig.OverrideEntry{
+		{Date: "2026-05-04", Is: appconfig.OverrideBusinessDay, Reason: "Working holiday"},
+		{Date: "2026-08-14", Is: appconfig.OverrideNonBusinessDay},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("parseOverridesText() = %+v, want %+v", got, want)
+	}
+	if back := overridesToText(got); back != "2026-05-04 businessDay Working holiday\n2026-08-14 nonBusinessDay" {
+		t.Errorf("overridesToText() = %q", back)
+	}
+	if _, err := parseOverridesText("2026-05-04"); err == nil {
+		t.Error("parseOverridesText() expected error for a line without a kind")
+	}
+}
+
+func TestFormToAppConfig_MultipleCountries(t *testing.T) {
+	form := url.Values{
+		formKeyLookback:       {"20"},
+		formKeyLookahead:      {"60"},
+		"country_codes":       {countryCodeJPN, "vnm"},
+		"country_combination": {"all"},
+		"write_calendar_id":   {"team-cal@group.calendar.google.com"},
+		"event_summary":       {"Freeze"},
+		"all_day":             {"on"},
+		formKeyRulesJSON:      {rulesJSON(t, []formRule{{Anchor: ruleAnchorToday, Conditions: []string{"isNonBusinessDay"}}})},
+	}
+	cfg, err := formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	g := cfg.ReadFrom.GoogleCalendar
+	if len(g.CountryCodes) != 2 || g.CountryCode != "" {
+		t.Errorf("CountryCodes = %v, CountryCode = %q; want [jpn vnm] and no legacy code", g.CountryCodes, g.CountryCode)
+	}
+	if !g.MatchAllCountries() {
+		t.Errorf("CountryCombination = %q, want all", g.CountryCombination)
+	}
+}
+
+func TestFormToAppConfig_RuleExpressions(t *testing.T) {
+	form := url.Values{
+		formKeyLookback:     {"20"},
+		formKeyLookahead:    {"60"},
+		"country_codes":     {countryCodeJPN},
+		"write_calendar_id": {"team-cal@group.calendar.google.com"},
+		"event_summary":     {"Freeze"},
+		"all_day":           {"on"},
+		formKeyRulesJSON: {rulesJSON(t, []formRule{
+			{Anchor: "expr", Conditions: []string{"  isLastWeekdayOfQuarter(friday) or isNthBusinessDayOfMonth(-1) "}},
+		})},
+	}
+	cfg, err := formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	got := cfg.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf[0]["expr"]
+	if len(got) != 1 || got[0] != "isLastWeekdayOfQuarter(friday) or isNthBusinessDayOfMonth(-1)" {
+		t.Errorf("expr rules = %q, want the trimmed expression", got)
+	}
+
+	form.Set(formKeyRulesJSON, rulesJSON(t, []formRule{{Anchor: "expr", Conditions: []string{"isNthBusinessDayOfMonth(friday)"}}}))
+	if _, err := formToAppConfig(makeFormRequest(form)); err == nil {
+		t.Fatal("formToAppConfig() expected error for an ill-typed expression")
+	}
+}
+
+func TestParseFreezeWindowsText(t *testing.T) {
+	text := "yearly 12-24..01-03 Year-end\n2026-11-10..2026-11-11  Product launch\n\nlastBusinessDaysOfQuarter 5 fiscalYearStartMonth=4 Quarter close\n"
+	got, err := parseFreezeWindowsText(text)
+	if err != nil {
+		t.Fatalf("parseFreezeWindowsText() error = %v", err)
+	}
+	want := []appconfig.FreezeWindowEntry{
+		{Name: "Year-end", From: "12-24", To: "01-03", Yearly: true},
+		{Name: "Product launch", From: "2026-11-10", To: "2026-11-11"},
+		{Name: "Quarter close", LastBusinessDaysOfQuarter: 5, FiscalYearStartMonth: 4},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseFreezeWindowsText() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	wantText := "yearly 12-24..01-03 Year-end\n2026-11-10..2026-11-11 Product launch\nlastBusinessDaysOfQuarter 5 fiscalYearStartMonth=4 Quarter close"
+	if back := freezeWindowsToText(got); back != wantText {
+		t.Errorf("freezeWindowsToText() = %q, want %q", back, wantText)
+	}
+	for _, bad := range []string{"2026-11-10 Launch", "lastBusinessDaysOfQuarter five Close", "yearly"} {
+		if _, err := parseFreezeWindowsText(bad); err == nil {
+			t.Errorf("parseFreezeWindowsText(%q) expected error", bad)
+		}
+	}
+}
+
+func TestFormToAppConfig_Templates(t *testing.T) {
+	form := url.Values{
+		formKeyLookback:     {"20"},
+		formKeyLookahead:    {"60"},
+		"country_codes":     {countryCodeJPN},
+		"write_calendar_id": {"team-cal@group.calendar.google.com"},
+		"event_summary":     {"Freeze"},
+		"all_day":           {"on"},
+		"templates":         {"monthEnd:\n  summary: Month-end freeze\n  colorId: \"11\"\n"},
+		"freeze_windows":    {"yearly 12-24..01-03 template=monthEnd Year-end"},
+		formKeyRulesJSON: {rulesJSON(t, []formRule{
+			{Anchor: ruleAnchorToday, Conditions: []string{"isTheLastBusinessDayOfTheMonth"}, Template: " monthEnd "},
+			{Anchor: ruleAnchorTomorrow, Conditions: []string{"isNonBusinessDay"}},
+		})},
+	}
+	cfg, err := formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	rules := cfg.ReadFrom.GoogleCalendar.TodayIsFreezeDayIf
+	if got := domain.RuleGroupTemplate(rules[0]); got != "monthEnd" {
+		t.Errorf("group 1 template = %q, want monthEnd", got)
+	}
+	if _, ok := rules[1][domain.RuleTemplateKey]; ok {
+		t.Error("group 2 should not have a template key")
+	}
+	if got := cfg.ReadFrom.FreezeWindows[0].Template; got != "monthEnd" {
+		t.Errorf("freeze window template = %q, want monthEnd", got)
+	}
+	if got := cfg.BlockerTemplates("").For("monthEnd"); got.Summary != "Month-end freeze" || got.ColorID != "11" || !got.AllDay {
+		t.Errorf("monthEnd template = %+v", got)
+	}
+
+	// The rules builder and the templates textarea get the templates back on edit.
+	var back []formRule
+	if err := json.Unmarshal([]byte(rulesToJSON(rules)), &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back) != 2 || back[0].Anchor != ruleAnchorToday || back[0].Template != "monthEnd" {
+		t.Errorf("rulesToJSON() = %+v, want the today group with template monthEnd", back)
+	}
+	if text := templatesToYAML(cfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Templates); !strings.Contains(text, "monthEnd:") {
+		t.Errorf("templatesToYAML() = %q, want the monthEnd template", text)
+	}
+
+	form.Set(formKeyRulesJSON, rulesJSON(t, []formRule{{Anchor: ruleAnchorToday, Conditions: []string{"isNonBusinessDay"}, Template: "missing"}}))
+	if _, err := formToAppConfig(makeFormRequest(form)); err == nil {
+		t.Error("formToAppConfig() expected error for an unknown template")
+	}
+	form.Set("templates", "monthEnd:\n  sumary: typo\n")
+	if _, err := formToAppConfig(makeFormRequest(form)); err == nil {
+		t.Error("formToAppConfig() expected error for an unknown template field")
+	}
+}
+
+func TestFormToAppConfig_MergeConsecutiveDays(t *testing.T) {
+	form := url.Values{
+		formKeyLookback:          {"20"},
+		formKeyLookahead:         {"60"},
+		"country_codes":          {countryCodeJPN},
+		"write_calendar_id":      {"team-cal@group.calendar.google.com"},
+		"event_summary":          {"Freeze"},
+		"all_day":                {"on"},
+		"merge_consecutive_days": {"on"},
+		formKeyRulesJSON:         {rulesJSON(t, []formRule{{Anchor: ruleAnchorToday, Conditions: []string{"isNonBusinessDay"}}})},
+	}
+	cfg, err := formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	if !cfg.BlockerTemplates("").MergeConsecutiveDays {
+		t.Error("MergeConsecutiveDays = false, want true")
+	}
+	yamlContent, err := cfg.ToYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(yamlContent, "mergeConsecutiveDays: true") {
+		t.Errorf("ToYAML() = %s, want mergeConsecutiveDays: true", yamlContent)
+	}
+}
+
+func TestFormToAppConfig_EventSettings(t *testing.T) {
+	form := url.Values{
+		formKeyLookback:     {"20"},
+		formKeyLookahead:    {"60"},
+		"country_codes":     {countryCodeJPN},
+		"write_calendar_id": {"team-cal@group.calendar.google.com"},
+		"event_summary":     {"Freeze"},
+		"all_day":           {"on"},
+		"color_id":          {"11"},
+		"visibility":        {"default"},
+		"transparency":      {"busy"},
+		"reminders":         {"popup 10, email 1440"},
+		formKeyRulesJSON:    {rulesJSON(t, []formRule{{Anchor: ruleAnchorToday, Conditions: []string{"isNonBusinessDay"}}})},
+	}
+	cfg, err := formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	d := cfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay.Default
+	if d.ColorID == nil || *d.ColorID != "11" {
+		t.Errorf("ColorID = %v, want 11", d.ColorID)
+	}
+	if d.Visibility != nil || d.Transparency != nil {
+		t.Errorf("Visibility = %v, Transparency = %v; defaults should be left out of the YAML", d.Visibility, d.Transparency)
+	}
+	if d.GuestsCanSeeOtherGuests == nil || *d.GuestsCanSeeOtherGuests {
+		t.Errorf("GuestsCanSeeOtherGuests = %v, want false for an unticked box", d.GuestsCanSeeOtherGuests)
+	}
+	if got := remindersToText(d.Reminders); got != "popup 10, email 1440" {
+		t.Errorf("reminders = %q, want the form value back", got)
+	}
+
+	form.Set("reminders", remindersNone)
+	form.Set("guests_can_see_other_guests", "on")
+	form.Set("transparency", "free")
+	cfg, err = formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	tmpl := cfg.BlockerTemplate()
+	if tmpl.Reminders == nil || len(tmpl.Reminders) != 0 || !tmpl.Free || tmpl.HideOtherGuests {
+		t.Errorf("BlockerTemplate() = %+v, want free, no reminders and guests visible", tmpl)
+	}
+
+	for _, bad := range []string{"popup", "popup ten", "sms 10"} {
+		form.Set("reminders", bad)
+		if _, err := formToAppConfig(makeFormRequest(form)); err == nil {
+			t.Errorf("formToAppConfig() with reminders %q expected error", bad)
+		}
+	}
+}
+
+func TestFormToAppConfig_Attendees(t *testing.T) {
+	form := url.Values{
+		formKeyLookback:     {"20"},
+		formKeyLookahead:    {"60"},
+		"country_codes":     {countryCodeJPN},
+		"write_calendar_id": {"team-cal@group.calendar.google.com"},
+		"event_summary":     {"Freeze"},
+		"all_day":           {"on"},
+		"attendees":         {"oncall@example.com, release@example.com\n\n"},
+		"send_updates":      {domain.SendUpdatesNone},
+		formKeyRulesJSON:    {rulesJSON(t, []formRule{{Anchor: ruleAnchorToday, Conditions: []string{"isNonBusinessDay"}}})},
+	}
+	cfg, err := formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	freeze := cfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay
+	if freeze.Default.Attendees == nil || len(*freeze.Default.Attendees) != 2 {
+		t.Errorf("Attendees = %v, want two addresses", freeze.Default.Attendees)
+	}
+	if freeze.SendUpdates != "" {
+		t.Errorf("SendUpdates = %q, the default should be left out of the YAML", freeze.SendUpdates)
+	}
+
+	form.Set("attendees", "")
+	form.Set("send_updates", domain.SendUpdatesAll)
+	cfg, err = formToAppConfig(makeFormRequest(form))
+	if err != nil {
+		t.Fatalf("formToAppConfig() error = %v", err)
+	}
+	freeze = cfg.WriteTo.GoogleCalendar.IfTodayIsFreezeDay
+	if freeze.Default.Attendees != nil || freeze.SendUpdates != domain.SendUpdatesAll {
+		t.Errorf("Attendees = %v, SendUpdates = %q; want none and %q", freeze.Default.Attendees, freeze.SendUpdates, domain.SendUpdatesAll)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+	"github.com/nvat/tgifreezeday/internal/perm"
+)
+
+func TestNewSyncPreview_CountsAndOrder(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	tmpl := domain.BlockerTemplate{Summary: "Freeze", AllDay: true}
+	plan := &domain.SyncPlan{
+		Create:      []*domain.Blocker{tmpl.BlockerOn(day("2026-05-10"))},
+		Delete:      []*domain.Blocker{{EventID: "old", Date: day("2026-05-06"), Summary: "Freeze"}},
+		Keep:        []*domain.Blocker{{EventID: "same", Date: day("2026-05-09"), Summary: "Freeze", AllDay: true}},
+		DaysChecked: 7,
+	}
+
+	p := newSyncPreview(plan, day("2026-05-04"), day("2026-05-11"))
+
+	if p.Counts[domain.PlanActionAdd] != 1 || p.Counts[domain.PlanActionRemove] != 1 || p.Counts[domain.PlanActionKeep] != 1 {
+		t.Errorf("Counts = %v, want 1 add, 1 remove, 1 keep", p.Counts)
+	}
+	wantOrder := []string{"2026-05-06", "2026-05-09", "2026-05-10"}
+	if len(p.Changes) != len(wantOrder) {
+		t.Fatalf("len(Changes) = %d, want %d", len(p.Changes), len(wantOrder))
+	}
+	for i, want := range wantOrder {
+		if p.Changes[i].Date != want {
+			t.Errorf("Changes[%d].Date = %s, want %s", i, p.Changes[i].Date, want)
+		}
+	}
+	if p.Changes[0].Action != domain.PlanActionRemove || p.Changes[0].EventID != "old" {
+		t.Errorf("Changes[0] = %+v, want remove of event old", p.Changes[0])
+	}
+}
+
+func TestNewSyncPreview_StoppedByDrift(t *testing.T) {
+	plan := &domain.SyncPlan{
+		Drift:       []domain.Drift{{Kind: domain.DriftDeleted, Date: "2026-05-09", EventID: "sat", Expected: "2026-05-09 all day"}},
+		DriftPolicy: domain.DriftPolicyStop,
+	}
+
+	p := newSyncPreview(plan, time.Now(), time.Now())
+
+	if !p.Stopped || len(p.Drift) != 1 || p.Drift[0].Kind != domain.DriftDeleted {
+		t.Errorf("preview = %+v, want one deleted drift and Stopped", p)
+	}
+	if got := previewHTML(p); !strings.Contains(got, "Sync will stop") {
+		t.Errorf("previewHTML does not say the sync will stop:\n%s", got)
+	}
+}
+
+func TestDriftHTML_ResolveButtons(t *testing.T) {
+	drift := []domain.Drift{{Kind: domain.DriftSummary, Date: "2026-05-09", EventID: "sat", Expected: "Freeze", Actual: "Freeze <b>"}}
+
+	got := driftHTML("/app", 7, drift, domain.DriftPolicyStop, true)
+	for _, want := range []string{"renamed", "Freeze &lt;b&gt;", `hx-post="/app/configs/7/sync"`, `"on_manual_edit":"overwrite"`, `"on_manual_edit":"respect"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("driftHTML missing %q", want)
+		}
+	}
+	if got := driftHTML("/app", 7, drift, domain.DriftPolicyStop, false); strings.Contains(got, "hx-post") {
+		t.Error("driftHTML offers to resolve drift without permission")
+	}
+}
+
+func TestHandlePreview_JSONErrors(t *testing.T) {
+	env := newAPITestEnv(t)
+	owner := env.user(t, "owner@example.com")
+	cfg, err := env.h.configs.Create(owner.ID, "Broken", "v1", "shared: [", "none", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	id := strconv.FormatInt(cfg.ID, 10)
+
+	w := call(env.h.HandlePreview, owner, perm.RoleWrite, "GET", "/configs/"+id+"/preview?format=json", "", "id", id)
+	if w.Code != http.StatusBadGateway || w.Header().Get("Content-Type") != "application/json" || errorCode(t, w) != apiErrUpstream {
+		t.Errorf("JSON preview of a broken config = %d %q %s, want 502 upstream_error as JSON", w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+	w = call(env.h.HandlePreview, owner, perm.RoleWrite, "GET", "/configs/999/preview?format=json", "", "id", "999")
+	if w.Code != http.StatusNotFound || errorCode(t, w) != apiErrNotFound {
+		t.Errorf("JSON preview of a missing config = %d %s, want 404 not_found", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+func TestSyncOutcomeHTML_Partial(t *testing.T) {
+	outcome := &domain.SyncOutcome{
+		Status: domain.SyncStatusPartial,
+		Result: &domain.SyncResult{
+			Created:  3,
+			HeldBack: 1,
+			Failed:   []domain.SyncFailure{{Action: domain.PlanActionAdd, Date: "2026-05-09", Err: errors.New("quota <exceeded>")}},
+		},
+	}
+
+	got := syncOutcomeHTML(outcome)
+	for _, want := range []string{"Sync partially applied", "created 3", "1 change(s) failed", "1 removal(s) were held back", "add 2026-05-09: quota &lt;exceeded&gt;"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("syncOutcomeHTML missing %q:\n%s", want, got)
+		}
+	}
+
+	if got := syncOutcomeHTML(domain.FailedSync("no token")); !strings.Contains(got, "no token") || strings.Contains(got, "partially") {
+		t.Errorf("syncOutcomeHTML(failed) = %s, want the plain error", got)
+	}
+}
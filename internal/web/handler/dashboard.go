@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"html"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/nvat/tgifreezeday/internal/adapter/db"
 	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
@@ -52,6 +54,16 @@ func (h *DashboardHandler) HandleDashboard(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Configs hidden by the filter can share a calendar too.
+	allCfgs := cfgs
+	if filterUserID != nil {
+		if allCfgs, err = h.configs.ListAllWithAuthor(nil); err != nil {
+			httpError(w, http.StatusInternalServerError, "failed to load configs")
+			return
+		}
+	}
+	shared := findSharedCalendars(allCfgs)
+
 	allUsers, _ := h.users.ListAll()
 
 	// Fetch current user's calendar names in one API call for display
@@ -89,8 +101,17 @@ func (h *DashboardHandler) HandleDashboard(w http.ResponseWriter, r *http.Reques
 			Author:       author,
 			CalendarID:   calID,
 			CalendarName: calDisplay,
+			SharesCalendar: slices.ContainsFunc(shared, func(s sharedCalendar) bool {
+				return s.First.ID == c.ID || s.Second.ID == c.ID
+			}),
 		})
 	}
+	visible := shared[:0:0]
+	for _, s := range shared {
+		if slices.ContainsFunc(rows, func(r dashRow) bool { return r.ID == s.First.ID || r.ID == s.Second.ID }) {
+			visible = append(visible, s)
+		}
+	}
 
 	greeting := currentUser.DisplayName
 	if greeting == "" {
@@ -101,7 +122,65 @@ func (h *DashboardHandler) HandleDashboard(w http.ResponseWriter, r *http.Reques
 	welcome := r.URL.Query().Get("welcome") == "1"
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	fmt.Fprint(w, dashboardPageHTML(h.basePath, greeting, rows, allUsers, filterMine, authorParam, role, currentUser.ID, welcome)) //nolint:errcheck
+	fmt.Fprint(w, dashboardPageHTML(h.basePath, greeting, rows, visible, calNames, allUsers, filterMine, authorParam, role, currentUser.ID, welcome)) //nolint:errcheck
+}
+
+// sharedCalendar is two configs writing blockers to the same calendar. Each config only
+// manages its own events, so a day frozen by both gets two blockers.
+type sharedCalendar struct {
+	CalendarID    string
+	First, Second *db.ConfigWithAuthor
+}
+
+// findSharedCalendars pairs up the configs that write to the same calendar. Configs whose
+// YAML does not parse are skipped.
+func findSharedCalendars(cfgs []*db.ConfigWithAuthor) []sharedCalendar {
+	type target struct {
+		cfg   *db.ConfigWithAuthor
+		calID string
+	}
+	targets := make([]target, 0, len(cfgs))
+	for _, c := range cfgs {
+		parsed, err := appconfig.LoadWithDefaultFromByteArray([]byte(c.ConfigYAML))
+		if err != nil || parsed.WriteTo.GoogleCalendar.ID == "" {
+			continue
+		}
+		targets = append(targets, target{cfg: c, calID: parsed.WriteTo.GoogleCalendar.ID})
+	}
+
+	var shared []sharedCalendar
+	for i, a := range targets {
+		for _, b := range targets[i+1:] {
+			if a.calID == b.calID {
+				shared = append(shared, sharedCalendar{CalendarID: a.calID, First: a.cfg, Second: b.cfg})
+			}
+		}
+	}
+	return shared
+}
+
+// sharedCalendarsHTML renders the warning banner listing configs that share a calendar.
+func sharedCalendarsHTML(basePath string, shared []sharedCalendar, calNames map[string]string) string {
+	if len(shared) == 0 {
+		return ""
+	}
+	var items strings.Builder
+	for _, s := range shared {
+		cal := s.CalendarID
+		if name := calNames[cal]; name != "" {
+			cal = name
+		}
+		fmt.Fprintf(&items, `<li><a href="%s/configs/%d">%s</a> and <a href="%s/configs/%d">%s</a> both write to <strong>%s</strong></li>`,
+			basePath, s.First.ID, html.EscapeString(trunc(s.First.Name, 50)),
+			basePath, s.Second.ID, html.EscapeString(trunc(s.Second.Name, 50)),
+			html.EscapeString(trunc(cal, 50)))
+	}
+	return fmt.Sprintf(`
+<div style="background:#3b2f1e;border:1px solid #92400e;border-radius:0.5rem;padding:0.75rem 1rem;margin-bottom:1.25rem;font-size:0.88rem">
+  <strong>⚠️ Configs share a calendar</strong>
+  <ul style="margin:0.4rem 0">%s</ul>
+  <span style="color:var(--pico-muted-color)">Each config only syncs and wipes its own blockers, so days frozen by both get two blocker events. Point one of them at another calendar unless that is intended.</span>
+</div>`, items.String())
 }
 
 func trunc(s string, n int) string {
@@ -122,9 +201,11 @@ type dashRow struct {
 	Author       string
 	CalendarID   string
 	CalendarName string
+	// SharesCalendar is set when another config writes to the same calendar.
+	SharesCalendar bool
 }
 
-func dashboardPageHTML(basePath string, greeting string, rows []dashRow, allUsers []*db.User, filterMine bool, authorParam string, role perm.Role, currentUserID int64, welcome bool) string {
+func dashboardPageHTML(basePath string, greeting string, rows []dashRow, shared []sharedCalendar, calNames map[string]string, allUsers []*db.User, filterMine bool, authorParam string, role perm.Role, currentUserID int64, welcome bool) string {
 	// --- filter bar ---
 	btnStyle := `style="padding:0.3rem 0.9rem;font-size:0.85rem;margin:0"`
 
@@ -209,6 +290,9 @@ func dashboardPageHTML(basePath string, greeting string, rows []dashRow, allUser
 				html.EscapeString(trunc(r.Author, 40)),
 				html.EscapeString(trunc(calDisplay, 50)),
 			)
+			if r.SharesCalendar {
+				meta += ` &nbsp;·&nbsp; <span style="color:#fbbf24" title="Another config writes blockers to this calendar">⚠️ shared calendar</span>`
+			}
 			editBtn := ""
 			if role.CanEditConfig(r.UserID, currentUserID) {
 				editBtn = fmt.Sprintf(`<a href="`+basePath+`/configs/%d/edit" role="button" class="outline secondary" style="padding:0.2rem 0.6rem;font-size:0.82rem;margin:0">Edit</a>`, r.ID)
@@ -257,6 +341,7 @@ func dashboardPageHTML(basePath string, greeting string, rows []dashRow, allUser
     <div class="user-area">
       <span>%s</span>
       <span style="font-size:0.75rem;padding:0.15rem 0.5rem;border-radius:999px;background:%s;color:%s;border:1px solid %s">%s</span>
+      <a href="`+basePath+`/tokens" style="font-size:0.85rem">API Tokens</a>
       %s
     </div>
   </nav>
@@ -268,6 +353,7 @@ func dashboardPageHTML(basePath string, greeting string, rows []dashRow, allUser
     </div>
     %s
     %s
+    %s
   </div>
   `+pageFooterHTML()+`
 </body>
@@ -282,7 +368,7 @@ func dashboardPageHTML(basePath string, greeting string, rows []dashRow, allUser
 			}
 			return ""
 		}(),
-		filterBar, cards)
+		sharedCalendarsHTML(basePath, shared, calNames), filterBar, cards)
 }
 
 func autoSyncDashBadge(schedule string) string {
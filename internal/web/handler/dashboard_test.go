@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+)
+
+func TestFindSharedCalendars(t *testing.T) {
+	yamlFor := func(calendarID string) string {
+		return "shared:\n  lookbackDays: 20\n  lookaheadDays: 60\nwriteTo:\n  googleCalendar:\n    id: \"" + calendarID + "\"\n"
+	}
+	cfg := func(id int64, name, yaml string) *db.ConfigWithAuthor {
+		return &db.ConfigWithAuthor{Config: db.Config{ID: id, Name: name, ConfigYAML: yaml}}
+	}
+	cfgs := []*db.ConfigWithAuthor{
+		cfg(1, "Ops", yamlFor("team@group.calendar.google.com")),
+		cfg(2, "Payments", yamlFor("payments@group.calendar.google.com")),
+		cfg(3, "Ops month-end", yamlFor("team@group.calendar.google.com")),
+		cfg(4, "Broken", "shared: ["),
+	}
+
+	shared := findSharedCalendars(cfgs)
+
+	if len(shared) != 1 {
+		t.Fatalf("len(shared) = %d, want 1", len(shared))
+	}
+	s := shared[0]
+	if s.First.ID != 1 || s.Second.ID != 3 || s.CalendarID != "team@group.calendar.google.com" {
+		t.Errorf("shared = %d and %d on %s, want 1 and 3 on the team calendar", s.First.ID, s.Second.ID, s.CalendarID)
+	}
+
+	banner := sharedCalendarsHTML("", shared, map[string]string{"team@group.calendar.google.com": "Team <Ops>"})
+	if !strings.Contains(banner, "Team &lt;Ops&gt;") || !strings.Contains(banner, `href="/configs/3"`) {
+		t.Errorf("banner should name the calendar and link both configs, got %s", banner)
+	}
+	if sharedCalendarsHTML("", nil, nil) != "" {
+		t.Error("no shared calendars should render no banner")
+	}
+}
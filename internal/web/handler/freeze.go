@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
+	"github.com/nvat/tgifreezeday/internal/domain"
+	"github.com/nvat/tgifreezeday/internal/holidays"
+)
+
+const (
+	// holidayCacheTTL is how long fetched public holidays are used before Google is asked again.
+	holidayCacheTTL = 24 * time.Hour
+	// freezeQueryMaxDays bounds how far from today the freeze query looks.
+	freezeQueryMaxDays = 366
+	// freezeQueryPaddingDays is how many days around the queried date are evaluated, so that
+	// anchors, first and last business days of the month and quarter windows see the days
+	// they depend on.
+	freezeQueryPaddingDays = 120
+)
+
+// apiFreeze is the JSON answer of the freeze query.
+type apiFreeze struct {
+	ConfigID int64  `json:"configId"`
+	Date     string `json:"date"`
+	Freeze   bool   `json:"freeze"`
+	// BusinessDay and Holidays describe the date after the holiday policy and overrides.
+	BusinessDay bool     `json:"businessDay"`
+	Holidays    []string `json:"holidays"`
+	// Rule is the freeze window or todayIsFreezeDayIf group that matched.
+	Rule     string           `json:"rule,omitempty"`
+	Template string           `json:"template,omitempty"`
+	Blocker  *apiFreezeWindow `json:"blocker,omitempty"`
+}
+
+// apiFreezeWindow is the blocker the sync writes on the freeze day.
+type apiFreezeWindow struct {
+	Summary   string `json:"summary"`
+	AllDay    bool   `json:"allDay"`
+	StartTime string `json:"startTime,omitempty"` // "HH:MM" in the target calendar's time zone.
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+// APIFreeze reports whether ?date= (default: today in JST) is a freeze day for the config,
+// which rule matched and the blocker's time window. Holidays come from the holiday cache.
+// Without an authenticated user in the context (FREEZE_API_PUBLIC) any config can be queried.
+func (h *ConfigHandler) APIFreeze(w http.ResponseWriter, r *http.Request) {
+	date, err := freezeQueryDate(r.URL.Query().Get("date"), time.Now())
+	if err != nil {
+		apiError(w, http.StatusBadRequest, apiErrBadRequest, err.Error())
+		return
+	}
+	var cfg *db.Config
+	public := userFromContext(r.Context()) == nil
+	if !public {
+		if cfg = h.apiConfigFromPath(w, r); cfg == nil {
+			return
+		}
+	} else {
+		id, ok := idFromPath(r)
+		if !ok {
+			apiError(w, http.StatusBadRequest, apiErrBadRequest, "invalid config id")
+			return
+		}
+		if cfg, err = h.configs.GetByID(id); err != nil {
+			apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to load config")
+			return
+		}
+		if cfg == nil {
+			apiError(w, http.StatusNotFound, apiErrNotFound, "config not found")
+			return
+		}
+	}
+	// Unauthenticated callers get generic messages: config and Google errors can tell
+	// them about the config and its owner's account.
+	fail := func(status int, code, generic string, err error) {
+		msg := err.Error()
+		if public {
+			log.WithError(err).WithField("config_id", cfg.ID).Warn("public freeze query failed")
+			msg = generic
+		}
+		apiError(w, status, code, msg)
+	}
+
+	appCfg, err := h.parseAppConfig(cfg.ConfigYAML)
+	if err != nil {
+		fail(http.StatusUnprocessableEntity, apiErrInvalidConfig, "the config is invalid", err)
+		return
+	}
+	// Public holidays are fetched with the owner's Google account, so the query works for
+	// tokens of other users and without authentication.
+	businessCal, err := holidays.CachedCalendarFromConfig(appCfg, h.holidayCache, func() (*googlecalendar.Repository, error) {
+		return h.buildRepo(r.Context(), cfg.UserID, cfg.ID, appCfg)
+	})
+	if err != nil {
+		fail(http.StatusBadGateway, apiErrUpstream, "failed to read holidays", err)
+		return
+	}
+	mapping, err := domain.BuildTGIFMapping(date.AddDate(0, 0, -freezeQueryPaddingDays), date.AddDate(0, 0, freezeQueryPaddingDays+1), businessCal)
+	if err != nil {
+		fail(http.StatusBadGateway, apiErrUpstream, "failed to read holidays", fmt.Errorf("failed to read holidays: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, evaluateFreeze(cfg.ID, (*mapping)[domain.NewDateKey(date)], appCfg.FreezeRules(), appCfg.BlockerTemplates(cfg.Name)))
+}
+
+// evaluateFreeze answers the freeze query for one day of the mapping.
+func evaluateFreeze(configID int64, day *domain.TGIFDay, rules *domain.FreezeRules, templates *domain.BlockerTemplates) apiFreeze {
+	out := apiFreeze{
+		ConfigID:    configID,
+		Date:        string(day.Key),
+		BusinessDay: day.IsBusinessDay,
+		Holidays:    day.HolidayNames,
+	}
+	if out.Holidays == nil {
+		out.Holidays = []string{}
+	}
+	match, ok := rules.Match(day)
+	if !ok {
+		return out
+	}
+	b := templates.BlockerFor(day, match)
+	out.Freeze, out.Rule, out.Template = true, match.Rule, match.Template
+	out.Blocker = &apiFreezeWindow{Summary: b.Summary, AllDay: b.AllDay, StartTime: b.StartTime, EndTime: b.EndTime}
+	return out
+}
+
+// freezeQueryDate parses the ?date= of a freeze query, defaulting to today in JST. Dates
+// more than freezeQueryMaxDays from today are rejected.
+func freezeQueryDate(s string, now time.Time) (time.Time, error) {
+	y, m, d := now.In(jstDisplay).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if s == "" {
+		return today, nil
+	}
+	date, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date must be YYYY-MM-DD, got %q", s)
+	}
+	if date.Before(today.AddDate(0, 0, -freezeQueryMaxDays)) || date.After(today.AddDate(0, 0, freezeQueryMaxDays)) {
+		return time.Time{}, fmt.Errorf("date must be within %d days of today", freezeQueryMaxDays)
+	}
+	return date, nil
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/perm"
+)
+
+func TestFreezeQueryDate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC) // 2026-10-19 05:00 JST
+	if got, err := freezeQueryDate("", now); err != nil || got.Format(time.DateOnly) != "2026-10-19" {
+		t.Errorf("default date = %v, %v; want today in JST, 2026-10-19", got, err)
+	}
+	if got, err := freezeQueryDate("2026-12-30", now); err != nil || got != time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("freezeQueryDate(2026-12-30) = %v, %v", got, err)
+	}
+	for _, s := range []string{"19/10/2026", "2028-01-01", "2025-01-01"} {
+		if _, err := freezeQueryDate(s, now); err == nil {
+			t.Errorf("freezeQueryDate(%q) accepted", s)
+		}
+	}
+}
+
+func TestAPIFreeze(t *testing.T) {
+	env := newAPITestEnv(t)
+	owner := env.user(t, "owner@example.com")
+
+	// A Monday to Thursday a week or two ahead: a company holiday, followed by a business day.
+	y, m, d := time.Now().In(jstDisplay).Date()
+	holiday := time.Date(y, m, d+7, 0, 0, 0, 0, time.UTC)
+	for holiday.Weekday() < time.Monday || holiday.Weekday() > time.Thursday {
+		holiday = holiday.AddDate(0, 0, 1)
+	}
+	cfgYAML := fmt.Sprintf(`
+shared:
+  lookbackDays: 20
+  lookaheadDays: 60
+readFrom:
+  googleCalendar:
+    todayIsFreezeDayIf:
+      - today: [isNonBusinessDay]
+  holidayList:
+    - date: %q
+      name: "Offsite"
+writeTo:
+  googleCalendar:
+    id: "example-freeze@example.com"
+    ifTodayIsFreezeDay:
+      default:
+        summary: "No deploys"
+        startTime: "09:00"
+        endTime: "18:00"
+`, holiday.Format(time.DateOnly))
+	if _, err := env.h.parseAppConfig(cfgYAML); err != nil {
+		t.Fatalf("test config is invalid: %v", err)
+	}
+	cfg, err := env.h.configs.Create(owner.ID, "Team", "v1", cfgYAML, "none", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	id := strconv.FormatInt(cfg.ID, 10)
+
+	query := func(user *db.User, date string) (int, apiFreeze) {
+		w := call(env.h.APIFreeze, user, perm.RoleReadOnly, "GET", "/api/v1/configs/"+id+"/freeze?date="+date, "", "id", id)
+		var got apiFreeze
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("freeze body %q: %v", w.Body, err)
+			}
+		}
+		return w.Code, got
+	}
+
+	// Without a user, as with FREEZE_API_PUBLIC=true.
+	code, got := query(nil, holiday.Format(time.DateOnly))
+	if code != http.StatusOK || !got.Freeze || got.BusinessDay || got.Rule != "todayIsFreezeDayIf #1" ||
+		len(got.Holidays) != 1 || got.Holidays[0] != "Offsite" ||
+		got.Blocker == nil || *got.Blocker != (apiFreezeWindow{Summary: "No deploys", StartTime: "09:00", EndTime: "18:00"}) {
+		t.Errorf("freeze on the holiday = %d %+v, want a freeze 09:00-18:00 by rule #1", code, got)
+	}
+
+	code, got = query(owner, holiday.AddDate(0, 0, 1).Format(time.DateOnly))
+	if code != http.StatusOK || got.Freeze || !got.BusinessDay || got.Rule != "" || got.Blocker != nil {
+		t.Errorf("freeze on the next business day = %d %+v, want no freeze", code, got)
+	}
+
+	other := env.user(t, "other@example.com")
+	if code, _ := query(other, holiday.Format(time.DateOnly)); code != http.StatusNotFound {
+		t.Errorf("freeze as another user = %d, want 404", code)
+	}
+	if code, _ := query(owner, "tomorrow"); code != http.StatusBadRequest {
+		t.Errorf("freeze with a bad date = %d, want 400", code)
+	}
+}
+
+func TestAPIFreeze_PublicErrorsAreGeneric(t *testing.T) {
+	env := newAPITestEnv(t)
+	owner := env.user(t, "owner@example.com")
+	cfg, err := env.h.configs.Create(owner.ID, "Broken", "v1", "shared: [", "none", nil)
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+	id := strconv.FormatInt(cfg.ID, 10)
+
+	message := func(user *db.User) string {
+		w := call(env.h.APIFreeze, user, perm.RoleReadOnly, "GET", "/api/v1/configs/"+id+"/freeze", "", "id", id)
+		var body apiErrorBody
+		if w.Code != http.StatusUnprocessableEntity || json.Unmarshal(w.Body.Bytes(), &body) != nil {
+			t.Fatalf("freeze of a broken config = %d %s, want 422", w.Code, w.Body)
+		}
+		return body.Error.Message
+	}
+	if got := message(nil); got != "the config is invalid" {
+		t.Errorf("public error message = %q, want the generic one", got)
+	}
+	if got := message(owner); got == "the config is invalid" {
+		t.Error("the owner gets the generic message, want the parse error")
+	}
+}
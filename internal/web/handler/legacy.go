@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/adapter/googlecalendar"
+	appconfig "github.com/nvat/tgifreezeday/internal/config"
+	"github.com/nvat/tgifreezeday/internal/domain"
+	"github.com/nvat/tgifreezeday/internal/holidays"
+	"github.com/nvat/tgifreezeday/internal/scheduler"
+)
+
+// Legacy blocker actions, see HandleResolveLegacyBlocker.
+const (
+	legacyActionAdopt  = "adopt"
+	legacyActionDelete = "delete"
+)
+
+// legacyEnv is what reading and adopting a config's legacy blockers needs.
+type legacyEnv struct {
+	repo                 *googlecalendar.Repository
+	appCfg               *appconfig.Config
+	cal                  *domain.BusinessCalendar
+	rangeStart, rangeEnd time.Time
+}
+
+func (h *ConfigHandler) openLegacyEnv(ctx context.Context, userID int64, cfg *db.Config) (*legacyEnv, error) {
+	appCfg, err := h.parseAppConfig(cfg.ConfigYAML)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := h.buildRepo(ctx, userID, cfg.ID, appCfg)
+	if err != nil {
+		return nil, err
+	}
+	cal, err := holidays.CalendarFromConfig(appCfg, repo)
+	if err != nil {
+		return nil, err
+	}
+	rangeStart, rangeEnd := dateRange(appCfg.Shared.LookbackDays, appCfg.Shared.LookaheadDays)
+	return &legacyEnv{repo: repo, appCfg: appCfg, cal: cal, rangeStart: rangeStart, rangeEnd: rangeEnd}, nil
+}
+
+// HandleLegacyBlockers lists the blockers from an earlier version that the config could not
+// tell were its own, with buttons to adopt or delete each one. Writes nothing. Returns an
+// HTML partial (HTMX).
+func (h *ConfigHandler) HandleLegacyBlockers(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	canResolve := roleFromContext(r.Context()).CanSyncConfig(cfg.UserID, user.ID)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	env, err := h.openLegacyEnv(r.Context(), user.ID, cfg)
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Legacy Blockers", err.Error(), true)) //nolint:errcheck
+		return
+	}
+	found, err := scheduler.FindLegacyBlockers(env.repo, h.configs, cfg, env.appCfg, env.cal, env.rangeStart, env.rangeEnd)
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Legacy Blockers", "failed to find legacy blockers: "+err.Error(), true)) //nolint:errcheck
+		return
+	}
+	fmt.Fprint(w, legacyBlockersHTML(h.basePath, cfg.ID, found.Unmatched, len(found.Owned), canResolve, "")) //nolint:errcheck,gosec
+}
+
+// HandleResolveLegacyBlocker adopts (action=adopt) or deletes (action=delete) one unmatched
+// legacy blocker, then runs the adoption again, which records the migration as done once
+// none are left. Returns the refreshed legacy blocker list (HTMX).
+func (h *ConfigHandler) HandleResolveLegacyBlocker(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	if role := roleFromContext(r.Context()); !role.CanSyncConfig(cfg.UserID, user.ID) {
+		httpError(w, http.StatusForbidden, "you do not have permission to change this config's blockers")
+		return
+	}
+	action := r.FormValue("action")
+	if action != legacyActionAdopt && action != legacyActionDelete {
+		httpError(w, http.StatusBadRequest, "action must be adopt or delete")
+		return
+	}
+	eventID := r.PathValue("eventID")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	env, err := h.openLegacyEnv(r.Context(), user.ID, cfg)
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Legacy Blockers", err.Error(), true)) //nolint:errcheck
+		return
+	}
+	found, err := scheduler.FindLegacyBlockers(env.repo, h.configs, cfg, env.appCfg, env.cal, env.rangeStart, env.rangeEnd)
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Legacy Blockers", "failed to find legacy blockers: "+err.Error(), true)) //nolint:errcheck
+		return
+	}
+	// Only events that are still unmatched legacy blockers of the range can be acted on.
+	i := slices.IndexFunc(found.Unmatched, func(b *domain.Blocker) bool { return b.EventID == eventID })
+	if i < 0 {
+		fmt.Fprint(w, legacyBlockersHTML(h.basePath, cfg.ID, found.Unmatched, len(found.Owned), true, "That event is no longer an unmatched legacy blocker.")) //nolint:errcheck,gosec
+		return
+	}
+	b := found.Unmatched[i]
+	if action == legacyActionAdopt {
+		_, err = env.repo.AdoptLegacyBlockers([]*domain.Blocker{b})
+	} else {
+		err = env.repo.DeleteBlocker(b.EventID)
+	}
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Legacy Blockers", err.Error(), true)) //nolint:errcheck
+		return
+	}
+	verb := map[string]string{legacyActionAdopt: "adopted", legacyActionDelete: "deleted"}[action]
+	h.recordAudit(r, cfg, db.AuditLegacyBlocker, fmt.Sprintf("%s %s %q", verb, b.Key(), b.Summary), "", "")
+
+	unmatched, err := scheduler.AdoptLegacyBlockers(env.repo, h.configs, cfg, env.appCfg, env.cal, env.rangeStart, env.rangeEnd)
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Legacy Blockers", err.Error(), true)) //nolint:errcheck
+		return
+	}
+	msg := fmt.Sprintf("Blocker on %s %s.", b.Key(), verb)
+	if len(unmatched) == 0 {
+		msg += " No legacy blockers are left; the migration is done."
+	}
+	fmt.Fprint(w, legacyBlockersHTML(h.basePath, cfg.ID, unmatched, 0, true, msg)) //nolint:errcheck,gosec
+}
+
+// legacyBlockersNoticeHTML renders the detail page notice for a config whose legacy blocker
+// migration left blockers it could not tell were its own; empty otherwise.
+func legacyBlockersNoticeHTML(basePath string, cfg *db.Config) string {
+	if cfg.LegacyBlockersAdoptedAt != nil || cfg.LegacyBlockersUnmatched == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+  <div style="background:#3b2f1e;border:1px solid #92400e;border-radius:0.5rem;padding:0.75rem 1rem;margin-bottom:1.25rem;font-size:0.88rem">
+    <strong>⚠️ %d blocker(s) from an earlier version need a decision</strong><br>
+    <span style="color:var(--pico-muted-color)">They carry the "Managed by tgifreezeday" line but don't match what this config writes today, so sync and wipe leave them alone.</span>
+    <button
+      hx-get="`+basePath+`/configs/%d/legacy"
+      hx-target="#blockers-panel"
+      hx-swap="innerHTML"
+      class="outline"
+      style="margin:0.5rem 0 0;padding:0.3rem 0.8rem;font-size:0.85rem">
+      Review legacy blockers
+    </button>
+  </div>`, cfg.LegacyBlockersUnmatched, cfg.ID)
+}
+
+// legacyBlockersHTML renders the unmatched legacy blockers. owned is how many more the next
+// sync or wipe adopts on its own. With canResolve, each blocker gets adopt and delete buttons.
+func legacyBlockersHTML(basePath string, configID int64, unmatched []*domain.Blocker, owned int, canResolve bool, msg string) string {
+	header := fmt.Sprintf(`
+  <div style="font-size:0.88rem;color:var(--pico-muted-color);margin-bottom:0.5rem">
+    Legacy Blockers &nbsp;·&nbsp; <strong style="color:var(--pico-color)">%d unmatched</strong>`, len(unmatched))
+	if owned > 0 {
+		header += fmt.Sprintf(` &nbsp;·&nbsp; %d more the next sync or wipe adopts`, owned)
+	}
+	header += `
+  </div>`
+	if msg != "" {
+		header += `
+  <div style="font-size:0.85rem;margin-bottom:0.5rem">` + html.EscapeString(msg) + `</div>`
+	}
+	if len(unmatched) == 0 {
+		return `<div>` + header + `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>No legacy blockers need a decision.</em></p></div>`
+	}
+
+	var rows strings.Builder
+	for _, b := range unmatched {
+		actions := ""
+		if canResolve {
+			url := html.EscapeString(fmt.Sprintf("%s/configs/%d/legacy/%s", basePath, configID, b.EventID))
+			actions = fmt.Sprintf(`
+      <button class="outline" style="margin:0;padding:0.2rem 0.6rem;font-size:0.82rem" hx-post="%s" hx-vals='{"action":"adopt"}' hx-target="#blockers-panel" title="Tag the event as this config's, so sync updates or removes it and wipe deletes it">Adopt</button>
+      <button class="outline secondary" style="margin:0;padding:0.2rem 0.6rem;font-size:0.82rem" hx-post="%s" hx-vals='{"action":"delete"}' hx-target="#blockers-panel" hx-confirm="Delete this event from the calendar?">Delete</button>`, url, url)
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td>%s</td><td style="white-space:nowrap">%s</td></tr>`,
+			html.EscapeString(string(b.Key())), html.EscapeString(b.Summary), actions)
+	}
+	return fmt.Sprintf(`
+<div>%s
+  <p style="font-size:0.85rem;color:var(--pico-muted-color)">These events carry the "Managed by tgifreezeday" line from an earlier version, but this config would not write them today: another config on the calendar may own them, or they are left over from older rules or holidays. Adopt the ones that are this config's and delete stale ones.</p>
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>Date</th><th>Summary</th><th></th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+</div>`, header, rows.String())
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+func TestLegacyBlockersHTML(t *testing.T) {
+	unmatched := []*domain.Blocker{{EventID: "ev1", Date: time.Date(2026, 5, 9, 0, 0, 0, 0, time.UTC), Summary: "Freeze <old>"}}
+
+	got := legacyBlockersHTML("/app", 7, unmatched, 2, true, "")
+	for _, want := range []string{"1 unmatched", "2 more the next sync or wipe adopts", "2026-05-09", "Freeze &lt;old&gt;", `hx-post="/app/configs/7/legacy/ev1"`, `"action":"adopt"`, `"action":"delete"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("legacyBlockersHTML missing %q", want)
+		}
+	}
+	if got := legacyBlockersHTML("/app", 7, unmatched, 0, false, ""); strings.Contains(got, "hx-post") {
+		t.Error("legacyBlockersHTML offers to adopt or delete without permission")
+	}
+}
+
+func TestLegacyBlockersNoticeHTML(t *testing.T) {
+	cfg := &db.Config{ID: 7}
+	if legacyBlockersNoticeHTML("/app", cfg) != "" {
+		t.Error("notice shown before any blockers were left unmatched")
+	}
+	cfg.LegacyBlockersUnmatched = 3
+	if got := legacyBlockersNoticeHTML("/app", cfg); !strings.Contains(got, "3 blocker(s)") || !strings.Contains(got, `hx-get="/app/configs/7/legacy"`) {
+		t.Errorf("notice = %s, want the count and a link to the list", got)
+	}
+	adoptedAt := time.Now()
+	cfg.LegacyBlockersAdoptedAt = &adoptedAt
+	if legacyBlockersNoticeHTML("/app", cfg) != "" {
+		t.Error("notice shown after the migration is done")
+	}
+}
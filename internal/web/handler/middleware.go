@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/nvat/tgifreezeday/internal/adapter/db"
 	"github.com/nvat/tgifreezeday/internal/logging"
@@ -40,6 +41,43 @@ func RequireAuth(users *db.UserStore, secret []byte, resolver *perm.Resolver, lo
 	})
 }
 
+// RequireAPIAuth authenticates API requests by an "Authorization: Bearer" API token or,
+// without that header, by the session cookie. Unauthenticated requests get a 401 JSON error.
+// On success, stores the *db.User and perm.Role in the request context like RequireAuth.
+func RequireAPIAuth(users *db.UserStore, apiTokens *db.APITokenStore, secret []byte, resolver *perm.Resolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var userID int64
+		var ok bool
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			token, isBearer := strings.CutPrefix(auth, "Bearer ")
+			if !isBearer {
+				apiError(w, http.StatusUnauthorized, apiErrUnauthorized, "expected an Authorization: Bearer API token")
+				return
+			}
+			var err error
+			if userID, ok, err = apiTokens.Authenticate(strings.TrimSpace(token)); err != nil {
+				logging.GetLogger().WithError(err).Error("failed to check api token")
+				apiError(w, http.StatusInternalServerError, apiErrInternal, "failed to check API token")
+				return
+			}
+		} else {
+			userID, ok = session.GetUserID(r, secret)
+		}
+		if !ok {
+			apiError(w, http.StatusUnauthorized, apiErrUnauthorized, "missing or invalid API token")
+			return
+		}
+		user, err := users.GetByID(userID)
+		if err != nil || user == nil {
+			apiError(w, http.StatusUnauthorized, apiErrUnauthorized, "the token's user no longer exists")
+			return
+		}
+		ctx := context.WithValue(r.Context(), userCtxKey, user)
+		ctx = context.WithValue(ctx, roleCtxKey, resolver.RoleFor(user.Email))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func userFromContext(ctx context.Context) *db.User {
 	u, _ := ctx.Value(userCtxKey).(*db.User)
 	return u
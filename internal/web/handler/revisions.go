@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+)
+
+// HandleRevisions lists the config's revisions, newest first, with a form to compare
+// two of them (HTMX).
+func (h *ConfigHandler) HandleRevisions(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	revs, err := h.configs.ListRevisions(cfg.ID)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Revisions", "failed to load revisions: "+err.Error(), true)) //nolint:errcheck
+		return
+	}
+	fmt.Fprint(w, revisionsHTML(h.basePath, cfg, revs)) //nolint:errcheck,gosec
+}
+
+// HandleRevision returns one revision's YAML (HTMX).
+func (h *ConfigHandler) HandleRevision(w http.ResponseWriter, r *http.Request) {
+	cfg, rev, ok := h.revisionFromPath(w, r)
+	if !ok {
+		return
+	}
+	user := userFromContext(r.Context())
+	canRollback := roleFromContext(r.Context()).CanEditConfig(cfg.UserID, user.ID) && rev.ConfigYAML != cfg.ConfigYAML
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, revisionHTML(h.basePath, cfg, rev, canRollback)) //nolint:errcheck,gosec
+}
+
+// HandleRevisionCompare returns a side-by-side diff of revisions ?from= and ?to= (HTMX).
+func (h *ConfigHandler) HandleRevisionCompare(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	from, errFrom := strconv.Atoi(r.URL.Query().Get("from"))
+	to, errTo := strconv.Atoi(r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil {
+		httpError(w, http.StatusBadRequest, "invalid revision")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	var revs [2]*db.ConfigRevision
+	for i, n := range []int{from, to} {
+		rev, err := h.configs.GetRevision(cfg.ID, n)
+		if err != nil {
+			httpError(w, http.StatusInternalServerError, "failed to load revision")
+			return
+		}
+		if rev == nil {
+			httpError(w, http.StatusNotFound, fmt.Sprintf("revision %d not found", n))
+			return
+		}
+		revs[i] = rev
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, revisionCompareHTML(h.basePath, cfg.ID, revs[0], revs[1])) //nolint:errcheck,gosec
+}
+
+// HandleRevisionRollback saves a past revision's YAML as a new revision and re-validates
+// the config. Returns HX-Redirect to the detail page.
+func (h *ConfigHandler) HandleRevisionRollback(w http.ResponseWriter, r *http.Request) {
+	cfg, rev, ok := h.revisionFromPath(w, r)
+	if !ok {
+		return
+	}
+	user := userFromContext(r.Context())
+	if role := roleFromContext(r.Context()); !role.CanEditConfig(cfg.UserID, user.ID) {
+		httpError(w, http.StatusForbidden, "you do not have permission to edit this config")
+		return
+	}
+	// Revisions saved before a schema change may no longer be valid.
+	if _, err := h.parseAppConfig(rev.ConfigYAML); err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, actionResultHTML("Rollback", fmt.Sprintf("Revision %d can no longer be used: %s", rev.Revision, err), true)) //nolint:errcheck
+		return
+	}
+	note := fmt.Sprintf("rollback to revision %d", rev.Revision)
+	if err := h.configs.Update(cfg.ID, cfg.UserID, user.ID, cfg.Name, rev.ConfigYAML, cfg.SyncSchedule, cfg.NextSyncAt, note); err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to roll back config")
+		return
+	}
+	h.recordAudit(r, cfg, db.AuditConfigRollback, note, cfg.ConfigYAML, rev.ConfigYAML)
+	go h.validateAndUpdateStatus(cfg.ID, user.ID, rev.ConfigYAML)
+	w.Header().Set("HX-Redirect", fmt.Sprintf(h.basePath+"/configs/%d", cfg.ID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revisionFromPath loads the config and revision named by the path, writing an error
+// response and returning false if either is missing.
+func (h *ConfigHandler) revisionFromPath(w http.ResponseWriter, r *http.Request) (*db.Config, *db.ConfigRevision, bool) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return nil, nil, false
+	}
+	n, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "invalid revision")
+		return nil, nil, false
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return nil, nil, false
+	}
+	rev, err := h.configs.GetRevision(cfg.ID, n)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to load revision")
+		return nil, nil, false
+	}
+	if rev == nil {
+		httpError(w, http.StatusNotFound, "revision not found")
+		return nil, nil, false
+	}
+	return cfg, rev, true
+}
+
+func revisionBy(rev *db.ConfigRevision) string {
+	if rev.UserEmail != "" {
+		return rev.UserEmail
+	}
+	return "deleted user"
+}
+
+func revisionOptions(revs []*db.ConfigRevision, selected int) string {
+	var b strings.Builder
+	for _, rev := range revs {
+		sel := ""
+		if rev.Revision == selected {
+			sel = " selected"
+		}
+		fmt.Fprintf(&b, `<option value="%d"%s>Revision %d</option>`, rev.Revision, sel, rev.Revision)
+	}
+	return b.String()
+}
+
+func revisionsHTML(basePath string, cfg *db.Config, revs []*db.ConfigRevision) string {
+	revsURL := fmt.Sprintf("%s/configs/%d/revisions", basePath, cfg.ID)
+	header := fmt.Sprintf(`
+  <div style="font-size:0.88rem;color:var(--pico-muted-color);margin-bottom:0.5rem">
+    Revisions &nbsp;·&nbsp; <strong style="color:var(--pico-color)">%d revision(s)</strong> &nbsp;·&nbsp; current: revision %d
+  </div>`, len(revs), cfg.Revision)
+
+	compare := ""
+	if len(revs) > 1 {
+		compare = fmt.Sprintf(`
+  <form hx-get="%s/compare" hx-target="#blockers-panel" hx-swap="innerHTML" style="display:flex;gap:0.5rem;align-items:center;margin-bottom:0.75rem;font-size:0.85rem">
+    Compare <select name="from" style="margin:0;width:auto">%s</select>
+    with <select name="to" style="margin:0;width:auto">%s</select>
+    <button type="submit" class="outline" style="margin:0;padding:0.2rem 0.7rem;font-size:0.8rem;width:auto">Compare</button>
+  </form>`, html.EscapeString(revsURL), revisionOptions(revs, revs[1].Revision), revisionOptions(revs, revs[0].Revision))
+	}
+
+	var rows strings.Builder
+	for _, rev := range revs {
+		label := strconv.Itoa(rev.Revision)
+		if rev.Revision == cfg.Revision {
+			label += " (current)"
+		}
+		actions := historyButton(fmt.Sprintf("%s/%d", revsURL, rev.Revision), "View", "outline")
+		if rev.Revision != cfg.Revision {
+			actions += " " + historyButton(fmt.Sprintf("%s/compare?from=%d&to=%d", revsURL, rev.Revision, cfg.Revision), "Compare with current", "outline secondary")
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td style="white-space:nowrap">%s</td><td>%s</td><td>%s</td><td style="white-space:nowrap">%s</td></tr>`,
+			html.EscapeString(label),
+			html.EscapeString(rev.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+			html.EscapeString(revisionBy(rev)),
+			html.EscapeString(rev.Note),
+			actions)
+	}
+	return fmt.Sprintf(`
+<div>%s%s
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>Revision</th><th>Saved</th><th>By</th><th>Note</th><th></th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+</div>`, header, compare, rows.String())
+}
+
+func revisionHTML(basePath string, cfg *db.Config, rev *db.ConfigRevision, canRollback bool) string {
+	revsURL := fmt.Sprintf("%s/configs/%d/revisions", basePath, cfg.ID)
+	buttons := historyButton(revsURL, "← Revisions", "outline secondary")
+	if rev.Revision != cfg.Revision {
+		buttons = historyButton(fmt.Sprintf("%s/compare?from=%d&to=%d", revsURL, rev.Revision, cfg.Revision), "Compare with current", "outline") + buttons
+	}
+	if canRollback {
+		buttons = fmt.Sprintf(`<button class="secondary" style="margin:0;padding:0.2rem 0.7rem;font-size:0.8rem"
+      hx-post="%s/%d/rollback" hx-target="#action-result" hx-swap="innerHTML"
+      hx-confirm="Save revision %d as a new revision and make it the current config?">↩ Roll back to this revision</button>`,
+			html.EscapeString(revsURL), rev.Revision, rev.Revision) + buttons
+	}
+	note := ""
+	if rev.Note != "" {
+		note = " &nbsp;·&nbsp; " + html.EscapeString(rev.Note)
+	}
+	return fmt.Sprintf(`
+<div>
+  <div style="display:flex;justify-content:space-between;align-items:center;gap:0.5rem;margin-bottom:0.5rem">
+    <div style="font-size:0.88rem;color:var(--pico-muted-color)">
+      Revision %d &nbsp;·&nbsp; saved %s by %s &nbsp;·&nbsp; schema: %s%s
+    </div>
+    <div style="display:flex;gap:0.5rem">%s</div>
+  </div>
+  <pre class="line-numbers language-yaml" style="white-space:pre-wrap;word-break:break-word;margin:0"><code>%s</code></pre>
+</div>`,
+		rev.Revision,
+		html.EscapeString(rev.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+		html.EscapeString(revisionBy(rev)),
+		html.EscapeString(rev.SchemaVersion), note,
+		buttons,
+		html.EscapeString(rev.ConfigYAML))
+}
+
+func revisionCompareHTML(basePath string, configID int64, from, to *db.ConfigRevision) string {
+	return fmt.Sprintf(`
+<div>
+  <div style="display:flex;justify-content:space-between;align-items:center;gap:0.5rem;margin-bottom:0.5rem">
+    <div style="font-size:0.88rem;color:var(--pico-muted-color)">
+      Revision %d (%s) → revision %d (%s)
+    </div>
+    %s
+  </div>
+  %s
+</div>`,
+		from.Revision, html.EscapeString(from.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+		to.Revision, html.EscapeString(to.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+		historyButton(fmt.Sprintf("%s/configs/%d/revisions", basePath, configID), "← Revisions", "outline secondary"),
+		yamlDiffHTML(from.ConfigYAML, to.ConfigYAML))
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+)
+
+func TestRevisionsHTML(t *testing.T) {
+	cfg := &db.Config{ID: 7, Revision: 3}
+	saved := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	revs := []*db.ConfigRevision{
+		{ConfigID: 7, Revision: 3, UserEmail: "b@example.com", Note: "rollback to revision 1", CreatedAt: saved},
+		{ConfigID: 7, Revision: 2, UserEmail: "a@example.com", CreatedAt: saved},
+		{ConfigID: 7, Revision: 1, CreatedAt: saved},
+	}
+
+	got := revisionsHTML("/app", cfg, revs)
+	for _, want := range []string{"3 revision(s)", "3 (current)", "rollback to revision 1", "deleted user", "2026-10-05 09:00 JST",
+		`hx-get="/app/configs/7/revisions/compare"`, `<option value="2" selected>`, `hx-get="/app/configs/7/revisions/1"`,
+		`hx-get="/app/configs/7/revisions/compare?from=2&amp;to=3"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("revisionsHTML missing %q", want)
+		}
+	}
+	if strings.Contains(got, "from=3&amp;to=3") {
+		t.Error("revisionsHTML offers to compare the current revision with itself")
+	}
+}
+
+func TestRevisionHTML(t *testing.T) {
+	cfg := &db.Config{ID: 7, Revision: 3}
+	rev := &db.ConfigRevision{ConfigID: 7, Revision: 1, SchemaVersion: "v1", ConfigYAML: "name: <x>\n"}
+
+	got := revisionHTML("/app", cfg, rev, true)
+	for _, want := range []string{"Revision 1", "name: &lt;x&gt;", `hx-post="/app/configs/7/revisions/1/rollback"`, "from=1&amp;to=3"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("revisionHTML missing %q", want)
+		}
+	}
+	if got := revisionHTML("/app", cfg, rev, false); strings.Contains(got, "/rollback") {
+		t.Error("revisionHTML offers a rollback without permission")
+	}
+}
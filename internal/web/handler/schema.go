@@ -1,9 +1,11 @@
 package handler
 
 import (
+	_ "embed"
 	"fmt"
 	"html"
 	"net/http"
+	"strings"
 
 	appconfig "github.com/nvat/tgifreezeday/internal/config"
 )
@@ -16,6 +18,15 @@ func NewSchemaHandler(basePath string) *SchemaHandler {
 	return &SchemaHandler{basePath: basePath}
 }
 
+//go:embed openapi.yaml
+var openAPIYAML string
+
+// HandleOpenAPI serves the OpenAPI document of the JSON API, with the server URL under basePath.
+func (h *SchemaHandler) HandleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	fmt.Fprint(w, strings.Replace(openAPIYAML, "- url: /api/v1", "- url: "+h.basePath+"/api/v1", 1)) //nolint:errcheck
+}
+
 func (h *SchemaHandler) HandleSchemaRef(w http.ResponseWriter, r *http.Request) {
 	version := r.PathValue("version")
 	schemaYAML, ok := appconfig.SchemaYAML(version)
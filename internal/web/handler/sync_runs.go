@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+// syncRunsPageSize is the number of runs per page of the sync history.
+const syncRunsPageSize = 20
+
+// HandleSyncRuns returns a page of the config's sync history, newest first (HTMX).
+// The page number comes from ?page=, starting at 1.
+func (h *ConfigHandler) HandleSyncRuns(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	runs, total, err := h.runs.List(cfg.ID, syncRunsPageSize, (page-1)*syncRunsPageSize)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		fmt.Fprint(w, actionResultHTML("Sync History", "failed to load sync history: "+err.Error(), true)) //nolint:errcheck
+		return
+	}
+	fmt.Fprint(w, syncRunsHTML(h.basePath, cfg.ID, runs, total, page)) //nolint:errcheck,gosec
+}
+
+// HandleSyncRun returns one sync run with its per-date outcome (HTMX).
+func (h *ConfigHandler) HandleSyncRun(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, ok := idFromPath(r)
+	if !ok {
+		httpError(w, http.StatusBadRequest, "invalid config id")
+		return
+	}
+	runID, err := strconv.ParseInt(r.PathValue("runID"), 10, 64)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "invalid run id")
+		return
+	}
+	cfg, err := h.getConfig(r.Context(), id, user.ID)
+	if err != nil || cfg == nil {
+		httpError(w, http.StatusNotFound, "config not found")
+		return
+	}
+	run, err := h.runs.Get(cfg.ID, runID)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to load sync run")
+		return
+	}
+	if run == nil {
+		httpError(w, http.StatusNotFound, "sync run not found")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, syncRunHTML(h.basePath, run)) //nolint:errcheck,gosec
+}
+
+// syncStatusStyle maps a sync status to its badge label and colours.
+var syncStatusStyle = map[domain.SyncStatus]struct{ label, style string }{
+	domain.SyncStatusSuccess: {"✓ success", "background:#1a4731;color:#4ade80;border:1px solid #166534"},
+	domain.SyncStatusPartial: {"⚠ partial", "background:#3b2f0b;color:#fbbf24;border:1px solid #854d0e"},
+	domain.SyncStatusFailed:  {"✗ failed", "background:#4a1122;color:#f87171;border:1px solid #7f1d1d"},
+}
+
+func syncStatusBadge(status domain.SyncStatus) string {
+	st, ok := syncStatusStyle[status]
+	if !ok {
+		st.label = string(status)
+	}
+	return fmt.Sprintf(`<span style="padding:0.1rem 0.5rem;border-radius:999px;font-size:0.75rem;font-weight:600;white-space:nowrap;%s">%s</span>`,
+		st.style, html.EscapeString(st.label))
+}
+
+// syncRunBy names who started a run.
+func syncRunBy(run *db.SyncRun) string {
+	switch {
+	case run.Trigger == db.SyncTriggerAuto:
+		return "Auto-Sync"
+	case run.UserEmail != "":
+		return run.UserEmail
+	default:
+		return "deleted user"
+	}
+}
+
+// syncRunRevision names the config revision a run synced.
+func syncRunRevision(run *db.SyncRun) string {
+	if run.ConfigRevision == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("rev %d", run.ConfigRevision)
+}
+
+// historyButton renders a button that loads a history fragment into the blockers panel.
+func historyButton(url, label, class string) string {
+	return fmt.Sprintf(`<button class="%s" style="margin:0;padding:0.2rem 0.7rem;font-size:0.8rem" hx-get="%s" hx-target="#blockers-panel" hx-swap="innerHTML">%s</button>`,
+		class, html.EscapeString(url), html.EscapeString(label))
+}
+
+func syncRunsHTML(basePath string, configID int64, runs []*db.SyncRun, total, page int) string {
+	header := fmt.Sprintf(`
+  <div style="font-size:0.88rem;color:var(--pico-muted-color);margin-bottom:0.5rem">
+    Sync History &nbsp;·&nbsp; <strong style="color:var(--pico-color)">%d run(s)</strong>
+  </div>`, total)
+	if total == 0 {
+		return `<div>` + header + `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>This config has not been synced yet.</em></p></div>`
+	}
+
+	runsURL := fmt.Sprintf("%s/configs/%d/runs", basePath, configID)
+	var rows strings.Builder
+	for _, run := range runs {
+		changes := fmt.Sprintf("+%d ~%d −%d", run.Created, run.Updated, run.Deleted)
+		if run.Failed > 0 {
+			changes += fmt.Sprintf(", %d failed", run.Failed)
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td>%s</td><td style="white-space:nowrap">%s</td><td>%s</td><td>%s</td><td style="white-space:nowrap">%s</td><td>%s</td></tr>`,
+			html.EscapeString(run.StartedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+			html.EscapeString(run.Trigger),
+			html.EscapeString(syncRunRevision(run)),
+			html.EscapeString(syncRunBy(run)),
+			syncStatusBadge(run.Status),
+			html.EscapeString(changes),
+			historyButton(fmt.Sprintf("%s/%d", runsURL, run.ID), "Details", "outline"))
+	}
+
+	lastPage := (total + syncRunsPageSize - 1) / syncRunsPageSize
+	var pager []string
+	if page > 1 {
+		pager = append(pager, historyButton(fmt.Sprintf("%s?page=%d", runsURL, page-1), "← Newer", "outline secondary"))
+	}
+	pager = append(pager, fmt.Sprintf(`<span style="font-size:0.8rem;color:var(--pico-muted-color)">Page %d of %d</span>`, page, lastPage))
+	if page < lastPage {
+		pager = append(pager, historyButton(fmt.Sprintf("%s?page=%d", runsURL, page+1), "Older →", "outline secondary"))
+	}
+	return fmt.Sprintf(`
+<div>%s
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>Started</th><th>Trigger</th><th>Config</th><th>By</th><th>Status</th><th>Changes</th><th></th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+  <div style="display:flex;gap:0.75rem;align-items:center;justify-content:center">%s</div>
+</div>`, header, rows.String(), strings.Join(pager, ""))
+}
+
+// changeResultStyle colours the result of a change in a run's drill-down.
+var changeResultStyle = map[string]string{
+	domain.ChangeApplied:   "color:#4ade80",
+	domain.ChangeFailed:    "color:#f87171",
+	domain.ChangeHeldBack:  "color:#fbbf24",
+	domain.ChangeUnchanged: "color:var(--pico-muted-color)",
+}
+
+func syncRunHTML(basePath string, run *db.SyncRun) string {
+	rangeLabel := "—"
+	if run.RangeStart != "" {
+		rangeLabel = run.RangeStart + " → " + run.RangeEnd
+	}
+	header := fmt.Sprintf(`
+  <div style="display:flex;justify-content:space-between;align-items:center;margin-bottom:0.5rem">
+    <div style="font-size:0.88rem;color:var(--pico-muted-color)">
+      Sync Run #%d &nbsp;·&nbsp; %s &nbsp;·&nbsp; %s by %s &nbsp;·&nbsp; config %s &nbsp;·&nbsp; %s (%s) &nbsp;·&nbsp; range %s
+    </div>
+    %s
+  </div>
+  <p style="font-size:0.85rem">%s</p>`,
+		run.ID, syncStatusBadge(run.Status),
+		html.EscapeString(run.Trigger), html.EscapeString(syncRunBy(run)), html.EscapeString(syncRunRevision(run)),
+		html.EscapeString(run.StartedAt.In(jstDisplay).Format("2006-01-02 15:04:05 JST")),
+		html.EscapeString(run.FinishedAt.Sub(run.StartedAt).Round(100*time.Millisecond).String()),
+		html.EscapeString(rangeLabel),
+		historyButton(fmt.Sprintf("%s/configs/%d/runs", basePath, run.ConfigID), "← History", "outline secondary"),
+		html.EscapeString(run.Message))
+	if len(run.Changes) == 0 {
+		return `<div>` + header + `<p style="color:var(--pico-muted-color);text-align:center;padding:1rem"><em>No changes were planned.</em></p></div>`
+	}
+
+	var rows strings.Builder
+	for _, c := range run.Changes {
+		st := previewActionStyle[c.Action]
+		dates := c.Date
+		if c.EndDate != "" {
+			dates += " → " + c.EndDate
+		}
+		fmt.Fprintf(&rows, `<tr><td style="white-space:nowrap">%s</td><td><span style="padding:0.1rem 0.5rem;border-radius:999px;font-size:0.75rem;font-weight:600;white-space:nowrap;%s">%s</span></td><td>%s</td><td style="white-space:nowrap;%s">%s</td><td style="font-size:0.78rem;color:var(--pico-muted-color)">%s</td></tr>`,
+			html.EscapeString(dates), st.style, html.EscapeString(st.label),
+			html.EscapeString(c.Summary),
+			changeResultStyle[c.Result], html.EscapeString(c.Result),
+			html.EscapeString(c.Error))
+	}
+	return fmt.Sprintf(`
+<div>%s
+  <table class="striped" style="font-size:0.85rem">
+    <thead><tr><th>Date</th><th>Action</th><th>Summary</th><th>Result</th><th>Error</th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+</div>`, header, rows.String())
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+	"github.com/nvat/tgifreezeday/internal/domain"
+)
+
+func TestSyncRunsHTML_Pagination(t *testing.T) {
+	started := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	runs := []*db.SyncRun{{ID: 42, ConfigID: 7, ConfigRevision: 3, Trigger: db.SyncTriggerAuto, StartedAt: started, Status: domain.SyncStatusPartial, Created: 2, Failed: 1}}
+
+	got := syncRunsHTML("/app", 7, runs, 45, 2)
+	for _, want := range []string{"45 run(s)", "2026-10-05 09:00 JST", "rev 3", "Auto-Sync", "⚠ partial", "+2 ~0 −0, 1 failed",
+		`hx-get="/app/configs/7/runs/42"`, `hx-get="/app/configs/7/runs?page=1"`, `hx-get="/app/configs/7/runs?page=3"`, "Page 2 of 3"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("syncRunsHTML missing %q", want)
+		}
+	}
+	if got := syncRunsHTML("/app", 7, runs, 45, 3); strings.Contains(got, "page=4") {
+		t.Error("syncRunsHTML links past the last page")
+	}
+}
+
+func TestSyncRunHTML_Changes(t *testing.T) {
+	run := &db.SyncRun{
+		ID: 42, ConfigID: 7, Trigger: db.SyncTriggerManual, UserEmail: "a@example.com",
+		Status: domain.SyncStatusPartial, Message: "Sync incomplete", RangeStart: "2026-10-01", RangeEnd: "2026-11-30",
+		Changes: []*db.SyncRunChange{
+			{Date: "2026-10-10", EndDate: "2026-10-11", Action: domain.PlanActionAdd, Summary: "Weekend", Result: domain.ChangeFailed, Error: "quota <exceeded>"},
+			{Date: "2026-10-10", Action: domain.PlanActionRemove, Summary: "Sat", Result: domain.ChangeHeldBack},
+		},
+	}
+
+	got := syncRunHTML("/app", run)
+	for _, want := range []string{"Sync Run #42", "manual by a@example.com", "2026-10-01 → 2026-11-30", "2026-10-10 → 2026-10-11",
+		"quota &lt;exceeded&gt;", "held back", `hx-get="/app/configs/7/runs"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("syncRunHTML missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nvat/tgifreezeday/internal/adapter/db"
+)
+
+// APITokenHandler lets users issue and revoke their personal API tokens.
+type APITokenHandler struct {
+	tokens   *db.APITokenStore
+	basePath string
+}
+
+func NewAPITokenHandler(tokens *db.APITokenStore, basePath string) *APITokenHandler {
+	return &APITokenHandler{tokens: tokens, basePath: basePath}
+}
+
+// HandleList renders the current user's API tokens.
+func (h *APITokenHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	h.render(w, r, "", "")
+}
+
+// HandleCreate issues a token and shows it once.
+func (h *APITokenHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<10)
+	if err := r.ParseForm(); err != nil {
+		httpError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		h.render(w, r, "", "Name is required.")
+		return
+	}
+	token, _, err := h.tokens.Create(user.ID, name)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to create token")
+		return
+	}
+	h.render(w, r, token, "")
+}
+
+// HandleDelete revokes one of the current user's tokens.
+func (h *APITokenHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	user := userFromContext(r.Context())
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+	if err := h.tokens.Delete(id, user.ID); err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to revoke token")
+		return
+	}
+	redirectTo(w, r, h.basePath+"/tokens")
+}
+
+func (h *APITokenHandler) render(w http.ResponseWriter, r *http.Request, newToken, formErr string) {
+	user := userFromContext(r.Context())
+	tokens, err := h.tokens.ListByUser(user.ID)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "failed to load tokens")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, apiTokensHTML(h.basePath, tokens, newToken, formErr)) //nolint:errcheck,gosec
+}
+
+func apiTokensHTML(basePath string, tokens []*db.APIToken, newToken, formErr string) string {
+	notice := ""
+	if newToken != "" {
+		notice = fmt.Sprintf(`
+    <article style="border:1px solid #166534">
+      <strong>New token</strong> — copy it now, it will not be shown again.
+      <pre style="margin:0.5rem 0 0;user-select:all"><code>%s</code></pre>
+    </article>`, html.EscapeString(newToken))
+	}
+	if formErr != "" {
+		notice = fmt.Sprintf(`<p style="color:#f87171">%s</p>`, html.EscapeString(formErr))
+	}
+
+	rows := `<tr><td colspan="4" style="color:var(--pico-muted-color);text-align:center"><em>No tokens yet.</em></td></tr>`
+	if len(tokens) > 0 {
+		var b strings.Builder
+		for _, t := range tokens {
+			lastUsed := "never"
+			if t.LastUsedAt != nil {
+				lastUsed = t.LastUsedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")
+			}
+			fmt.Fprintf(&b, `<tr><td>%s</td><td>%s</td><td>%s</td><td><form method="POST" action="%s/tokens/%d/delete" style="margin:0" onsubmit="return confirm('Revoke this token? Anything using it will stop working.')"><button type="submit" class="outline secondary" style="margin:0;padding:0.2rem 0.7rem;font-size:0.8rem">Revoke</button></form></td></tr>`,
+				html.EscapeString(t.Name),
+				html.EscapeString(t.CreatedAt.In(jstDisplay).Format("2006-01-02 15:04 JST")),
+				html.EscapeString(lastUsed),
+				basePath, t.ID)
+		}
+		rows = b.String()
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en" data-theme="dark">
+<head>
+  <meta charset="UTF-8">
+  <link rel="icon" href="data:image/svg+xml,<svg xmlns='http://www.w3.org/2000/svg' viewBox='0 0 100 100'><text y='.9em' font-size='90'>🧊</text></svg>">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>API Tokens &#8211; TGI Freeze Day</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/@picocss/pico@2/css/pico.min.css">
+  <style>
+    nav.topnav { background: var(--pico-card-background-color); border-bottom: 1px solid var(--pico-card-border-color); padding: 0.75rem 1.5rem; display:flex; align-items:center; justify-content:space-between; }
+    nav.topnav .brand { font-weight:700; font-size:1rem; text-decoration:none; color:inherit; }
+    .page-content { max-width: 900px; margin: 2rem auto; padding: 0 1.5rem; }
+    .breadcrumb { font-size:0.82rem; color:var(--pico-muted-color); margin-bottom:0.4rem; }
+    .breadcrumb a { color:var(--pico-muted-color); text-decoration:none; }
+  </style>
+</head>
+<body>
+  <nav class="topnav">
+    <a href="`+basePath+`/dashboard" class="brand">🙏🧔🏽‍♀️👉🧊🗓️ TGI Freeze Day</a>
+    <div>%s</div>
+  </nav>
+  <div class="page-content">
+    <div class="breadcrumb"><a href="`+basePath+`/dashboard">Configs</a> &rsaquo; API Tokens</div>
+    <h2>API Tokens</h2>
+    <p style="font-size:0.9rem;color:var(--pico-muted-color)">
+      Tokens let scripts and pipelines call the <a href="`+basePath+`/api/v1/openapi.yaml">JSON API</a> as you, with your permissions.
+      Send one as <code>Authorization: Bearer &lt;token&gt;</code>.
+    </p>
+    %s
+    <form method="POST" action="`+basePath+`/tokens" style="display:flex;gap:0.5rem;align-items:center">
+      <input type="text" name="name" placeholder="Token name, e.g. deploy pipeline" required maxlength="100" style="margin:0">
+      <button type="submit" style="margin:0;width:auto;white-space:nowrap">Create token</button>
+    </form>
+    <table class="striped" style="font-size:0.88rem;margin-top:1rem">
+      <thead><tr><th>Name</th><th>Created</th><th>Last used</th><th></th></tr></thead>
+      <tbody>%s</tbody>
+    </table>
+  </div>
+  `+pageFooterHTML()+`
+</body>
+</html>`, logoutForm(basePath), notice, rows)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// diffRow is one row of a side-by-side diff. A line number of 0 means the side is blank.
+type diffRow struct {
+	OldNo, NewNo int
+	Old, New     string
+	Changed      bool
+}
+
+// diffLines compares old and new line by line, using the longest common subsequence, and
+// pairs up the removed and added lines between two unchanged ones.
+func diffLines(old, new string) []diffRow {
+	a, b := splitYAMLLines(old), splitYAMLLines(new)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var rows []diffRow
+	var removed, added []int // Pending changed line indexes into a and b.
+	flush := func() {
+		for k := range max(len(removed), len(added)) {
+			row := diffRow{Changed: true}
+			if k < len(removed) {
+				row.OldNo, row.Old = removed[k]+1, a[removed[k]]
+			}
+			if k < len(added) {
+				row.NewNo, row.New = added[k]+1, b[added[k]]
+			}
+			rows = append(rows, row)
+		}
+		removed, added = removed[:0], added[:0]
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			rows = append(rows, diffRow{OldNo: i + 1, NewNo: j + 1, Old: a[i], New: b[j]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, i)
+			i++
+		default:
+			added = append(added, j)
+			j++
+		}
+	}
+	flush()
+	return rows
+}
+
+func splitYAMLLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// yamlDiffHTML renders old and new side by side, highlighting the changed lines.
+func yamlDiffHTML(old, new string) string {
+	cell := func(no int, line, bg string) string {
+		if no == 0 {
+			return `<td style="background:var(--pico-muted-border-color)"></td><td style="background:var(--pico-muted-border-color)"></td>`
+		}
+		return fmt.Sprintf(`<td style="color:var(--pico-muted-color);text-align:right;user-select:none;%s">%d</td><td style="white-space:pre;%s">%s</td>`,
+			bg, no, bg, html.EscapeString(line))
+	}
+	var rows strings.Builder
+	for _, row := range diffLines(old, new) {
+		oldBg, newBg := "", ""
+		if row.Changed {
+			oldBg, newBg = "background:#4a1122;", "background:#1a4731;"
+		}
+		fmt.Fprintf(&rows, `<tr>%s%s</tr>`, cell(row.OldNo, row.Old, oldBg), cell(row.NewNo, row.New, newBg))
+	}
+	return fmt.Sprintf(`
+<div style="overflow-x:auto">
+  <table style="font-family:var(--pico-font-family-monospace);font-size:0.75rem;table-layout:fixed;width:100%%">
+    <colgroup><col style="width:3rem"><col><col style="width:3rem"><col></colgroup>
+    <thead><tr><th colspan="2">Before</th><th colspan="2">After</th></tr></thead>
+    <tbody>%s</tbody>
+  </table>
+</div>`, rows.String())
+}